@@ -0,0 +1,77 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/guarref/pr-service-assignment/config"
+	"github.com/guarref/pr-service-assignment/internal/errs"
+	"github.com/guarref/pr-service-assignment/internal/models"
+	"github.com/guarref/pr-service-assignment/internal/repository/postgres"
+	"github.com/guarref/pr-service-assignment/internal/service"
+	pg "github.com/guarref/pr-service-assignment/pkg/postgres"
+)
+
+// импорт команд напрямую в БД: app import -file teams.csv [-format csv|yaml] [-dry-run]
+func runImport(ctx context.Context, cfg *config.Config, args []string) error {
+
+	fs := flag.NewFlagSet("import", flag.ExitOnError)
+	path := fs.String("file", "", "CSV или YAML файл (team, user_id, username, active)")
+	format := fs.String("format", "", "формат файла (по умолчанию по расширению)")
+	dryRun := fs.Bool("dry-run", false, "только показать изменения")
+	_ = fs.Parse(args)
+
+	if *path == "" {
+		fs.Usage()
+		return errors.New("-file is required")
+	}
+
+	importFormat := models.ImportFormat(*format)
+	if importFormat == "" {
+		switch strings.ToLower(filepath.Ext(*path)) {
+		case ".csv":
+			importFormat = models.ImportFormatCSV
+		case ".yaml", ".yml":
+			importFormat = models.ImportFormatYAML
+		default:
+			return fmt.Errorf("cannot detect format of %s, use -format", *path)
+		}
+	}
+
+	file, err := os.Open(*path)
+	if err != nil {
+		return fmt.Errorf("error opening file: %w", err)
+	}
+	defer file.Close()
+
+	db, err := pg.NewPDB(cfg.DSN())
+	if err != nil {
+		return fmt.Errorf("error db connect: %w", err)
+	}
+	defer db.Close()
+
+	// у CLI нет /metrics, снятые без замены ревьюверы здесь не учитываются
+	importSvc := service.NewImportService(postgres.NewImportRepository(db.DB), postgres.NewTeamRepository(db.DB), nil)
+
+	diff, err := importSvc.ImportTeamsAsOperator(ctx, importFormat, file, *dryRun)
+	if err != nil {
+		var validationErr *errs.ValidationError
+		if errors.As(err, &validationErr) {
+			for _, issue := range validationErr.Issues {
+				fmt.Fprintf(os.Stderr, "%s:%d: %s\n", *path, issue.Line, issue.Message)
+			}
+		}
+		return err
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+
+	return enc.Encode(diff)
+}
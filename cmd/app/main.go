@@ -3,6 +3,7 @@ package main
 import (
 	"context"
 	"log"
+	"os"
 	"os/signal"
 	"syscall"
 
@@ -10,6 +11,12 @@ import (
 	"github.com/guarref/pr-service-assignment/internal/app"
 )
 
+// подкоманды CLI; без подкоманды запускается сервер
+var commands = map[string]func(ctx context.Context, cfg *config.Config, args []string) error{
+	"token":  runToken,
+	"import": runImport,
+}
+
 func main() {
 
 	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
@@ -17,6 +24,15 @@ func main() {
 
 	cfg := config.MustLoad()
 
+	if len(os.Args) > 1 {
+		if command, ok := commands[os.Args[1]]; ok {
+			if err := command(ctx, cfg, os.Args[2:]); err != nil {
+				log.Fatalf("%s: %v", os.Args[1], err)
+			}
+			return
+		}
+	}
+
 	application, err := app.New(ctx, cfg)
 	if err != nil {
 		log.Fatalf("error creating new application: %v", err)
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"time"
+
+	"github.com/guarref/pr-service-assignment/config"
+	"github.com/guarref/pr-service-assignment/internal/auth"
+)
+
+// выпуск токена: app token -user u1 [-ttl 24h]
+func runToken(_ context.Context, cfg *config.Config, args []string) error {
+
+	fs := flag.NewFlagSet("token", flag.ExitOnError)
+	userID := fs.String("user", "", "user_id вызывающего")
+	ttl := fs.Duration("ttl", 24*time.Hour, "время жизни токена")
+	_ = fs.Parse(args)
+
+	if cfg.AuthSecret == "" {
+		return errors.New("AUTH_SECRET is not set")
+	}
+	if *userID == "" {
+		fs.Usage()
+		return errors.New("-user is required")
+	}
+
+	token, err := auth.NewSigner(cfg.AuthSecret).Issue(*userID, *ttl)
+	if err != nil {
+		return fmt.Errorf("error issuing token: %w", err)
+	}
+
+	fmt.Println(token)
+
+	return nil
+}
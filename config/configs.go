@@ -3,6 +3,7 @@ package config
 import (
 	"fmt"
 	"log"
+	"time"
 
 	env "github.com/caarlos0/env/v6"
 )
@@ -18,6 +19,17 @@ type Config struct {
 
 	MigrateEnable bool   `env:"MIGRATE_ENABLE"`
 	MigrateFolder string `env:"MIGRATE_FOLDER"`
+
+	AuthSecret string `env:"AUTH_SECRET"`
+
+	// ключ HMAC для поиска удалённых пользователей по исходному id; если не задан, используется AUTH_SECRET
+	PseudonymSecret string `env:"PSEUDONYM_SECRET"`
+
+	// пустой токен отключает SCIM
+	ScimToken string `env:"SCIM_TOKEN"`
+
+	// как часто пересчитывается сводная статистика для GET /stats без фильтров
+	StatsRefreshInterval time.Duration `env:"STATS_REFRESH_INTERVAL" envDefault:"30s"`
 }
 
 func Load() (*Config, error) {
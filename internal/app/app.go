@@ -12,19 +12,30 @@ import (
 	"github.com/guarref/pr-service-assignment/config"
 	pg "github.com/guarref/pr-service-assignment/pkg/postgres"
 
+	"github.com/guarref/pr-service-assignment/internal/auth"
+	"github.com/guarref/pr-service-assignment/internal/metrics"
 	"github.com/guarref/pr-service-assignment/internal/repository/postgres"
 	"github.com/guarref/pr-service-assignment/internal/service"
 	"github.com/guarref/pr-service-assignment/internal/web"
 )
 
 type App struct {
-	cfg  *config.Config
-	db   *pg.PDB
-	echo *echo.Echo
+	cfg      *config.Config
+	db       *pg.PDB
+	echo     *echo.Echo
+	statsSvc *service.StatsService
 }
 
 func New(_ context.Context, cfg *config.Config) (*App, error) {
 
+	if cfg.AuthSecret == "" {
+		return nil, fmt.Errorf("AUTH_SECRET is not set")
+	}
+	// без фонового пересчёта сводная статистика осталась бы устаревшей навсегда
+	if cfg.StatsRefreshInterval <= 0 {
+		return nil, fmt.Errorf("STATS_REFRESH_INTERVAL must be positive, got %s", cfg.StatsRefreshInterval)
+	}
+
 	db, err := pg.NewPDB(cfg.DSN())
 	if err != nil {
 		return nil, fmt.Errorf("error db connect: %w", err)
@@ -40,28 +51,46 @@ func New(_ context.Context, cfg *config.Config) (*App, error) {
 	userRepo := postgres.NewUserRepository(db.DB)
 	prRepo := postgres.NewPullRequestRepository(db.DB, userRepo)
 	statsRepo := postgres.NewStatsRepository(db.DB)
+	importRepo := postgres.NewImportRepository(db.DB)
+	scimRepo := postgres.NewScimRepository(db.DB)
+
+	appMetrics := metrics.New(statsRepo, db.DB.DB)
 
-	teamSvc := service.NewTeamService(teamRepo)
-	userSvc := service.NewUserService(userRepo)
-	prSvc := service.NewPullRequestService(prRepo, userRepo)
+	teamSvc := service.NewTeamService(teamRepo, userRepo, appMetrics)
+	pseudonymSecret := cfg.PseudonymSecret
+	if pseudonymSecret == "" {
+		pseudonymSecret = cfg.AuthSecret
+	}
+
+	userSvc := service.NewUserService(userRepo, teamRepo, pseudonymSecret, appMetrics)
+	prSvc := service.NewPullRequestService(prRepo, userRepo, teamRepo)
 	statsSvc := service.NewStatsService(statsRepo)
+	importSvc := service.NewImportService(importRepo, teamRepo, appMetrics)
+	scimSvc := service.NewScimService(scimRepo, teamRepo, appMetrics)
 
 	e := echo.New()
 	e.HideBanner = true
 	e.HidePort = true
 
 	e.Use(middleware.Recover())
-	e.Use(web.AccessLogMiddleware)
+	e.Use(web.MetricsMiddleware(appMetrics))
+	e.Use(web.AuthMiddleware(auth.NewSigner(cfg.AuthSecret)))
 
-	web.RegisterRoutes(e, teamSvc, userSvc, prSvc, statsSvc)
+	web.RegisterRoutes(e, teamSvc, userSvc, prSvc, statsSvc, importSvc)
+	web.RegisterMetricsRoute(e, appMetrics)
+	if cfg.ScimToken != "" {
+		web.RegisterScimRoutes(e, scimSvc, cfg.ScimToken)
+	}
 
-	return &App{cfg: cfg, db: db, echo: e}, nil
+	return &App{cfg: cfg, db: db, echo: e, statsSvc: statsSvc}, nil
 }
 
 func (a *App) Run(ctx context.Context) error {
 
 	serverErr := make(chan error, 1)
 
+	go a.statsSvc.RunSummaryRefresh(ctx, a.cfg.StatsRefreshInterval)
+
 	go func() {
 		addr := fmt.Sprintf(":%d", a.cfg.Port)
 		fmt.Printf("SERVER is starting on port%s\n", addr)
@@ -0,0 +1,96 @@
+package auth
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+)
+
+var (
+	ErrInvalidToken = errors.New("invalid token")
+	ErrExpiredToken = errors.New("token expired")
+)
+
+// токен: base64url(claims).base64url(hmac-sha256(claims))
+type claims struct {
+	Subject   string `json:"sub"`
+	ExpiresAt int64  `json:"exp"`
+}
+
+type Signer struct {
+	secret []byte
+	now    func() time.Time
+}
+
+func NewSigner(secret string) *Signer {
+	return &Signer{secret: []byte(secret), now: time.Now}
+}
+
+func (s *Signer) Issue(userID string, ttl time.Duration) (string, error) {
+
+	if userID == "" {
+		return "", ErrInvalidToken
+	}
+
+	payload, err := json.Marshal(claims{Subject: userID, ExpiresAt: s.now().Add(ttl).Unix()})
+	if err != nil {
+		return "", err
+	}
+
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+
+	return encoded + "." + base64.RawURLEncoding.EncodeToString(s.sign(encoded)), nil
+}
+
+// возвращает user_id вызывающего
+func (s *Signer) Verify(token string) (string, error) {
+
+	encoded, sig, ok := strings.Cut(token, ".")
+	if !ok {
+		return "", ErrInvalidToken
+	}
+
+	rawSig, err := base64.RawURLEncoding.DecodeString(sig)
+	if err != nil || !hmac.Equal(rawSig, s.sign(encoded)) {
+		return "", ErrInvalidToken
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", ErrInvalidToken
+	}
+
+	var c claims
+	if err := json.Unmarshal(payload, &c); err != nil || c.Subject == "" {
+		return "", ErrInvalidToken
+	}
+	if s.now().Unix() >= c.ExpiresAt {
+		return "", ErrExpiredToken
+	}
+
+	return c.Subject, nil
+}
+
+func (s *Signer) sign(encoded string) []byte {
+
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(encoded))
+
+	return mac.Sum(nil)
+}
+
+type callerKey struct{}
+
+func WithCaller(ctx context.Context, userID string) context.Context {
+	return context.WithValue(ctx, callerKey{}, userID)
+}
+
+func CallerFromContext(ctx context.Context) (string, bool) {
+	userID, ok := ctx.Value(callerKey{}).(string)
+	return userID, ok && userID != ""
+}
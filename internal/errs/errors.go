@@ -55,6 +55,38 @@ var (
 		StatusCode: http.StatusConflict,
 	}
 
+	ErrUserExists = &RespError{
+		Code:       "USER_EXISTS",
+		Message:    "user_id already exists",
+		StatusCode: http.StatusConflict,
+	}
+
+	ErrTeamArchived = &RespError{
+		Code:       "TEAM_ARCHIVED",
+		Message:    "team is archived",
+		StatusCode: http.StatusConflict,
+	}
+
+	ErrTeamHasHistory = &RespError{
+		Code:       "TEAM_HAS_HISTORY",
+		Message:    "team has pull request history, use force to delete it with export",
+		StatusCode: http.StatusConflict,
+	}
+
+	// 401
+	ErrUnauthorized = &RespError{
+		Code:       "UNAUTHORIZED",
+		Message:    "missing or invalid bearer token",
+		StatusCode: http.StatusUnauthorized,
+	}
+
+	// 403
+	ErrForbidden = &RespError{
+		Code:       "FORBIDDEN",
+		Message:    "caller has no required role in team",
+		StatusCode: http.StatusForbidden,
+	}
+
 	// 404
 	ErrNotFound = &RespError{
 		Code:       "NOT_FOUND",
@@ -68,6 +100,12 @@ var (
 		StatusCode: http.StatusNotFound,
 	}
 
+	ErrParentTeamNotFound = &RespError{
+		Code:       "NOT_FOUND",
+		Message:    "parent team not found",
+		StatusCode: http.StatusNotFound,
+	}
+
 	ErrUserNotFound = &RespError{
 		Code:       "NOT_FOUND",
 		Message:    "user not found",
@@ -92,4 +130,51 @@ var (
 		Message:    "invalid JSON format",
 		StatusCode: http.StatusBadRequest,
 	}
+
+	ErrInvalidCursor = &RespError{
+		Code:       "BAD_REQUEST",
+		Message:    "invalid pagination cursor",
+		StatusCode: http.StatusBadRequest,
+	}
+
+	ErrInvalidFilter = &RespError{
+		Code:       "BAD_REQUEST",
+		Message:    "unsupported or invalid filter",
+		StatusCode: http.StatusBadRequest,
+	}
+
+	ErrImmutableAttribute = &RespError{
+		Code:       "BAD_REQUEST",
+		Message:    "attribute cannot be modified",
+		StatusCode: http.StatusBadRequest,
+	}
+
+	ErrInvalidImport = &RespError{
+		Code:       "BAD_REQUEST",
+		Message:    "import file is invalid",
+		StatusCode: http.StatusBadRequest,
+	}
 )
+
+type Issue struct {
+	Line    int
+	Message string
+}
+
+// ошибка валидации входного файла со списком проблем по строкам
+type ValidationError struct {
+	Err    *RespError
+	Issues []Issue
+}
+
+func NewValidationError(err *RespError, issues []Issue) *ValidationError {
+	return &ValidationError{Err: err, Issues: issues}
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("%s (%d issues)", e.Err.Error(), len(e.Issues))
+}
+
+func (e *ValidationError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,143 @@
+package metrics
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/guarref/pr-service-assignment/internal/errs"
+	"github.com/guarref/pr-service-assignment/internal/models"
+	"github.com/guarref/pr-service-assignment/internal/repository"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "pr_service"
+
+// доменные счётчики читаются из материализованных представлений при каждом опросе, запрос не должен задерживать scrape
+const domainScrapeTimeout = 5 * time.Second
+
+// причины снятия ревьювера; экспортируются всегда, даже с нулём, чтобы ряды существовали с первого опроса
+var reassignmentReasons = []models.PullRequestEventReason{
+	models.EventReasonManualReassign,
+	models.EventReasonDeactivation,
+	models.EventReasonErasure,
+	models.EventReasonTeamDeletion,
+}
+
+type Metrics struct {
+	registry *prometheus.Registry
+	requests *prometheus.CounterVec
+	duration *prometheus.HistogramVec
+	errors   *prometheus.CounterVec
+}
+
+// db — пул соединений для статистики go_sql_*; nil отключает её
+func New(statsRepo repository.StatsRepository, db *sql.DB) *Metrics {
+
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "http_requests_total",
+			Help:      "HTTP requests by route, method and status code.",
+		}, []string{"method", "route", "code"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "http_request_duration_seconds",
+			Help:      "HTTP request latency by route and method.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "route"}),
+		errors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "errors_total",
+			Help:      "Error responses by error code (NO_CANDIDATE, NOT_FOUND, ...); NO_CANDIDATE also counts reviewers removed without a replacement.",
+		}, []string{"code"}),
+	}
+
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.requests,
+		m.duration,
+		m.errors,
+		newDomainCollector(statsRepo),
+	)
+
+	if db != nil {
+		m.registry.MustRegister(collectors.NewDBStatsCollector(db, "postgres"))
+	}
+
+	return m
+}
+
+// при недоступной БД доменные метрики пропускаются, остальные отдаются как обычно
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{
+		ErrorLog:      log.Default(),
+		ErrorHandling: promhttp.ContinueOnError,
+	})
+}
+
+// route — шаблон маршрута (/team/get), а не фактический путь, чтобы число рядов было ограничено
+func (m *Metrics) ObserveRequest(method, route string, status int, duration time.Duration) {
+	m.requests.WithLabelValues(method, route, strconv.Itoa(status)).Inc()
+	m.duration.WithLabelValues(method, route).Observe(duration.Seconds())
+}
+
+func (m *Metrics) IncError(code string) {
+	m.errors.WithLabelValues(code).Inc()
+}
+
+// ревьюверы, снятые при деактивации, архивации, импорте или SCIM без замены, считаются как ошибки NO_CANDIDATE
+func (m *Metrics) AddDroppedReviewers(n int) {
+	m.errors.WithLabelValues(errs.ErrNoCandidate.Code).Add(float64(n))
+}
+
+type domainCollector struct {
+	statsRepo     repository.StatsRepository
+	openPRs       *prometheus.Desc
+	assignments   *prometheus.Desc
+	reassignments *prometheus.Desc
+}
+
+func newDomainCollector(statsRepo repository.StatsRepository) *domainCollector {
+	return &domainCollector{
+		statsRepo: statsRepo,
+		openPRs: prometheus.NewDesc(prometheus.BuildFQName(namespace, "", "open_pull_requests"),
+			"Pull requests in OPEN status.", nil, nil),
+		assignments: prometheus.NewDesc(prometheus.BuildFQName(namespace, "", "review_assignments_total"),
+			"Reviewer assignments made, including later reassigned ones.", nil, nil),
+		reassignments: prometheus.NewDesc(prometheus.BuildFQName(namespace, "", "reassignments_total"),
+			"Reviewers removed from pull requests by reason.", []string{"reason"}, nil),
+	}
+}
+
+func (dc *domainCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- dc.openPRs
+	ch <- dc.assignments
+	ch <- dc.reassignments
+}
+
+func (dc *domainCollector) Collect(ch chan<- prometheus.Metric) {
+
+	ctx, cancel := context.WithTimeout(context.Background(), domainScrapeTimeout)
+	defer cancel()
+
+	counters, err := dc.statsRepo.GetDomainCounters(ctx)
+	if err != nil {
+		ch <- prometheus.NewInvalidMetric(dc.openPRs, err)
+		return
+	}
+
+	ch <- prometheus.MustNewConstMetric(dc.openPRs, prometheus.GaugeValue, float64(counters.OpenPullRequests))
+	ch <- prometheus.MustNewConstMetric(dc.assignments, prometheus.CounterValue, float64(counters.Assignments))
+	for _, reason := range reassignmentReasons {
+		ch <- prometheus.MustNewConstMetric(dc.reassignments, prometheus.CounterValue,
+			float64(counters.Reassignments[reason]), string(reason))
+	}
+}
@@ -0,0 +1,46 @@
+package models
+
+type ImportFormat string
+
+const (
+	ImportFormatCSV  ImportFormat = "csv"
+	ImportFormatYAML ImportFormat = "yaml"
+)
+
+// строка импорта: участник команды; Line — номер строки во входном файле
+type ImportRow struct {
+	Line     int
+	TeamName string
+	UserID   string
+	UserName string
+	IsActive bool
+}
+
+type ImportUserUpdate struct {
+	UserID      string `json:"user_id"`
+	OldUserName string `json:"old_username"`
+	NewUserName string `json:"new_username"`
+	OldIsActive bool   `json:"old_is_active"`
+	NewIsActive bool   `json:"new_is_active"`
+}
+
+type ImportMembership struct {
+	TeamName string `json:"team_name" db:"team_name"`
+	UserID   string `json:"user_id" db:"user_id"`
+}
+
+// существующая команда, которую затрагивает импорт: из строк файла или команда пользователя, чьи данные меняются
+type ImportAffectedTeam struct {
+	TeamName string `db:"team_name"`
+	IsActive bool   `db:"is_active"`
+}
+
+// разница между файлом импорта и текущим состоянием
+type ImportDiff struct {
+	DryRun           bool               `json:"dry_run"`
+	TeamsCreated     []string           `json:"teams_created"`
+	UsersCreated     []string           `json:"users_created"`
+	UsersUpdated     []ImportUserUpdate `json:"users_updated"`
+	MembershipsAdded []ImportMembership `json:"memberships_added"`
+	Unchanged        int                `json:"unchanged"`
+}
@@ -0,0 +1,7 @@
+package models
+
+// позиция keyset-пагинации: значение поля сортировки и идентификатор последней записи страницы
+type PageCursor struct {
+	Key string
+	ID  string
+}
@@ -22,9 +22,139 @@ type PullRequest struct {
 	MergedAt  *time.Time `json:"mergedAt,omitempty" db:"merged_at"`
 }
 
+// отдельного одобрения ревью в модели нет: состояние выводится из статуса PR
+type ReviewState string
+
+const (
+	ReviewStatePending   ReviewState = "PENDING"
+	ReviewStateCompleted ReviewState = "COMPLETED"
+)
+
+// ревьювер PR с данными пользователя; TeamName — основная команда,
+// HeldSeconds — сколько ревьювер держит назначение (для смерженных PR — до merge)
+type PullRequestReviewer struct {
+	PullRequestID string      `json:"-" db:"pull_request_id"`
+	UserID        string      `json:"user_id" db:"user_id"`
+	UserName      string      `json:"username" db:"username"`
+	TeamName      string      `json:"team_name" db:"team_name"`
+	IsActive      bool        `json:"is_active" db:"is_active"`
+	AssignedAt    time.Time   `json:"assigned_at" db:"assigned_at"`
+	HeldSeconds   int64       `json:"held_seconds" db:"held_seconds"`
+	State         ReviewState `json:"review_state" db:"-"`
+}
+
+type PullRequestDetails struct {
+	PullRequest
+	Reviewers []PullRequestReviewer `json:"reviewers"`
+}
+
+// PR в очереди ревьювера; AgeSeconds — возраст PR (для смерженных — до merge)
 type PullRequestShort struct {
 	PullRequestID   string            `json:"pull_request_id" db:"pull_request_id"`
 	PullRequestName string            `json:"pull_request_name" db:"pull_request_name"`
 	AuthorID        string            `json:"author_id" db:"author_id"`
 	Status          PullRequestStatus `json:"status" db:"status"`
+
+	CreatedAt  time.Time `json:"createdAt" db:"created_at"`
+	AssignedAt time.Time `json:"assigned_at" db:"assigned_at"`
+	AgeSeconds int64     `json:"age_seconds" db:"age_seconds"`
+}
+
+type ReviewAssignment struct {
+	PullRequestID string    `json:"pull_request_id" db:"pull_request_id"`
+	UserID        string    `json:"user_id" db:"user_id"`
+	AssignedAt    time.Time `json:"assigned_at" db:"assigned_at"`
+}
+
+type PullRequestSort string
+
+const (
+	PullRequestSortCreatedAt       PullRequestSort = "created_at"
+	PullRequestSortMergedAt        PullRequestSort = "merged_at"
+	PullRequestSortPullRequestID   PullRequestSort = "pull_request_id"
+	PullRequestSortPullRequestName PullRequestSort = "pull_request_name"
+)
+
+// диапазоны дат полуоткрытые: From включительно, To не включительно
+type PullRequestListFilter struct {
+	Status     PullRequestStatus
+	AuthorID   string
+	ReviewerID string
+	TeamName   string
+
+	CreatedFrom *time.Time
+	CreatedTo   *time.Time
+	MergedFrom  *time.Time
+	MergedTo    *time.Time
+
+	Sort  PullRequestSort
+	Desc  bool
+	Limit int
+	After *PageCursor
+}
+
+// oldest/newest — по дате создания PR; priority — сначала открытые, затем по времени назначения (дольше ждущие первыми)
+type ReviewQueueSort string
+
+const (
+	ReviewQueueSortOldest   ReviewQueueSort = "oldest"
+	ReviewQueueSortNewest   ReviewQueueSort = "newest"
+	ReviewQueueSortPriority ReviewQueueSort = "priority"
+)
+
+// PR автора, от новых к старым
+type AuthoredPullRequestsFilter struct {
+	AuthorID string
+	Status   PullRequestStatus
+
+	Limit int
+	After *PageCursor
+}
+
+type ReviewQueueFilter struct {
+	ReviewerID string
+	Status     PullRequestStatus
+
+	Sort  ReviewQueueSort
+	Limit int
+	After *ReviewQueueCursor
+}
+
+// разобранный курсор очереди ревью: Time — created_at для oldest/newest, assigned_at для priority;
+// StatusRank (0 — OPEN, 1 — MERGED) используется только для priority
+type ReviewQueueCursor struct {
+	StatusRank int
+	Time       time.Time
+	ID         string
+}
+
+type PullRequestEventType string
+
+const (
+	PullRequestEventCreated          PullRequestEventType = "created"
+	PullRequestEventReviewerAssigned PullRequestEventType = "reviewer_assigned"
+	PullRequestEventReviewerRemoved  PullRequestEventType = "reviewer_removed"
+	PullRequestEventMerged           PullRequestEventType = "merged"
+)
+
+// причина назначения или снятия ревьювера
+type PullRequestEventReason string
+
+const (
+	EventReasonCreation       PullRequestEventReason = "creation"
+	EventReasonManualReassign PullRequestEventReason = "manual_reassign"
+	EventReasonDeactivation   PullRequestEventReason = "deactivation"
+	EventReasonErasure        PullRequestEventReason = "erasure"
+	EventReasonTeamDeletion   PullRequestEventReason = "team_deletion"
+)
+
+// запись журнала pr_events; UserID — автор для created и ревьювер для событий назначения,
+// у событий, перенесённых миграцией из существующих данных, причины нет
+type PullRequestEvent struct {
+	EventID       int64                   `json:"event_id" db:"event_id"`
+	PullRequestID string                  `json:"pull_request_id" db:"pull_request_id"`
+	Type          PullRequestEventType    `json:"type" db:"event_type"`
+	UserID        *string                 `json:"user_id,omitempty" db:"user_id"`
+	Reason        *PullRequestEventReason `json:"reason,omitempty" db:"reason"`
+	CreatedAt     time.Time               `json:"created_at" db:"created_at"`
 }
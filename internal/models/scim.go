@@ -0,0 +1,33 @@
+package models
+
+// фильтры SCIM: поддерживаются только сравнения eq, объединённые через and
+type ScimUserFilter struct {
+	UserID   string
+	UserName string
+	IsActive *bool
+
+	Offset int
+	Limit  int
+}
+
+type ScimGroupFilter struct {
+	TeamName string
+	MemberID string
+
+	Offset int
+	Limit  int
+}
+
+// операция PATCH (RFC 7644, 3.5.2); Value — произвольное JSON-значение
+type ScimPatchOperation struct {
+	Op    string `json:"op"`
+	Path  string `json:"path,omitempty"`
+	Value any    `json:"value,omitempty"`
+}
+
+// изменения состава группы; при Replace участники, которых нет в Add, удаляются
+type ScimMembersUpdate struct {
+	Add     []string
+	Remove  []string
+	Replace bool
+}
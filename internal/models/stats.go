@@ -1,5 +1,13 @@
 package models
 
+import "time"
+
+// полуоткрытый период [From, To); пустая граница не ограничивает период
+type StatsWindow struct {
+	From *time.Time
+	To   *time.Time
+}
+
 type TopReviewer struct {
 	UserID      string `json:"user_id"      db:"user_id"`
 	UserName    string `json:"username"     db:"username"`
@@ -7,10 +15,189 @@ type TopReviewer struct {
 }
 
 type Stats struct {
-	TotalTeams        int            `json:"total_teams"         db:"total_teams"`
-	TotalUsers        int            `json:"total_users"         db:"total_users"`
-	ActiveUsers       int            `json:"active_users"        db:"active_users"`
-	TotalPullRequests int            `json:"total_pull_requests" db:"total_pull_requests"`
-	OpenPullRequests  int            `json:"open_pull_requests"  db:"open_pull_requests"`
-	TopReviewers      []*TopReviewer `json:"top_reviewers"       db:"-"`
+	TotalTeams         int            `json:"total_teams"         db:"total_teams"`
+	TotalUsers         int            `json:"total_users"         db:"total_users"`
+	ActiveUsers        int            `json:"active_users"        db:"active_users"`
+	TotalPullRequests  int            `json:"total_pull_requests" db:"total_pull_requests"`
+	OpenPullRequests   int            `json:"open_pull_requests"  db:"open_pull_requests"`
+	MergedPullRequests int            `json:"merged_pull_requests" db:"merged_pull_requests"`
+	TopReviewers       []*TopReviewer `json:"top_reviewers"       db:"-"`
+
+	// момент, на который посчитаны данные (для сводной статистики — время обновления в БД)
+	AsOf time.Time `json:"as_of" db:"as_of"`
+}
+
+type TeamMemberStats struct {
+	UserID       string `json:"user_id"       db:"user_id"`
+	UserName     string `json:"username"      db:"username"`
+	IsActive     bool   `json:"is_active"     db:"is_active"`
+	OpenReviews  int    `json:"open_reviews"  db:"open_reviews"`
+	TotalReviews int    `json:"total_reviews" db:"total_reviews"`
+}
+
+// статистика одной команды (без дочерних): PR считаются по авторам-участникам команды
+type TeamStats struct {
+	TeamName                 string             `json:"team_name"                  db:"-"`
+	OpenPullRequests         int                `json:"open_pull_requests"         db:"open_pull_requests"`
+	MergedPullRequests       int                `json:"merged_pull_requests"       db:"merged_pull_requests"`
+	TotalMembers             int                `json:"total_members"              db:"total_members"`
+	ActiveMembers            int                `json:"active_members"             db:"active_members"`
+	AvgReviewersPerPR        float64            `json:"avg_reviewers_per_pr"       db:"avg_reviewers_per_pr"`
+	RequiredReviewers        int                `json:"required_reviewers"         db:"-"`
+	UnderstaffedPullRequests int                `json:"understaffed_pull_requests" db:"understaffed_pull_requests"`
+	Members                  []*TeamMemberStats `json:"members"                    db:"-"`
+}
+
+// перцентили длительности в секундах; Count — число наблюдений
+type LatencyPercentiles struct {
+	Count int     `json:"count" db:"count"`
+	P50   float64 `json:"p50"   db:"p50"`
+	P90   float64 `json:"p90"   db:"p90"`
+	P99   float64 `json:"p99"   db:"p99"`
+}
+
+type TeamLatency struct {
+	TeamName        string             `json:"team_name"`
+	TimeToMerge     LatencyPercentiles `json:"time_to_merge"`
+	AssignedToMerge LatencyPercentiles `json:"assigned_to_merge"`
+}
+
+type ReviewerLatency struct {
+	UserID          string             `json:"user_id"`
+	UserName        string             `json:"username"`
+	AssignedToMerge LatencyPercentiles `json:"assigned_to_merge"`
+}
+
+// TimeToMerge — от создания PR до merge, AssignedToMerge — от назначения ревьювера до merge
+type LatencyStats struct {
+	TimeToMerge     LatencyPercentiles `json:"time_to_merge"`
+	AssignedToMerge LatencyPercentiles `json:"assigned_to_merge"`
+	Teams           []*TeamLatency     `json:"teams"`
+	Reviewers       []*ReviewerLatency `json:"reviewers"`
+}
+
+// число назначений ревьювера на PR команды за период
+type ReviewerLoad struct {
+	TeamName    string `json:"-"           db:"team_name"`
+	UserID      string `json:"user_id"     db:"user_id"`
+	UserName    string `json:"username"    db:"username"`
+	Assignments int    `json:"assignments" db:"assignments"`
+}
+
+// распределение назначений между активными участниками команды
+type TeamFairness struct {
+	TeamName         string          `json:"team_name"`
+	Reviewers        int             `json:"reviewers"`
+	TotalAssignments int             `json:"total_assignments"`
+	Min              int             `json:"min"`
+	Max              int             `json:"max"`
+	Mean             float64         `json:"mean"`
+	StdDev           float64         `json:"stddev"`
+	Gini             float64         `json:"gini"`
+	Overloaded       []*ReviewerLoad `json:"overloaded"`
+	Underloaded      []*ReviewerLoad `json:"underloaded"`
+}
+
+type TimeseriesMetric string
+
+const (
+	TimeseriesPullRequestsCreated TimeseriesMetric = "prs_created"
+	TimeseriesPullRequestsMerged  TimeseriesMetric = "prs_merged"
+	TimeseriesAssignments         TimeseriesMetric = "assignments"
+)
+
+// границы интервалов выравниваются по UTC, неделя начинается с понедельника
+type TimeseriesBucket string
+
+const (
+	TimeseriesDay   TimeseriesBucket = "day"
+	TimeseriesWeek  TimeseriesBucket = "week"
+	TimeseriesMonth TimeseriesBucket = "month"
+)
+
+// период [From, To); TeamName ограничивает ряд командой и её дочерними командами
+type TimeseriesFilter struct {
+	Metric   TimeseriesMetric
+	Bucket   TimeseriesBucket
+	TeamName string
+	From     time.Time
+	To       time.Time
+}
+
+type TimeseriesPoint struct {
+	BucketStart time.Time `json:"bucket_start" db:"bucket_start"`
+	Value       int       `json:"value"        db:"value"`
+}
+
+type Timeseries struct {
+	Metric   TimeseriesMetric   `json:"metric"`
+	Bucket   TimeseriesBucket   `json:"bucket"`
+	TeamName string             `json:"team,omitempty"`
+	From     time.Time          `json:"from"`
+	To       time.Time          `json:"to"`
+	Points   []*TimeseriesPoint `json:"points"`
+}
+
+type TeamReviewCount struct {
+	TeamName string `json:"team_name" db:"team_name"`
+	Reviews  int    `json:"reviews"   db:"reviews"`
+}
+
+// назначения считаются по событиям pr_events (включая снятые), остальные ревью — по текущим pr_reviewers
+type UserStats struct {
+	UserID               string             `json:"user_id"                db:"user_id"`
+	UserName             string             `json:"username"               db:"username"`
+	AuthoredPullRequests int                `json:"authored_pull_requests" db:"authored_pull_requests"`
+	OpenReviews          int                `json:"open_reviews"           db:"open_reviews"`
+	TotalReviews         int                `json:"total_reviews"          db:"total_reviews"`
+	ReassignedAway       int                `json:"reassigned_away"        db:"reassigned_away"`
+	MergedWhileReviewer  int                `json:"merged_while_reviewer"  db:"merged_while_reviewer"`
+	MedianHeldSeconds    *float64           `json:"median_held_seconds"    db:"median_held_seconds"`
+	ReviewedTeams        []*TeamReviewCount `json:"reviewed_teams"         db:"-"`
+}
+
+// счётчики для /metrics из материализованных представлений: отстают не больше чем на STATS_REFRESH_INTERVAL
+// и, так как считаются по журналу pr_events, не сбрасываются при рестарте
+type DomainCounters struct {
+	OpenPullRequests int                            `db:"open_pull_requests"`
+	Assignments      int                            `db:"assignments"`
+	Reassignments    map[PullRequestEventReason]int `db:"-"`
+}
+
+// открытые PR по возрасту: меньше суток, 1–3, 3–7 и больше 7 суток
+type AgingBuckets struct {
+	Under1Day int `json:"under_1d" db:"under_1d"`
+	Days1To3  int `json:"days_1_3" db:"days_1_3"`
+	Days3To7  int `json:"days_3_7" db:"days_3_7"`
+	Over7Days int `json:"over_7d"  db:"over_7d"`
+	Total     int `json:"total"    db:"total"`
+}
+
+type TeamAging struct {
+	TeamName string `json:"team_name" db:"team_name"`
+	AgingBuckets
+}
+
+// возраст считается от создания PR, а не от назначения ревьювера
+type ReviewerAging struct {
+	UserID   string `json:"user_id"  db:"user_id"`
+	UserName string `json:"username" db:"username"`
+	AgingBuckets
+}
+
+type AgingPullRequest struct {
+	PullRequestID     string    `json:"pull_request_id"    db:"pull_request_id"`
+	PullRequestName   string    `json:"pull_request_name"  db:"pull_request_name"`
+	AuthorID          string    `json:"author_id"          db:"author_id"`
+	CreatedAt         time.Time `json:"createdAt"          db:"created_at"`
+	AgeSeconds        int64     `json:"age_seconds"        db:"age_seconds"`
+	AssignedReviewers []string  `json:"assigned_reviewers" db:"-"`
+}
+
+type AgingStats struct {
+	AsOf      time.Time           `json:"as_of"`
+	Total     AgingBuckets        `json:"total"`
+	Teams     []*TeamAging        `json:"teams"`
+	Reviewers []*ReviewerAging    `json:"reviewers"`
+	Oldest    []*AgingPullRequest `json:"oldest"`
 }
@@ -2,16 +2,61 @@ package models
 
 import "time"
 
+type TeamRole string
+
+const (
+	TeamRoleMember     TeamRole = "member"
+	TeamRoleLead       TeamRole = "lead"
+	TeamRoleMaintainer TeamRole = "maintainer"
+)
+
 type TeamMember struct {
-	UserID   string `json:"user_id" db:"user_id"`
-	UserName string `json:"username" db:"username"`
-	IsActive bool   `json:"is_active" db:"is_active"`
+	UserID    string   `json:"user_id" db:"user_id"`
+	UserName  string   `json:"username" db:"username"`
+	IsActive  bool     `json:"is_active" db:"is_active"`
+	IsPrimary bool     `json:"is_primary" db:"is_primary"`
+	Role      TeamRole `json:"role" db:"role"`
 }
 
 type Team struct {
 	TeamName string       `json:"team_name" db:"team_name"`
 	Members  []TeamMember `json:"members" db:"-"`
+	IsActive bool         `json:"is_active" db:"is_active"`
+
+	ParentTeam *string `json:"parent_team,omitempty" db:"parent_team"`
+
+	ArchivedAt *time.Time `json:"-" db:"archived_at"`
+	CreatedAt  time.Time  `json:"-" db:"created_at"`
+	UpdatedAt  time.Time  `json:"-" db:"updated_at"`
+}
+
+type TeamSummary struct {
+	TeamName   string    `json:"team_name" db:"team_name"`
+	ParentTeam *string   `json:"parent_team,omitempty" db:"parent_team"`
+	IsActive   bool      `json:"is_active" db:"is_active"`
+	CreatedAt  time.Time `json:"created_at" db:"created_at"`
+
+	MemberCount       int `json:"member_count" db:"member_count"`
+	ActiveMemberCount int `json:"active_member_count" db:"active_member_count"`
+	OpenPullRequests  int `json:"open_pull_requests" db:"open_pull_requests"`
+}
+
+type TeamListFilter struct {
+	Search   string
+	IsActive *bool
+
+	Limit int
+	After *PageCursor
+}
+
+type TeamArchive struct {
+	TeamName         string    `json:"team_name"`
+	DeactivatedUsers []string  `json:"deactivated_users"`
+	ArchivedAt       time.Time `json:"archived_at"`
+}
 
-	CreatedAt time.Time `json:"-" db:"created_at"`
-	UpdatedAt time.Time `json:"-" db:"updated_at"`
+type TeamExport struct {
+	Team         *Team               `json:"team"`
+	PullRequests []*PullRequest      `json:"pull_requests"`
+	Reviews      []*ReviewAssignment `json:"reviews"`
 }
@@ -8,6 +8,38 @@ type User struct {
 	TeamName string `json:"team_name" db:"team_name"`
 	IsActive bool   `json:"is_active" db:"is_active"`
 
+	// основная команда хранится в TeamName, Teams — все команды пользователя
+	Teams []string `json:"teams" db:"-"`
+
+	OpenReviewCount int `json:"open_review_count" db:"open_review_count"`
+
 	CreatedAt time.Time `json:"-" db:"created_at"`
 	UpdatedAt time.Time `json:"-" db:"updated_at"`
 }
+
+// запись аудита удаления персональных данных пользователя
+type UserErasure struct {
+	Pseudonym         string    `json:"user_id" db:"pseudonym"`
+	RequestedBy       string    `json:"requested_by" db:"requested_by"`
+	ReassignedReviews int       `json:"reassigned_reviews" db:"reassigned_reviews"`
+	ErasedAt          time.Time `json:"erased_at" db:"erased_at"`
+}
+
+type UserSort string
+
+const (
+	UserSortUserID          UserSort = "user_id"
+	UserSortUserName        UserSort = "username"
+	UserSortOpenReviewCount UserSort = "open_review_count"
+)
+
+type UserListFilter struct {
+	TeamName   string
+	IsActive   *bool
+	NamePrefix string
+
+	Sort  UserSort
+	Desc  bool
+	Limit int
+	After *PageCursor
+}
@@ -0,0 +1,28 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/guarref/pr-service-assignment/internal/models"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockImportRepository struct {
+	mock.Mock
+}
+
+func (m *MockImportRepository) GetAffectedTeams(ctx context.Context, rows []models.ImportRow) ([]*models.ImportAffectedTeam, error) {
+	args := m.Called(ctx, rows)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.ImportAffectedTeam), args.Error(1)
+}
+
+func (m *MockImportRepository) ImportTeams(ctx context.Context, rows []models.ImportRow, dryRun bool) (*models.ImportDiff, int, error) {
+	args := m.Called(ctx, rows, dryRun)
+	if args.Get(0) == nil {
+		return nil, 0, args.Error(2)
+	}
+	return args.Get(0).(*models.ImportDiff), args.Int(1), args.Error(2)
+}
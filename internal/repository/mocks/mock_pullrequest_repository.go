@@ -35,11 +35,50 @@ func (m *MockPullRequestRepository) ReassignToPullRequest(ctx context.Context, p
 	return args.Get(0).(*models.PullRequest), args.Get(1).(string), args.Error(2)
 }
 
-func (m *MockPullRequestRepository) GetPullRequestByReviewerID(ctx context.Context, userID string) ([]*models.PullRequestShort, error) {
-	args := m.Called(ctx, userID)
+func (m *MockPullRequestRepository) GetPullRequestByReviewerID(ctx context.Context, filter models.ReviewQueueFilter) ([]*models.PullRequestShort, error) {
+	args := m.Called(ctx, filter)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).([]*models.PullRequestShort), args.Error(1)
 }
 
+func (m *MockPullRequestRepository) ListPullRequests(ctx context.Context, filter models.PullRequestListFilter) ([]*models.PullRequest, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.PullRequest), args.Error(1)
+}
+
+func (m *MockPullRequestRepository) GetPullRequestsByIDs(ctx context.Context, prIDs []string) ([]*models.PullRequestDetails, error) {
+	args := m.Called(ctx, prIDs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.PullRequestDetails), args.Error(1)
+}
+
+func (m *MockPullRequestRepository) GetPullRequestsByAuthorID(ctx context.Context, filter models.AuthoredPullRequestsFilter) ([]*models.PullRequestDetails, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.PullRequestDetails), args.Error(1)
+}
+
+func (m *MockPullRequestRepository) SearchPullRequests(ctx context.Context, query string, limit int) ([]*models.PullRequest, error) {
+	args := m.Called(ctx, query, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.PullRequest), args.Error(1)
+}
+
+func (m *MockPullRequestRepository) GetPullRequestTimeline(ctx context.Context, prID string) ([]*models.PullRequestEvent, error) {
+	args := m.Called(ctx, prID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.PullRequestEvent), args.Error(1)
+}
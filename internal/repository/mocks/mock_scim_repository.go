@@ -0,0 +1,64 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/guarref/pr-service-assignment/internal/models"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockScimRepository struct {
+	mock.Mock
+}
+
+func (m *MockScimRepository) ListUsers(ctx context.Context, filter models.ScimUserFilter) ([]*models.User, int, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) == nil {
+		return nil, args.Int(1), args.Error(2)
+	}
+	return args.Get(0).([]*models.User), args.Int(1), args.Error(2)
+}
+
+func (m *MockScimRepository) GetUser(ctx context.Context, userID string) (*models.User, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.User), args.Error(1)
+}
+
+func (m *MockScimRepository) CreateUser(ctx context.Context, user *models.User) error {
+	args := m.Called(ctx, user)
+	return args.Error(0)
+}
+
+func (m *MockScimRepository) UpdateUser(ctx context.Context, userID string, userName string, isActive bool) (int, error) {
+	args := m.Called(ctx, userID, userName, isActive)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockScimRepository) ListGroups(ctx context.Context, filter models.ScimGroupFilter) ([]*models.Team, int, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) == nil {
+		return nil, args.Int(1), args.Error(2)
+	}
+	return args.Get(0).([]*models.Team), args.Int(1), args.Error(2)
+}
+
+func (m *MockScimRepository) GetGroup(ctx context.Context, teamName string) (*models.Team, error) {
+	args := m.Called(ctx, teamName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Team), args.Error(1)
+}
+
+func (m *MockScimRepository) CreateGroup(ctx context.Context, teamName string, memberIDs []string) error {
+	args := m.Called(ctx, teamName, memberIDs)
+	return args.Error(0)
+}
+
+func (m *MockScimRepository) UpdateGroupMembers(ctx context.Context, teamName string, update models.ScimMembersUpdate) (int, error) {
+	args := m.Called(ctx, teamName, update)
+	return args.Int(0), args.Error(1)
+}
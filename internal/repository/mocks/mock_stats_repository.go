@@ -11,10 +11,71 @@ type MockStatsRepository struct {
 	mock.Mock
 }
 
-func (m *MockStatsRepository) GetStats(ctx context.Context, top int) (*models.Stats, error) {
-	args := m.Called(ctx, top)
+func (m *MockStatsRepository) GetStats(ctx context.Context, top int, teamName string, window models.StatsWindow) (*models.Stats, error) {
+	args := m.Called(ctx, top, teamName, window)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).(*models.Stats), args.Error(1)
 }
+
+func (m *MockStatsRepository) GetTeamStats(ctx context.Context, teamName string, requiredReviewers int) (*models.TeamStats, error) {
+	args := m.Called(ctx, teamName, requiredReviewers)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.TeamStats), args.Error(1)
+}
+
+func (m *MockStatsRepository) GetLatencyStats(ctx context.Context, window models.StatsWindow) (*models.LatencyStats, error) {
+	args := m.Called(ctx, window)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.LatencyStats), args.Error(1)
+}
+
+func (m *MockStatsRepository) GetReviewerLoads(ctx context.Context, teamName string, window models.StatsWindow) ([]*models.ReviewerLoad, error) {
+	args := m.Called(ctx, teamName, window)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.ReviewerLoad), args.Error(1)
+}
+
+func (m *MockStatsRepository) GetTimeseries(ctx context.Context, filter models.TimeseriesFilter) ([]*models.TimeseriesPoint, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.TimeseriesPoint), args.Error(1)
+}
+
+func (m *MockStatsRepository) GetUserStats(ctx context.Context, userID string) (*models.UserStats, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.UserStats), args.Error(1)
+}
+
+func (m *MockStatsRepository) RefreshStatsSummary(ctx context.Context) error {
+	args := m.Called(ctx)
+	return args.Error(0)
+}
+
+func (m *MockStatsRepository) GetAgingStats(ctx context.Context, teamName string, oldest int) (*models.AgingStats, error) {
+	args := m.Called(ctx, teamName, oldest)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.AgingStats), args.Error(1)
+}
+
+func (m *MockStatsRepository) GetDomainCounters(ctx context.Context) (*models.DomainCounters, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.DomainCounters), args.Error(1)
+}
@@ -24,11 +24,40 @@ func (m *MockTeamRepository) GetTeamByName(ctx context.Context, name string) (*m
 	return args.Get(0).(*models.Team), args.Error(1)
 }
 
-func (m *MockTeamRepository) DeactivateUsersAndReassignPRs(ctx context.Context, teamName string, userIDs []string) ([]string, error) {
+func (m *MockTeamRepository) DeactivateUsersAndReassignPRs(ctx context.Context, teamName string, userIDs []string) ([]string, int, error) {
 	args := m.Called(ctx, teamName, userIDs)
+	if args.Get(0) == nil {
+		return nil, 0, args.Error(2)
+	}
+	return args.Get(0).([]string), args.Int(1), args.Error(2)
+}
+
+
+func (m *MockTeamRepository) ArchiveTeam(ctx context.Context, teamName string) (*models.TeamArchive, int, error) {
+	args := m.Called(ctx, teamName)
+	if args.Get(0) == nil {
+		return nil, 0, args.Error(2)
+	}
+	return args.Get(0).(*models.TeamArchive), args.Int(1), args.Error(2)
+}
+
+func (m *MockTeamRepository) DeleteTeam(ctx context.Context, teamName string, force bool) (*models.TeamExport, int, error) {
+	args := m.Called(ctx, teamName, force)
+	if args.Get(0) == nil {
+		return nil, 0, args.Error(2)
+	}
+	return args.Get(0).(*models.TeamExport), args.Int(1), args.Error(2)
+}
+
+func (m *MockTeamRepository) ListTeams(ctx context.Context, filter models.TeamListFilter) ([]*models.TeamSummary, error) {
+	args := m.Called(ctx, filter)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
-	return args.Get(0).([]string), args.Error(1)
+	return args.Get(0).([]*models.TeamSummary), args.Error(1)
 }
 
+func (m *MockTeamRepository) GetMemberRole(ctx context.Context, teamName string, userID string) (models.TeamRole, error) {
+	args := m.Called(ctx, teamName, userID)
+	return args.Get(0).(models.TeamRole), args.Error(1)
+}
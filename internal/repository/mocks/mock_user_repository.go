@@ -35,3 +35,31 @@ func (m *MockUserRepository) GetActiveUsersByTeam(ctx context.Context, teamName
 	return args.Get(0).([]*models.User), args.Error(1)
 }
 
+func (m *MockUserRepository) ListUsers(ctx context.Context, filter models.UserListFilter) ([]*models.User, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.User), args.Error(1)
+}
+
+func (m *MockUserRepository) IsManagerOf(ctx context.Context, managerID string, userID string) (bool, error) {
+	args := m.Called(ctx, managerID, userID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockUserRepository) EraseUser(ctx context.Context, userID string, subjectHash string, pseudonym string, requestedBy string) (*models.UserErasure, int, error) {
+	args := m.Called(ctx, userID, subjectHash, pseudonym, requestedBy)
+	if args.Get(0) == nil {
+		return nil, 0, args.Error(2)
+	}
+	return args.Get(0).(*models.UserErasure), args.Int(1), args.Error(2)
+}
+
+func (m *MockUserRepository) GetUserErasure(ctx context.Context, userID string, subjectHash string) (*models.UserErasure, error) {
+	args := m.Called(ctx, userID, subjectHash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.UserErasure), args.Error(1)
+}
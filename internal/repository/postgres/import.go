@@ -0,0 +1,222 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/guarref/pr-service-assignment/internal/models"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+type ImportRepository struct {
+	db *sqlx.DB
+}
+
+func NewImportRepository(db *sqlx.DB) *ImportRepository {
+	return &ImportRepository{db: db}
+}
+
+// команды из строк файла, которые уже существуют, и активные команды пользователей,
+// у которых импорт меняет имя или флаг активности
+func (ir *ImportRepository) GetAffectedTeams(ctx context.Context, rows []models.ImportRow) ([]*models.ImportAffectedTeam, error) {
+
+	teamNames := make([]string, 0, len(rows))
+	userIDs := make([]string, 0, len(rows))
+	userNames := make([]string, 0, len(rows))
+	isActive := make([]bool, 0, len(rows))
+	for _, row := range rows {
+		teamNames = append(teamNames, row.TeamName)
+		userIDs = append(userIDs, row.UserID)
+		userNames = append(userNames, row.UserName)
+		isActive = append(isActive, row.IsActive)
+	}
+
+	query := `WITH incoming AS (
+			SELECT DISTINCT user_id, username, is_active
+			FROM unnest($2::text[], $3::text[], $4::boolean[]) AS i(user_id, username, is_active)
+		)
+		SELECT team_name, is_active FROM teams WHERE team_name = ANY($1)
+		UNION
+		SELECT t.team_name, t.is_active
+		FROM incoming i
+		JOIN users u ON u.user_id = i.user_id AND (u.username <> i.username OR u.is_active <> i.is_active)
+		JOIN team_memberships tm ON tm.user_id = u.user_id AND tm.is_active
+		JOIN teams t ON t.team_name = tm.team_name AND t.is_active
+		ORDER BY team_name`
+
+	var teams []*models.ImportAffectedTeam
+	if err := ir.db.SelectContext(ctx, &teams, query,
+		pq.Array(teamNames), pq.Array(userIDs), pq.Array(userNames), pq.Array(isActive)); err != nil {
+		return nil, fmt.Errorf("error getting teams affected by import: %w", err)
+	}
+
+	return teams, nil
+}
+
+// разница считается и применяется в одной транзакции, при dryRun транзакция откатывается
+// команды и членства только добавляются, пользователи обновляются (upsert)
+func (ir *ImportRepository) ImportTeams(ctx context.Context, rows []models.ImportRow, dryRun bool) (*models.ImportDiff, int, error) {
+
+	tx, err := ir.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, 0, fmt.Errorf("error begining transaction import: %w", err)
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil && !errors.Is(err, sql.ErrTxDone) {
+			log.Printf("rollback error: %v", err)
+		}
+	}()
+
+	teamNames := make([]string, 0, len(rows))
+	userIDs := make([]string, 0, len(rows))
+	for _, row := range rows {
+		teamNames = append(teamNames, row.TeamName)
+		userIDs = append(userIDs, row.UserID)
+	}
+
+	var existingTeams []string
+	teamsQuery := `SELECT team_name FROM teams WHERE team_name = ANY($1)`
+	if err := tx.SelectContext(ctx, &existingTeams, teamsQuery, pq.Array(teamNames)); err != nil {
+		return nil, 0, fmt.Errorf("error getting existing teams: %w", err)
+	}
+
+	type userState struct {
+		UserID   string `db:"user_id"`
+		UserName string `db:"username"`
+		IsActive bool   `db:"is_active"`
+	}
+
+	var existingUsers []userState
+	usersQuery := `SELECT user_id, username, is_active FROM users WHERE user_id = ANY($1) FOR UPDATE`
+	if err := tx.SelectContext(ctx, &existingUsers, usersQuery, pq.Array(userIDs)); err != nil {
+		return nil, 0, fmt.Errorf("error getting existing users: %w", err)
+	}
+
+	var existingMemberships []models.ImportMembership
+	membershipsQuery := `SELECT team_name, user_id FROM team_memberships WHERE user_id = ANY($1)`
+	if err := tx.SelectContext(ctx, &existingMemberships, membershipsQuery, pq.Array(userIDs)); err != nil {
+		return nil, 0, fmt.Errorf("error getting existing memberships: %w", err)
+	}
+
+	teams := make(map[string]bool, len(existingTeams))
+	for _, name := range existingTeams {
+		teams[name] = true
+	}
+	users := make(map[string]userState, len(existingUsers))
+	for _, u := range existingUsers {
+		users[u.UserID] = u
+	}
+	memberships := make(map[models.ImportMembership]bool, len(existingMemberships))
+	for _, m := range existingMemberships {
+		memberships[m] = true
+	}
+
+	diff := &models.ImportDiff{
+		DryRun:           dryRun,
+		TeamsCreated:     []string{},
+		UsersCreated:     []string{},
+		UsersUpdated:     []models.ImportUserUpdate{},
+		MembershipsAdded: []models.ImportMembership{},
+	}
+
+	var (
+		upsertUsers  []models.ImportRow
+		deactivated  []string
+		handledUsers = make(map[string]bool, len(rows))
+	)
+
+	for _, row := range rows {
+		if !teams[row.TeamName] {
+			teams[row.TeamName] = true
+			diff.TeamsCreated = append(diff.TeamsCreated, row.TeamName)
+		}
+
+		userChanged := false
+		if !handledUsers[row.UserID] {
+			handledUsers[row.UserID] = true
+
+			current, ok := users[row.UserID]
+			switch {
+			case !ok:
+				diff.UsersCreated = append(diff.UsersCreated, row.UserID)
+				upsertUsers = append(upsertUsers, row)
+				userChanged = true
+			case current.UserName != row.UserName || current.IsActive != row.IsActive:
+				diff.UsersUpdated = append(diff.UsersUpdated, models.ImportUserUpdate{
+					UserID:      row.UserID,
+					OldUserName: current.UserName,
+					NewUserName: row.UserName,
+					OldIsActive: current.IsActive,
+					NewIsActive: row.IsActive,
+				})
+				upsertUsers = append(upsertUsers, row)
+				userChanged = true
+				if current.IsActive && !row.IsActive {
+					deactivated = append(deactivated, row.UserID)
+				}
+			}
+		}
+
+		membership := models.ImportMembership{TeamName: row.TeamName, UserID: row.UserID}
+		if !memberships[membership] {
+			memberships[membership] = true
+			diff.MembershipsAdded = append(diff.MembershipsAdded, membership)
+			continue
+		}
+
+		if !userChanged {
+			diff.Unchanged++
+		}
+	}
+
+	if dryRun {
+		return diff, 0, nil
+	}
+
+	creationTeamQuery := `INSERT INTO teams (team_name, created_at, updated_at) VALUES ($1, NOW(), NOW())
+		ON CONFLICT (team_name) DO NOTHING`
+	for _, name := range diff.TeamsCreated {
+		if _, err := tx.ExecContext(ctx, creationTeamQuery, name); err != nil {
+			return nil, 0, fmt.Errorf("error creating team %s: %w", name, err)
+		}
+	}
+
+	upsertUserQuery := `INSERT INTO users (user_id, username, is_active, created_at, updated_at)
+		VALUES ($1, $2, $3, NOW(), NOW()) ON CONFLICT (user_id)
+		DO UPDATE SET
+		username   = EXCLUDED.username,
+		is_active  = EXCLUDED.is_active,
+		updated_at = NOW()`
+	for _, row := range upsertUsers {
+		if _, err := tx.ExecContext(ctx, upsertUserQuery, row.UserID, row.UserName, row.IsActive); err != nil {
+			return nil, 0, fmt.Errorf("error upserting user %s: %w", row.UserID, err)
+		}
+	}
+
+	additionMembershipQuery := `INSERT INTO team_memberships (user_id, team_name, is_primary, is_active, created_at, updated_at)
+		VALUES ($1, $2, NOT EXISTS(SELECT 1 FROM team_memberships WHERE user_id = $1 AND is_primary), true, NOW(), NOW())
+		ON CONFLICT (user_id, team_name) DO NOTHING`
+	for _, m := range diff.MembershipsAdded {
+		if _, err := tx.ExecContext(ctx, additionMembershipQuery, m.UserID, m.TeamName); err != nil {
+			return nil, 0, fmt.Errorf("error adding membership of user %s: %w", m.UserID, err)
+		}
+	}
+
+	// как и при деактивации, открытые ревью выключенных пользователей переназначаются
+	dropped := 0
+	if len(deactivated) > 0 {
+		if dropped, err = reassignOpenReviews(ctx, tx, deactivated, models.EventReasonDeactivation); err != nil {
+			return nil, 0, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, 0, fmt.Errorf("error committing transaction import: %w", err)
+	}
+
+	return diff, dropped, nil
+}
@@ -6,12 +6,11 @@ import (
 	"errors"
 	"fmt"
 	"log"
-	"math/rand"
-	"time"
 
 	"github.com/guarref/pr-service-assignment/internal/errs"
 	"github.com/guarref/pr-service-assignment/internal/models"
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 )
 
 type PullRequestRepository struct {
@@ -50,6 +49,10 @@ func (prr *PullRequestRepository) CreatePullRequest(ctx context.Context, pr *mod
 		return nil, fmt.Errorf("error pull request creation: %w", err)
 	}
 
+	if err := insertPullRequestEvent(ctx, tx, newPR.PullRequestID, models.PullRequestEventCreated, newPR.AuthorID, ""); err != nil {
+		return nil, err
+	}
+
 	if len(pr.AssignedReviewers) > 0 {
 		reviewerIns := `INSERT INTO pr_reviewers (pull_request_id, user_id, assigned_at) VALUES ($1, $2, NOW())`
 
@@ -57,6 +60,9 @@ func (prr *PullRequestRepository) CreatePullRequest(ctx context.Context, pr *mod
 			if _, err := tx.ExecContext(ctx, reviewerIns, newPR.PullRequestID, reviewerID); err != nil {
 				return nil, fmt.Errorf("error addition reviewer %s: %w", reviewerID, err)
 			}
+			if err := insertPullRequestEvent(ctx, tx, newPR.PullRequestID, models.PullRequestEventReviewerAssigned, reviewerID, models.EventReasonCreation); err != nil {
+				return nil, err
+			}
 		}
 	}
 
@@ -90,6 +96,16 @@ func (prr *PullRequestRepository) MergePullRequestByID(ctx context.Context, prID
 		}
 	}()
 
+	// блокировка строки, чтобы событие merged записал только первый из параллельных запросов
+	var prevStatus models.PullRequestStatus
+	statusQuery := `SELECT status FROM pull_requests WHERE pull_request_id = $1 FOR UPDATE`
+	if err := tx.GetContext(ctx, &prevStatus, statusQuery, prID); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errs.ErrPullRequestNotFound
+		}
+		return nil, fmt.Errorf("error getting pull request status: %w", err)
+	}
+
 	updateQuery := `UPDATE pull_requests
 		SET status = $1,
 		merged_at = COALESCE(merged_at, NOW())
@@ -98,12 +114,15 @@ func (prr *PullRequestRepository) MergePullRequestByID(ctx context.Context, prID
 
 	var pr models.PullRequest
 	if err := tx.GetContext(ctx, &pr, updateQuery, models.PullRequestMerged, prID); err != nil {
-		if err == sql.ErrNoRows {
-			return nil, errs.ErrPullRequestNotFound
-		}
 		return nil, fmt.Errorf("error updating pull request status to MERGED: %w", err)
 	}
 
+	if prevStatus != models.PullRequestMerged {
+		if err := insertPullRequestEvent(ctx, tx, prID, models.PullRequestEventMerged, "", ""); err != nil {
+			return nil, err
+		}
+	}
+
 	reviewersQuery := `SELECT user_id
 		FROM pr_reviewers
 		WHERE pull_request_id = $1
@@ -162,12 +181,18 @@ func (prr *PullRequestRepository) ReassignToPullRequest(ctx context.Context, prI
 		return nil, "", errs.ErrNotAssigned
 	}
 
-	teamQuery := `SELECT team_name
-		FROM users
-		WHERE user_id = $1`
+	// замену ищем в общей с автором команде ревьювера, а если такой нет — в основной команде ревьювера
+	teamQuery := `SELECT tm.team_name
+		FROM team_memberships tm
+		WHERE tm.user_id = $1
+		ORDER BY EXISTS(SELECT 1 FROM team_memberships a WHERE a.user_id = $2 AND a.team_name = tm.team_name AND a.is_primary) DESC,
+			EXISTS(SELECT 1 FROM team_memberships a WHERE a.user_id = $2 AND a.team_name = tm.team_name) DESC,
+			tm.is_primary DESC,
+			tm.team_name
+		LIMIT 1`
 
 	var oldUserTeam string
-	if err := tx.GetContext(ctx, &oldUserTeam, teamQuery, oldUserID); err != nil {
+	if err := tx.GetContext(ctx, &oldUserTeam, teamQuery, oldUserID, pr.AuthorID); err != nil {
 		if err == sql.ErrNoRows {
 			return nil, "", errs.ErrUserNotFound
 		}
@@ -183,33 +208,19 @@ func (prr *PullRequestRepository) ReassignToPullRequest(ctx context.Context, prI
 		return nil, "", fmt.Errorf("error getting current reviewers: %w", err)
 	}
 
-	currentSet := make(map[string]struct{}, len(currentReviewers))
-	for _, id := range currentReviewers {
-		currentSet[id] = struct{}{}
-	}
-
-	usersQuery := `SELECT user_id
-		FROM users
-		WHERE team_name = $1 AND is_active = true AND user_id <> $2 AND user_id <> $3`
+	excludeUsers := append(currentReviewers, pr.AuthorID)
 
-	var allUsersIDs []string
-	if err := tx.SelectContext(ctx, &allUsersIDs, usersQuery, oldUserTeam, oldUserID, pr.AuthorID); err != nil {
+	accessible, err := findCandidates(ctx, tx, oldUserTeam, excludeUsers)
+	if err != nil {
 		return nil, "", fmt.Errorf("error getting users for reassign: %w", err)
 	}
 
-	accessible := make([]string, 0, len(allUsersIDs))
-	for _, id := range allUsersIDs {
-		if _, ok := currentSet[id]; ok {
-			continue
-		}
-		accessible = append(accessible, id)
-	}
-
 	if len(accessible) == 0 {
 		return nil, "", errs.ErrNoCandidate
 	}
 
-	newReviewerID := getRandomID(accessible)
+	// кандидаты уже упорядочены по нагрузке
+	newReviewerID := accessible[0]
 
 	deleteQuery := `DELETE FROM pr_reviewers
 		WHERE pull_request_id = $1 AND user_id = $2`
@@ -217,12 +228,18 @@ func (prr *PullRequestRepository) ReassignToPullRequest(ctx context.Context, prI
 	if _, err := tx.ExecContext(ctx, deleteQuery, prID, oldUserID); err != nil {
 		return nil, "", fmt.Errorf("error delete old reviewer: %w", err)
 	}
+	if err := insertPullRequestEvent(ctx, tx, prID, models.PullRequestEventReviewerRemoved, oldUserID, models.EventReasonManualReassign); err != nil {
+		return nil, "", err
+	}
 
 	reviewerIns := `INSERT INTO pr_reviewers (pull_request_id, user_id, assigned_at)
 		VALUES ($1, $2, NOW())`
 	if _, err := tx.ExecContext(ctx, reviewerIns, prID, newReviewerID); err != nil {
 		return nil, "", fmt.Errorf("error addition reviewer %s: %w", newReviewerID, err)
 	}
+	if err := insertPullRequestEvent(ctx, tx, prID, models.PullRequestEventReviewerAssigned, newReviewerID, models.EventReasonManualReassign); err != nil {
+		return nil, "", err
+	}
 
 	var updatedReviewers []string
 	if err := tx.SelectContext(ctx, &updatedReviewers, reviewersQuery, prID); err != nil {
@@ -237,35 +254,294 @@ func (prr *PullRequestRepository) ReassignToPullRequest(ctx context.Context, prI
 	return &pr, newReviewerID, nil
 }
 
-func getRandomID(ids []string) string {
+func (prr *PullRequestRepository) GetPullRequestByReviewerID(ctx context.Context, filter models.ReviewQueueFilter) ([]*models.PullRequestShort, error) {
+
+	query := `SELECT pull_request_id, pull_request_name, author_id, status, created_at, assigned_at, age_seconds
+		FROM (
+			SELECT pr.pull_request_id, pr.pull_request_name, pr.author_id, pr.status, pr.created_at, prev.assigned_at,
+				EXTRACT(EPOCH FROM COALESCE(pr.merged_at, now()) - pr.created_at)::bigint AS age_seconds,
+				CASE WHEN pr.status = 'OPEN' THEN 0 ELSE 1 END AS status_rank
+			FROM pull_requests pr
+			INNER JOIN pr_reviewers prev ON pr.pull_request_id = prev.pull_request_id
+			WHERE prev.user_id = $1
+			AND ($2::text = '' OR pr.status = $2::text)
+		) queue`
+
+	args := []any{filter.ReviewerID, string(filter.Status)}
+
+	var keyset, order string
+	switch filter.Sort {
+	case models.ReviewQueueSortOldest:
+		keyset = `(created_at, pull_request_id) > ($3::timestamptz, $4::text)`
+		order = `created_at ASC, pull_request_id ASC`
+	case models.ReviewQueueSortPriority:
+		keyset = `(status_rank, assigned_at, pull_request_id) > ($3::int, $4::timestamptz, $5::text)`
+		order = `status_rank ASC, assigned_at ASC, pull_request_id ASC`
+	default:
+		keyset = `(created_at, pull_request_id) < ($3::timestamptz, $4::text)`
+		order = `created_at DESC, pull_request_id DESC`
+	}
+
+	if filter.After != nil {
+		if filter.Sort == models.ReviewQueueSortPriority {
+			args = append(args, filter.After.StatusRank)
+		}
+		args = append(args, filter.After.Time, filter.After.ID)
+		query += `
+		WHERE ` + keyset
+	}
+
+	args = append(args, filter.Limit)
+	query += fmt.Sprintf(`
+		ORDER BY %s
+		LIMIT $%d`, order, len(args))
 
-	if len(ids) == 0 {
-		return ""
+	var prs []*models.PullRequestShort
+	if err := prr.db.SelectContext(ctx, &prs, query, args...); err != nil {
+		return nil, fmt.Errorf("error getting pull requests by reviewer %s: %w", filter.ReviewerID, err)
 	}
-	if len(ids) == 1 {
-		return ids[0]
+
+	if prs == nil {
+		prs = []*models.PullRequestShort{}
 	}
 
-	r := rand.New(rand.NewSource(time.Now().UnixNano()))
-	return ids[r.Intn(len(ids))]
+	return prs, nil
 }
 
-func (prr *PullRequestRepository) GetPullRequestByReviewerID(ctx context.Context, userID string) ([]*models.PullRequestShort, error) {
+// сортировка: выражение в выборке и тип для приведения значения из курсора;
+// у открытых PR merged_at пуст, при сортировке по нему они считаются смерженными в бесконечности
+var pullRequestSortColumns = map[models.PullRequestSort]struct {
+	column string
+	cast   string
+}{
+	models.PullRequestSortCreatedAt:       {column: "created_at", cast: "timestamptz"},
+	models.PullRequestSortMergedAt:        {column: "merged_sort", cast: "timestamptz"},
+	models.PullRequestSortPullRequestID:   {column: "pull_request_id", cast: "text"},
+	models.PullRequestSortPullRequestName: {column: "pull_request_name", cast: "text"},
+}
 
-	query := `SELECT pr.pull_request_id, pr.pull_request_name, pr.author_id,pr.status
-        FROM pull_requests pr
-        INNER JOIN pr_reviewers prev ON pr.pull_request_id = prev.pull_request_id
-        WHERE prev.user_id = $1
-        ORDER BY pr.created_at DESC`
+type pullRequestListRow struct {
+	models.PullRequest
+	Reviewers pq.StringArray `db:"assigned_reviewers"`
+}
 
-	var prs []*models.PullRequestShort
-	if err := prr.db.SelectContext(ctx, &prs, query, userID); err != nil {
-		return nil, fmt.Errorf("error getting pull requests by reviewer %s: %w", userID, err)
+// команда — PR, автор которых состоит в ней (в том числе не основной командой)
+func (prr *PullRequestRepository) ListPullRequests(ctx context.Context, filter models.PullRequestListFilter) ([]*models.PullRequest, error) {
+
+	sortColumn, ok := pullRequestSortColumns[filter.Sort]
+	if !ok {
+		sortColumn = pullRequestSortColumns[models.PullRequestSortCreatedAt]
+	}
+
+	direction, op := "ASC", ">"
+	if filter.Desc {
+		direction, op = "DESC", "<"
+	}
+
+	listQuery := `WITH list AS (
+			SELECT pr.pull_request_id, pr.pull_request_name, pr.author_id, pr.status, pr.created_at, pr.merged_at,
+				COALESCE(pr.merged_at, 'infinity'::timestamptz) AS merged_sort,
+				ARRAY(SELECT rev.user_id FROM pr_reviewers rev WHERE rev.pull_request_id = pr.pull_request_id
+					ORDER BY rev.assigned_at, rev.user_id) AS assigned_reviewers
+			FROM pull_requests pr
+			WHERE ($1::text = '' OR pr.status = $1::text)
+			AND ($2::text = '' OR pr.author_id = $2::text)
+			AND ($3::text = '' OR EXISTS(
+				SELECT 1 FROM pr_reviewers f WHERE f.pull_request_id = pr.pull_request_id AND f.user_id = $3::text
+			))
+			AND ($4::text = '' OR EXISTS(
+				SELECT 1 FROM team_memberships tm WHERE tm.user_id = pr.author_id AND tm.team_name = $4::text
+			))
+			AND ($5::timestamptz IS NULL OR pr.created_at >= $5::timestamptz)
+			AND ($6::timestamptz IS NULL OR pr.created_at < $6::timestamptz)
+			AND ($7::timestamptz IS NULL OR pr.merged_at >= $7::timestamptz)
+			AND ($8::timestamptz IS NULL OR pr.merged_at < $8::timestamptz)
+		)
+		SELECT pull_request_id, pull_request_name, author_id, status, created_at, merged_at, assigned_reviewers
+		FROM list`
+
+	args := []any{
+		string(filter.Status), filter.AuthorID, filter.ReviewerID, filter.TeamName,
+		filter.CreatedFrom, filter.CreatedTo, filter.MergedFrom, filter.MergedTo,
+	}
+
+	if filter.After != nil {
+		args = append(args, filter.After.Key, filter.After.ID)
+		listQuery += fmt.Sprintf(`
+		WHERE (%s, pull_request_id) %s ($9::%s, $10::text)`, sortColumn.column, op, sortColumn.cast)
+	}
+
+	args = append(args, filter.Limit)
+	listQuery += fmt.Sprintf(`
+		ORDER BY %s %s, pull_request_id %s
+		LIMIT $%d`, sortColumn.column, direction, direction, len(args))
+
+	var rows []pullRequestListRow
+	if err := prr.db.SelectContext(ctx, &rows, listQuery, args...); err != nil {
+		return nil, fmt.Errorf("error listing pull requests: %w", err)
+	}
+
+	prs := make([]*models.PullRequest, 0, len(rows))
+	for _, row := range rows {
+		pr := row.PullRequest
+		pr.AssignedReviewers = []string(row.Reviewers)
+		prs = append(prs, &pr)
+	}
+
+	return prs, nil
+}
+
+// полнотекстовый поиск по названию и подстрочный поиск через триграммы (ключи задач вроде PAY-1234);
+// ранг — сумма ts_rank и триграммной похожести
+func (prr *PullRequestRepository) SearchPullRequests(ctx context.Context, query string, limit int) ([]*models.PullRequest, error) {
+
+	searchQuery := `SELECT pr.pull_request_id, pr.pull_request_name, pr.author_id, pr.status, pr.created_at, pr.merged_at,
+			ARRAY(SELECT rev.user_id FROM pr_reviewers rev WHERE rev.pull_request_id = pr.pull_request_id
+				ORDER BY rev.assigned_at, rev.user_id) AS assigned_reviewers
+		FROM pull_requests pr
+		WHERE to_tsvector('simple', pr.pull_request_name) @@ plainto_tsquery('simple', $1)
+		OR pr.pull_request_name ILIKE '%' || $2 || '%'
+		ORDER BY ts_rank(to_tsvector('simple', pr.pull_request_name), plainto_tsquery('simple', $1))
+			+ similarity(pr.pull_request_name, $1) DESC,
+			pr.created_at DESC, pr.pull_request_id
+		LIMIT $3`
+
+	var rows []pullRequestListRow
+	if err := prr.db.SelectContext(ctx, &rows, searchQuery, query, escapeLike(query), limit); err != nil {
+		return nil, fmt.Errorf("error searching pull requests: %w", err)
+	}
+
+	prs := make([]*models.PullRequest, 0, len(rows))
+	for _, row := range rows {
+		pr := row.PullRequest
+		pr.AssignedReviewers = []string(row.Reviewers)
+		prs = append(prs, &pr)
+	}
+
+	return prs, nil
+}
+
+// порядок результата не определён, отсутствующие id пропускаются
+func (prr *PullRequestRepository) GetPullRequestsByIDs(ctx context.Context, prIDs []string) ([]*models.PullRequestDetails, error) {
+
+	prsQuery := `SELECT pull_request_id, pull_request_name, author_id, status, created_at, merged_at
+		FROM pull_requests
+		WHERE pull_request_id = ANY($1)`
+
+	var prs []*models.PullRequestDetails
+	if err := prr.db.SelectContext(ctx, &prs, prsQuery, pq.Array(prIDs)); err != nil {
+		return nil, fmt.Errorf("error getting pull requests: %w", err)
+	}
+
+	if err := prr.loadReviewers(ctx, prs); err != nil {
+		return nil, err
+	}
+
+	return prs, nil
+}
+
+func (prr *PullRequestRepository) GetPullRequestsByAuthorID(ctx context.Context, filter models.AuthoredPullRequestsFilter) ([]*models.PullRequestDetails, error) {
+
+	query := `SELECT pull_request_id, pull_request_name, author_id, status, created_at, merged_at
+		FROM pull_requests
+		WHERE author_id = $1
+		AND ($2::text = '' OR status = $2::text)`
+
+	args := []any{filter.AuthorID, string(filter.Status)}
+
+	if filter.After != nil {
+		args = append(args, filter.After.Key, filter.After.ID)
+		query += `
+		AND (created_at, pull_request_id) < ($3::timestamptz, $4::text)`
+	}
+
+	args = append(args, filter.Limit)
+	query += fmt.Sprintf(`
+		ORDER BY created_at DESC, pull_request_id DESC
+		LIMIT $%d`, len(args))
+
+	var prs []*models.PullRequestDetails
+	if err := prr.db.SelectContext(ctx, &prs, query, args...); err != nil {
+		return nil, fmt.Errorf("error getting pull requests by author %s: %w", filter.AuthorID, err)
+	}
+
+	if err := prr.loadReviewers(ctx, prs); err != nil {
+		return nil, err
 	}
 
 	if prs == nil {
-		prs = []*models.PullRequestShort{}
+		prs = []*models.PullRequestDetails{}
 	}
 
 	return prs, nil
 }
+
+// заполняет Reviewers и AssignedReviewers одним запросом на все PR
+func (prr *PullRequestRepository) loadReviewers(ctx context.Context, prs []*models.PullRequestDetails) error {
+
+	if len(prs) == 0 {
+		return nil
+	}
+
+	prIDs := make([]string, 0, len(prs))
+	for _, pr := range prs {
+		prIDs = append(prIDs, pr.PullRequestID)
+	}
+
+	reviewersQuery := `SELECT rev.pull_request_id, rev.user_id, u.username, COALESCE(tm.team_name, '') AS team_name, u.is_active, rev.assigned_at,
+			GREATEST(EXTRACT(EPOCH FROM COALESCE(pr.merged_at, now()) - rev.assigned_at), 0)::bigint AS held_seconds
+		FROM pr_reviewers rev
+		INNER JOIN pull_requests pr ON pr.pull_request_id = rev.pull_request_id
+		INNER JOIN users u ON u.user_id = rev.user_id
+		LEFT JOIN team_memberships tm ON tm.user_id = u.user_id AND tm.is_primary
+		WHERE rev.pull_request_id = ANY($1)
+		ORDER BY rev.assigned_at, rev.user_id`
+
+	var reviewers []models.PullRequestReviewer
+	if err := prr.db.SelectContext(ctx, &reviewers, reviewersQuery, pq.Array(prIDs)); err != nil {
+		return fmt.Errorf("error getting pull request reviewers: %w", err)
+	}
+
+	byID := make(map[string]*models.PullRequestDetails, len(prs))
+	for _, pr := range prs {
+		pr.Reviewers = []models.PullRequestReviewer{}
+		pr.AssignedReviewers = []string{}
+		byID[pr.PullRequestID] = pr
+	}
+	for _, rev := range reviewers {
+		pr := byID[rev.PullRequestID]
+		pr.Reviewers = append(pr.Reviewers, rev)
+		pr.AssignedReviewers = append(pr.AssignedReviewers, rev.UserID)
+	}
+
+	return nil
+}
+
+// события PR в порядке записи
+func (prr *PullRequestRepository) GetPullRequestTimeline(ctx context.Context, prID string) ([]*models.PullRequestEvent, error) {
+
+	var exists bool
+	existsQuery := `SELECT EXISTS(SELECT 1 FROM pull_requests WHERE pull_request_id = $1)`
+	if err := prr.db.GetContext(ctx, &exists, existsQuery, prID); err != nil {
+		return nil, fmt.Errorf("error checking pull request: %w", err)
+	}
+	if !exists {
+		return nil, errs.ErrPullRequestNotFound
+	}
+
+	eventsQuery := `SELECT event_id, pull_request_id, event_type, user_id, reason, created_at
+		FROM pr_events
+		WHERE pull_request_id = $1
+		ORDER BY event_id`
+
+	var events []*models.PullRequestEvent
+	if err := prr.db.SelectContext(ctx, &events, eventsQuery, prID); err != nil {
+		return nil, fmt.Errorf("error getting pull request events: %w", err)
+	}
+
+	if events == nil {
+		events = []*models.PullRequestEvent{}
+	}
+
+	return events, nil
+}
@@ -0,0 +1,237 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/guarref/pr-service-assignment/internal/models"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// поддерево команды: сама команда и все её дочерние команды на любой глубине
+const teamSubtreeCTE = `WITH RECURSIVE subtree AS (
+		SELECT team_name FROM teams WHERE team_name = $1
+		UNION
+		SELECT t.team_name FROM teams t
+		INNER JOIN subtree s ON t.parent_team = s.team_name
+	)`
+
+// ограничение глубины обхода иерархии команд
+const maxTeamDepth = 32
+
+// возвращает активных кандидатов в ревьюверы из команды, сначала наименее загруженных: нагрузка — открытые ревью
+// во всех командах пользователя, при равной нагрузке порядок случайный;
+// если в команде никого не осталось, поднимается по родительским командам до первой непустой
+func findCandidates(ctx context.Context, tx *sqlx.Tx, teamName string, excludeUsers []string) ([]string, error) {
+
+	usersQuery := `SELECT u.user_id
+		FROM users u
+		INNER JOIN team_memberships tm ON tm.user_id = u.user_id
+		WHERE tm.team_name = $1
+		AND tm.is_active = true
+		AND u.is_active = true
+		AND u.user_id != ALL($2)
+		ORDER BY (
+			SELECT COUNT(*) FROM pr_reviewers rev
+			INNER JOIN pull_requests pr ON pr.pull_request_id = rev.pull_request_id
+			WHERE rev.user_id = u.user_id AND pr.status = 'OPEN'
+		), RANDOM()`
+
+	var users []string
+	if err := tx.SelectContext(ctx, &users, usersQuery, teamName, pq.Array(excludeUsers)); err != nil {
+		return nil, fmt.Errorf("error getting users: %w", err)
+	}
+	if len(users) > 0 {
+		return users, nil
+	}
+
+	ancestorsQuery := `WITH RECURSIVE ancestors AS (
+			SELECT parent_team AS team_name, 1 AS depth
+			FROM teams
+			WHERE team_name = $1 AND parent_team IS NOT NULL
+			UNION
+			SELECT t.parent_team, a.depth + 1
+			FROM teams t
+			INNER JOIN ancestors a ON t.team_name = a.team_name
+			WHERE t.parent_team IS NOT NULL AND a.depth < $2
+		)
+		SELECT team_name FROM ancestors ORDER BY depth`
+
+	var ancestors []string
+	if err := tx.SelectContext(ctx, &ancestors, ancestorsQuery, teamName, maxTeamDepth); err != nil {
+		return nil, fmt.Errorf("error getting parent teams of %s: %w", teamName, err)
+	}
+
+	for _, parent := range ancestors {
+		if err := tx.SelectContext(ctx, &users, usersQuery, parent, pq.Array(excludeUsers)); err != nil {
+			return nil, fmt.Errorf("error getting users of parent team %s: %w", parent, err)
+		}
+		if len(users) > 0 {
+			return users, nil
+		}
+	}
+
+	return []string{}, nil
+}
+
+// пишет событие в журнал pr_events в транзакции изменения; пустые userID и reason сохраняются как NULL
+func insertPullRequestEvent(ctx context.Context, tx *sqlx.Tx, prID string, eventType models.PullRequestEventType, userID string, reason models.PullRequestEventReason) error {
+
+	eventQuery := `INSERT INTO pr_events (pull_request_id, event_type, user_id, reason, created_at)
+		VALUES ($1, $2, NULLIF($3::text, ''), NULLIF($4::text, ''), NOW())`
+
+	if _, err := tx.ExecContext(ctx, eventQuery, prID, eventType, userID, reason); err != nil {
+		return fmt.Errorf("error writing %s event for PR %s: %w", eventType, prID, err)
+	}
+
+	return nil
+}
+
+// ревью пользователя в открытом PR, которое нужно снять
+type reviewToReplace struct {
+	PullRequestID string `db:"pull_request_id"`
+	AuthorID      string `db:"author_id"`
+	ReviewerID    string `db:"user_id"`
+}
+
+// снимает пользователей с ревью открытых PR и по возможности назначает замену
+// из активных участников основной команды автора (или ближайшей родительской команды); если кандидатов нет, ревьювер просто удаляется.
+// Возвращает число ревьюверов, снятых без замены
+func reassignOpenReviews(ctx context.Context, tx *sqlx.Tx, usersToDeactivate []string, reason models.PullRequestEventReason) (int, error) {
+
+	reviewsQuery := `SELECT pr.pull_request_id, pr.author_id, rev.user_id
+		FROM pull_requests pr
+		INNER JOIN pr_reviewers rev ON pr.pull_request_id = rev.pull_request_id
+		WHERE pr.status = 'OPEN'
+		AND rev.user_id = ANY($1)
+		ORDER BY pr.pull_request_id, rev.user_id`
+
+	var reviews []reviewToReplace
+	if err := tx.SelectContext(ctx, &reviews, reviewsQuery, pq.Array(usersToDeactivate)); err != nil {
+		return 0, fmt.Errorf("error getting affected PRs: %w", err)
+	}
+
+	return replaceReviewers(ctx, tx, reviews, usersToDeactivate, reason)
+}
+
+// снимает пользователей только с тех ревью, которые держались на их членстве в команде teamName:
+// команда входит в цепочку «основная команда автора и её родители», а других активных членств в этой цепочке
+// у ревьювера не осталось. Вызывается после того, как членства в teamName помечены неактивными
+func reassignTeamReviews(ctx context.Context, tx *sqlx.Tx, teamName string, userIDs []string, reason models.PullRequestEventReason) (int, error) {
+
+	reviewsQuery := `WITH RECURSIVE chain AS (
+			SELECT pr.pull_request_id, tm.team_name, 1 AS depth
+			FROM pull_requests pr
+			INNER JOIN team_memberships tm ON tm.user_id = pr.author_id AND tm.is_primary
+			WHERE pr.status = 'OPEN'
+			AND EXISTS (
+				SELECT 1 FROM pr_reviewers r
+				WHERE r.pull_request_id = pr.pull_request_id AND r.user_id = ANY($2)
+			)
+			UNION
+			SELECT c.pull_request_id, t.parent_team, c.depth + 1
+			FROM chain c
+			INNER JOIN teams t ON t.team_name = c.team_name
+			WHERE t.parent_team IS NOT NULL AND c.depth < $3
+		)
+		SELECT pr.pull_request_id, pr.author_id, rev.user_id
+		FROM pull_requests pr
+		INNER JOIN pr_reviewers rev ON pr.pull_request_id = rev.pull_request_id
+		WHERE pr.status = 'OPEN'
+		AND rev.user_id = ANY($2)
+		AND EXISTS (
+			SELECT 1 FROM chain c
+			WHERE c.pull_request_id = pr.pull_request_id AND c.team_name = $1
+		)
+		AND NOT EXISTS (
+			SELECT 1 FROM chain c
+			INNER JOIN team_memberships tm ON tm.team_name = c.team_name
+			WHERE c.pull_request_id = pr.pull_request_id
+			AND tm.user_id = rev.user_id
+			AND tm.is_active = true
+		)
+		ORDER BY pr.pull_request_id, rev.user_id`
+
+	var reviews []reviewToReplace
+	if err := tx.SelectContext(ctx, &reviews, reviewsQuery, teamName, pq.Array(userIDs), maxTeamDepth); err != nil {
+		return 0, fmt.Errorf("error getting affected PRs of team %s: %w", teamName, err)
+	}
+
+	return replaceReviewers(ctx, tx, reviews, userIDs, reason)
+}
+
+// reviews отсортированы по PR; excludeUsers не назначаются заменой.
+// Возвращает число ревьюверов, для которых замены не нашлось
+func replaceReviewers(ctx context.Context, tx *sqlx.Tx, reviews []reviewToReplace, excludeUsers []string, reason models.PullRequestEventReason) (int, error) {
+
+	dropped := 0
+
+	for start := 0; start < len(reviews); {
+		end := start
+		for end < len(reviews) && reviews[end].PullRequestID == reviews[start].PullRequestID {
+			end++
+		}
+
+		prID, authorID := reviews[start].PullRequestID, reviews[start].AuthorID
+
+		var currentReviewers []string
+		reviewersQuery := `SELECT user_id FROM pr_reviewers WHERE pull_request_id = $1`
+		if err := tx.SelectContext(ctx, &currentReviewers, reviewersQuery, prID); err != nil {
+			return 0, fmt.Errorf("error getting current reviewers for PR %s: %w", prID, err)
+		}
+
+		var authorTeam string
+		teamQuery := `SELECT team_name FROM team_memberships WHERE user_id = $1 AND is_primary`
+		if err := tx.GetContext(ctx, &authorTeam, teamQuery, authorID); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				// у автора не осталось команд — заменить некем, ревьюверы просто снимаются
+				authorTeam = ""
+			} else {
+				return 0, fmt.Errorf("error getting author team: %w", err)
+			}
+		}
+
+		exclude := append(currentReviewers, authorID)
+		exclude = append(exclude, excludeUsers...)
+
+		users, err := findCandidates(ctx, tx, authorTeam, exclude)
+		if err != nil {
+			return 0, err
+		}
+
+		userIdx := 0
+		for _, review := range reviews[start:end] {
+			deleteQuery := `DELETE FROM pr_reviewers WHERE pull_request_id = $1 AND user_id = $2`
+
+			if _, err := tx.ExecContext(ctx, deleteQuery, prID, review.ReviewerID); err != nil {
+				return 0, fmt.Errorf("error removing reviewer %s from PR %s: %w", review.ReviewerID, prID, err)
+			}
+			if err := insertPullRequestEvent(ctx, tx, prID, models.PullRequestEventReviewerRemoved, review.ReviewerID, reason); err != nil {
+				return 0, err
+			}
+
+			if userIdx < len(users) {
+				newReviewerID := users[userIdx]
+				userIdx++
+
+				insertQuery := `INSERT INTO pr_reviewers (pull_request_id, user_id, assigned_at) VALUES ($1, $2, NOW())`
+
+				if _, err := tx.ExecContext(ctx, insertQuery, prID, newReviewerID); err != nil {
+					return 0, fmt.Errorf("error assigning new reviewer %s to PR %s: %w", newReviewerID, prID, err)
+				}
+				if err := insertPullRequestEvent(ctx, tx, prID, models.PullRequestEventReviewerAssigned, newReviewerID, reason); err != nil {
+					return 0, err
+				}
+			} else {
+				dropped++
+			}
+		}
+
+		start = end
+	}
+
+	return dropped, nil
+}
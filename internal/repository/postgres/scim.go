@@ -0,0 +1,374 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/guarref/pr-service-assignment/internal/errs"
+	"github.com/guarref/pr-service-assignment/internal/models"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+type ScimRepository struct {
+	db *sqlx.DB
+}
+
+func NewScimRepository(db *sqlx.DB) *ScimRepository {
+	return &ScimRepository{db: db}
+}
+
+// участники группы SCIM — только активные членства
+const scimMembersQuery = `SELECT u.user_id, u.username, u.is_active, tm.is_primary, tm.role
+	FROM team_memberships tm
+	INNER JOIN users u ON u.user_id = tm.user_id
+	WHERE tm.team_name = $1 AND tm.is_active
+	ORDER BY u.user_id`
+
+type scimUserRow struct {
+	models.User
+	Teams pq.StringArray `db:"teams"`
+	Total int            `db:"total"`
+}
+
+func (sr *ScimRepository) ListUsers(ctx context.Context, filter models.ScimUserFilter) ([]*models.User, int, error) {
+
+	listQuery := `SELECT u.user_id, u.username, COALESCE(tm.team_name, '') AS team_name, u.is_active, u.created_at, u.updated_at,
+			ARRAY(SELECT m.team_name FROM team_memberships m WHERE m.user_id = u.user_id AND m.is_active
+				ORDER BY m.is_primary DESC, m.team_name) AS teams,
+			COUNT(*) OVER() AS total
+		FROM users u
+		LEFT JOIN team_memberships tm ON tm.user_id = u.user_id AND tm.is_primary
+		WHERE ($1::text = '' OR u.user_id = $1::text)
+		AND ($2::text = '' OR u.username = $2::text)
+		AND ($3::boolean IS NULL OR u.is_active = $3::boolean)
+		ORDER BY u.user_id
+		OFFSET $4 LIMIT $5`
+
+	var rows []scimUserRow
+	if err := sr.db.SelectContext(ctx, &rows, listQuery, filter.UserID, filter.UserName, filter.IsActive, filter.Offset, filter.Limit); err != nil {
+		return nil, 0, fmt.Errorf("error listing scim users: %w", err)
+	}
+
+	users := make([]*models.User, 0, len(rows))
+	total := 0
+	for _, row := range rows {
+		user := row.User
+		user.Teams = []string(row.Teams)
+		users = append(users, &user)
+		total = row.Total
+	}
+
+	// на пустой странице (в том числе при count=0) COUNT(*) OVER() не вернётся, считаем отдельно
+	if len(rows) == 0 {
+		countQuery := `SELECT COUNT(*)
+			FROM users u
+			WHERE ($1::text = '' OR u.user_id = $1::text)
+			AND ($2::text = '' OR u.username = $2::text)
+			AND ($3::boolean IS NULL OR u.is_active = $3::boolean)`
+
+		if err := sr.db.GetContext(ctx, &total, countQuery, filter.UserID, filter.UserName, filter.IsActive); err != nil {
+			return nil, 0, fmt.Errorf("error counting scim users: %w", err)
+		}
+	}
+
+	return users, total, nil
+}
+
+func (sr *ScimRepository) GetUser(ctx context.Context, userID string) (*models.User, error) {
+
+	users, _, err := sr.ListUsers(ctx, models.ScimUserFilter{UserID: userID, Limit: 1})
+	if err != nil {
+		return nil, err
+	}
+	if len(users) == 0 {
+		return nil, errs.ErrUserNotFound
+	}
+
+	return users[0], nil
+}
+
+func (sr *ScimRepository) CreateUser(ctx context.Context, user *models.User) error {
+
+	creationUserQuery := `INSERT INTO users (user_id, username, is_active, created_at, updated_at)
+		VALUES ($1, $2, $3, NOW(), NOW())
+		ON CONFLICT (user_id) DO NOTHING`
+
+	res, err := sr.db.ExecContext(ctx, creationUserQuery, user.UserID, user.UserName, user.IsActive)
+	if err != nil {
+		return fmt.Errorf("error creating scim user: %w", err)
+	}
+
+	created, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error getting rows affected: %w", err)
+	}
+	if created == 0 {
+		return errs.ErrUserExists
+	}
+
+	return nil
+}
+
+// деактивация через SCIM переназначает открытые ревью так же, как /team/deactivate
+func (sr *ScimRepository) UpdateUser(ctx context.Context, userID string, userName string, isActive bool) (int, error) {
+
+	tx, err := sr.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("error begining transaction scim_user: %w", err)
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil && !errors.Is(err, sql.ErrTxDone) {
+			log.Printf("rollback error: %v", err)
+		}
+	}()
+
+	var wasActive bool
+	lockQuery := `SELECT is_active FROM users WHERE user_id = $1 FOR UPDATE`
+	if err := tx.GetContext(ctx, &wasActive, lockQuery, userID); err != nil {
+		if err == sql.ErrNoRows {
+			return 0, errs.ErrUserNotFound
+		}
+		return 0, fmt.Errorf("error getting user: %w", err)
+	}
+
+	dropped := 0
+	if wasActive && !isActive {
+		if dropped, err = reassignOpenReviews(ctx, tx, []string{userID}, models.EventReasonDeactivation); err != nil {
+			return 0, err
+		}
+	}
+
+	updateQuery := `UPDATE users
+		SET username = $2, is_active = $3, updated_at = NOW()
+		WHERE user_id = $1`
+	if _, err := tx.ExecContext(ctx, updateQuery, userID, userName, isActive); err != nil {
+		return 0, fmt.Errorf("error updating scim user: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("error committing transaction scim_user: %w", err)
+	}
+
+	return dropped, nil
+}
+
+type scimGroupRow struct {
+	models.Team
+	Total int `db:"total"`
+}
+
+func (sr *ScimRepository) ListGroups(ctx context.Context, filter models.ScimGroupFilter) ([]*models.Team, int, error) {
+
+	listQuery := `SELECT t.team_name, t.parent_team, t.is_active, t.archived_at, t.created_at, t.updated_at,
+			COUNT(*) OVER() AS total
+		FROM teams t
+		WHERE ($1::text = '' OR t.team_name = $1::text)
+		AND ($2::text = '' OR EXISTS(
+			SELECT 1 FROM team_memberships f WHERE f.team_name = t.team_name AND f.user_id = $2::text AND f.is_active
+		))
+		ORDER BY t.team_name
+		OFFSET $3 LIMIT $4`
+
+	var rows []scimGroupRow
+	if err := sr.db.SelectContext(ctx, &rows, listQuery, filter.TeamName, filter.MemberID, filter.Offset, filter.Limit); err != nil {
+		return nil, 0, fmt.Errorf("error listing scim groups: %w", err)
+	}
+
+	teams := make([]*models.Team, 0, len(rows))
+	total := 0
+	for _, row := range rows {
+		team := row.Team
+
+		var members []models.TeamMember
+		if err := sr.db.SelectContext(ctx, &members, scimMembersQuery, team.TeamName); err != nil {
+			return nil, 0, fmt.Errorf("error getting team members: %w", err)
+		}
+		if members == nil {
+			members = []models.TeamMember{}
+		}
+		team.Members = members
+
+		teams = append(teams, &team)
+		total = row.Total
+	}
+
+	if len(rows) == 0 {
+		countQuery := `SELECT COUNT(*)
+			FROM teams t
+			WHERE ($1::text = '' OR t.team_name = $1::text)
+			AND ($2::text = '' OR EXISTS(
+				SELECT 1 FROM team_memberships f WHERE f.team_name = t.team_name AND f.user_id = $2::text AND f.is_active
+			))`
+
+		if err := sr.db.GetContext(ctx, &total, countQuery, filter.TeamName, filter.MemberID); err != nil {
+			return nil, 0, fmt.Errorf("error counting scim groups: %w", err)
+		}
+	}
+
+	return teams, total, nil
+}
+
+func (sr *ScimRepository) GetGroup(ctx context.Context, teamName string) (*models.Team, error) {
+
+	teams, _, err := sr.ListGroups(ctx, models.ScimGroupFilter{TeamName: teamName, Limit: 1})
+	if err != nil {
+		return nil, err
+	}
+	if len(teams) == 0 {
+		return nil, errs.ErrTeamNotFound
+	}
+
+	return teams[0], nil
+}
+
+func (sr *ScimRepository) CreateGroup(ctx context.Context, teamName string, memberIDs []string) error {
+
+	tx, err := sr.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("error begining transaction scim_group: %w", err)
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil && !errors.Is(err, sql.ErrTxDone) {
+			log.Printf("rollback error: %v", err)
+		}
+	}()
+
+	creationTeamQuery := `INSERT INTO teams (team_name, created_at, updated_at) VALUES ($1, NOW(), NOW())
+		ON CONFLICT (team_name) DO NOTHING`
+
+	res, err := tx.ExecContext(ctx, creationTeamQuery, teamName)
+	if err != nil {
+		return fmt.Errorf("error creating scim group: %w", err)
+	}
+
+	created, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error getting rows affected: %w", err)
+	}
+	if created == 0 {
+		return errs.ErrTeamExists
+	}
+
+	if err := addScimMembers(ctx, tx, teamName, memberIDs); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error committing transaction scim_group: %w", err)
+	}
+
+	return nil
+}
+
+// удалённое из группы членство стирается, основной становится другая команда пользователя,
+// а ревью, которые держались на этом членстве, переназначаются; состав архивной команды не меняется
+func (sr *ScimRepository) UpdateGroupMembers(ctx context.Context, teamName string, update models.ScimMembersUpdate) (int, error) {
+
+	tx, err := sr.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("error begining transaction scim_group: %w", err)
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil && !errors.Is(err, sql.ErrTxDone) {
+			log.Printf("rollback error: %v", err)
+		}
+	}()
+
+	var isActive bool
+	lockQuery := `SELECT is_active FROM teams WHERE team_name = $1 FOR UPDATE`
+	if err := tx.GetContext(ctx, &isActive, lockQuery, teamName); err != nil {
+		if err == sql.ErrNoRows {
+			return 0, errs.ErrTeamNotFound
+		}
+		return 0, fmt.Errorf("error getting team: %w", err)
+	}
+	if !isActive {
+		return 0, errs.ErrTeamArchived
+	}
+
+	remove := update.Remove
+	if update.Replace {
+		keep := update.Add
+		if keep == nil {
+			keep = []string{}
+		}
+
+		staleQuery := `SELECT user_id FROM team_memberships WHERE team_name = $1 AND NOT (user_id = ANY($2))`
+		if err := tx.SelectContext(ctx, &remove, staleQuery, teamName, pq.Array(keep)); err != nil {
+			return 0, fmt.Errorf("error getting stale members: %w", err)
+		}
+	}
+
+	dropped := 0
+	if len(remove) > 0 {
+		removeQuery := `DELETE FROM team_memberships WHERE team_name = $1 AND user_id = ANY($2)`
+		if _, err := tx.ExecContext(ctx, removeQuery, teamName, pq.Array(remove)); err != nil {
+			return 0, fmt.Errorf("error removing members: %w", err)
+		}
+
+		promotePrimaryQuery := `UPDATE team_memberships tm
+			SET is_primary = true, updated_at = NOW()
+			FROM (
+				SELECT DISTINCT ON (user_id) user_id, team_name
+				FROM team_memberships
+				WHERE user_id = ANY($1)
+				ORDER BY user_id, is_active DESC, created_at, team_name
+			) next
+			WHERE tm.user_id = next.user_id AND tm.team_name = next.team_name
+			AND NOT EXISTS(SELECT 1 FROM team_memberships p WHERE p.user_id = tm.user_id AND p.is_primary)`
+
+		if _, err := tx.ExecContext(ctx, promotePrimaryQuery, pq.Array(remove)); err != nil {
+			return 0, fmt.Errorf("error promoting primary teams: %w", err)
+		}
+
+		if dropped, err = reassignTeamReviews(ctx, tx, teamName, remove, models.EventReasonDeactivation); err != nil {
+			return 0, err
+		}
+	}
+
+	if err := addScimMembers(ctx, tx, teamName, update.Add); err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("error committing transaction scim_group: %w", err)
+	}
+
+	return dropped, nil
+}
+
+// добавляет (или снова включает) членства; все пользователи должны существовать
+func addScimMembers(ctx context.Context, tx *sqlx.Tx, teamName string, userIDs []string) error {
+
+	if len(userIDs) == 0 {
+		return nil
+	}
+
+	var found int
+	existsQuery := `SELECT COUNT(*) FROM users WHERE user_id = ANY($1)`
+	if err := tx.GetContext(ctx, &found, existsQuery, pq.Array(userIDs)); err != nil {
+		return fmt.Errorf("error checking for users existence: %w", err)
+	}
+	if found != len(userIDs) {
+		return errs.ErrUserNotFound
+	}
+
+	additionMembershipQuery := `INSERT INTO team_memberships (user_id, team_name, is_primary, is_active, created_at, updated_at)
+		VALUES ($1, $2, NOT EXISTS(SELECT 1 FROM team_memberships WHERE user_id = $1 AND is_primary), true, NOW(), NOW())
+		ON CONFLICT (user_id, team_name)
+		DO UPDATE SET
+		is_active  = true,
+		updated_at = NOW()`
+
+	for _, userID := range userIDs {
+		if _, err := tx.ExecContext(ctx, additionMembershipQuery, userID, teamName); err != nil {
+			return fmt.Errorf("error adding membership of user %s: %w", userID, err)
+		}
+	}
+
+	return nil
+}
@@ -2,10 +2,16 @@ package postgres
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"fmt"
+	"log"
+	"time"
 
+	"github.com/guarref/pr-service-assignment/internal/errs"
 	"github.com/guarref/pr-service-assignment/internal/models"
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 )
 
 type StatsRepository struct {
@@ -16,30 +22,99 @@ func NewStatsRepository(db *sqlx.DB) *StatsRepository {
 	return &StatsRepository{db: db}
 }
 
-func (sr *StatsRepository) GetStats(ctx context.Context, top int) (*models.Stats, error) {
+// условие на вхождение колонки в период; значения границ добавляются в args.
+// условие собирается только из заданных границ, чтобы запрос мог использовать индекс по колонке
+func windowCondition(column string, window models.StatsWindow, args []any) (string, []any) {
+
+	var cond string
+	if window.From != nil {
+		args = append(args, *window.From)
+		cond += fmt.Sprintf(" AND %s >= $%d", column, len(args))
+	}
+	if window.To != nil {
+		args = append(args, *window.To)
+		cond += fmt.Sprintf(" AND %s < $%d", column, len(args))
+	}
+
+	return cond, args
+}
+
+// если передано имя команды, статистика считается по всему её поддереву (сама команда и все дочерние);
+// PR ограничиваются периодом по created_at (смерженные — по merged_at), ревью — по assigned_at.
+// Без фильтров данные читаются из материализованных представлений, обновляемых RefreshStatsSummary
+func (sr *StatsRepository) GetStats(ctx context.Context, top int, teamName string, window models.StatsWindow) (*models.Stats, error) {
+
+	if teamName != "" {
+		return sr.getTeamTreeStats(ctx, top, teamName, window)
+	}
+	if window.From == nil && window.To == nil {
+		return sr.getSummaryStats(ctx, top)
+	}
 
 	var stats models.Stats
 
+	createdCond, args := windowCondition("created_at", window, nil)
+	mergedCond, args := windowCondition("merged_at", window, args)
+
 	countsQuery := `SELECT
     	(SELECT COUNT(*) FROM teams) AS total_teams,
     	(SELECT COUNT(*) FROM users) AS total_users,
     	(SELECT COUNT(*) FROM users WHERE is_active = TRUE) AS active_users,
-    	(SELECT COUNT(*) FROM pull_requests) AS total_pull_requests,
-    	(SELECT COUNT(*) FROM pull_requests WHERE status = 'OPEN') AS open_pull_requests`
+    	(SELECT COUNT(*) FROM pull_requests WHERE TRUE` + createdCond + `) AS total_pull_requests,
+    	(SELECT COUNT(*) FROM pull_requests WHERE status = 'OPEN'` + createdCond + `) AS open_pull_requests,
+    	(SELECT COUNT(*) FROM pull_requests WHERE merged_at IS NOT NULL` + mergedCond + `) AS merged_pull_requests,
+    	now() AS as_of`
 
-	if err := sr.db.GetContext(ctx, &stats, countsQuery); err != nil {
+	if err := sr.db.GetContext(ctx, &stats, countsQuery, args...); err != nil {
 		return nil, fmt.Errorf("error getting stats: %w", err)
 	}
 
+	assignedCond, args := windowCondition("prr.assigned_at", window, []any{top})
+
 	topReviewersQuery := `SELECT u.user_id, u.username, COUNT(DISTINCT prr.pull_request_id) AS review_count
 		FROM users u
 		JOIN pr_reviewers prr ON u.user_id = prr.user_id
+		WHERE TRUE` + assignedCond + `
 		GROUP BY u.user_id, u.username
 		ORDER BY review_count DESC, u.user_id
 		LIMIT $1`
 
 	var reviewers []*models.TopReviewer
 
+	if err := sr.db.SelectContext(ctx, &reviewers, topReviewersQuery, args...); err != nil {
+		return nil, fmt.Errorf("error getting top reviewers: %w", err)
+	}
+
+	if reviewers == nil {
+		reviewers = []*models.TopReviewer{}
+	}
+
+	stats.TopReviewers = reviewers
+
+	return &stats, nil
+}
+
+func (sr *StatsRepository) getSummaryStats(ctx context.Context, top int) (*models.Stats, error) {
+
+	var stats models.Stats
+
+	summaryQuery := `SELECT total_teams, total_users, active_users, total_pull_requests,
+			open_pull_requests, merged_pull_requests, refreshed_at AS as_of
+		FROM stats_summary`
+
+	if err := sr.db.GetContext(ctx, &stats, summaryQuery); err != nil {
+		return nil, fmt.Errorf("error getting stats summary: %w", err)
+	}
+
+	// имя берётся из users, чтобы обезличенные пользователи не показывались под прежним именем до обновления
+	topReviewersQuery := `SELECT c.user_id, u.username, c.review_count
+		FROM stats_reviewer_counts c
+		INNER JOIN users u ON u.user_id = c.user_id
+		ORDER BY c.review_count DESC, c.user_id
+		LIMIT $1`
+
+	var reviewers []*models.TopReviewer
+
 	if err := sr.db.SelectContext(ctx, &reviewers, topReviewersQuery, top); err != nil {
 		return nil, fmt.Errorf("error getting top reviewers: %w", err)
 	}
@@ -52,3 +127,619 @@ func (sr *StatsRepository) GetStats(ctx context.Context, top int) (*models.Stats
 
 	return &stats, nil
 }
+
+// CONCURRENTLY не блокирует чтение представлений на время пересчёта
+func (sr *StatsRepository) RefreshStatsSummary(ctx context.Context) error {
+
+	for _, view := range []string{"stats_summary", "stats_reviewer_counts", "stats_event_counts"} {
+		if _, err := sr.db.ExecContext(ctx, "REFRESH MATERIALIZED VIEW CONCURRENTLY "+view); err != nil {
+			return fmt.Errorf("error refreshing %s: %w", view, err)
+		}
+	}
+
+	return nil
+}
+
+func (sr *StatsRepository) getTeamTreeStats(ctx context.Context, top int, teamName string, window models.StatsWindow) (*models.Stats, error) {
+
+	var isExists bool
+	checkTeamQuery := `SELECT EXISTS(SELECT 1 FROM teams WHERE team_name = $1)`
+
+	if err := sr.db.GetContext(ctx, &isExists, checkTeamQuery, teamName); err != nil {
+		return nil, fmt.Errorf("error checking for team existence: %w", err)
+	}
+	if !isExists {
+		return nil, errs.ErrTeamNotFound
+	}
+
+	var stats models.Stats
+
+	createdCond, args := windowCondition("created_at", window, []any{teamName})
+	mergedCond, args := windowCondition("merged_at", window, args)
+
+	countsQuery := teamSubtreeCTE + `,
+	tree_users AS (
+		SELECT DISTINCT u.user_id, u.is_active FROM users u
+		INNER JOIN team_memberships tm ON tm.user_id = u.user_id
+		INNER JOIN subtree s ON tm.team_name = s.team_name
+	),
+	tree_prs AS (
+		SELECT pr.status, pr.created_at, pr.merged_at FROM pull_requests pr
+		INNER JOIN tree_users tu ON pr.author_id = tu.user_id
+	)
+	SELECT
+		(SELECT COUNT(*) FROM subtree) AS total_teams,
+		(SELECT COUNT(*) FROM tree_users) AS total_users,
+		(SELECT COUNT(*) FROM tree_users WHERE is_active = TRUE) AS active_users,
+		(SELECT COUNT(*) FROM tree_prs WHERE TRUE` + createdCond + `) AS total_pull_requests,
+		(SELECT COUNT(*) FROM tree_prs WHERE status = 'OPEN'` + createdCond + `) AS open_pull_requests,
+		(SELECT COUNT(*) FROM tree_prs WHERE merged_at IS NOT NULL` + mergedCond + `) AS merged_pull_requests,
+		now() AS as_of`
+
+	if err := sr.db.GetContext(ctx, &stats, countsQuery, args...); err != nil {
+		return nil, fmt.Errorf("error getting stats for team %s: %w", teamName, err)
+	}
+
+	assignedCond, args := windowCondition("prr.assigned_at", window, []any{teamName, top})
+
+	topReviewersQuery := teamSubtreeCTE + `
+		SELECT u.user_id, u.username, COUNT(DISTINCT prr.pull_request_id) AS review_count
+		FROM users u
+		JOIN pr_reviewers prr ON u.user_id = prr.user_id
+		WHERE EXISTS(
+			SELECT 1 FROM team_memberships tm
+			INNER JOIN subtree s ON tm.team_name = s.team_name
+			WHERE tm.user_id = u.user_id
+		)` + assignedCond + `
+		GROUP BY u.user_id, u.username
+		ORDER BY review_count DESC, u.user_id
+		LIMIT $2`
+
+	var reviewers []*models.TopReviewer
+
+	if err := sr.db.SelectContext(ctx, &reviewers, topReviewersQuery, args...); err != nil {
+		return nil, fmt.Errorf("error getting top reviewers for team %s: %w", teamName, err)
+	}
+
+	if reviewers == nil {
+		reviewers = []*models.TopReviewer{}
+	}
+
+	stats.TopReviewers = reviewers
+
+	return &stats, nil
+}
+
+// PR команды — PR, авторы которых состоят в команде; счётчики ревью участников учитывают все их назначения,
+// в том числе на PR других команд
+func (sr *StatsRepository) GetTeamStats(ctx context.Context, teamName string, requiredReviewers int) (*models.TeamStats, error) {
+
+	var isExists bool
+	checkTeamQuery := `SELECT EXISTS(SELECT 1 FROM teams WHERE team_name = $1)`
+
+	if err := sr.db.GetContext(ctx, &isExists, checkTeamQuery, teamName); err != nil {
+		return nil, fmt.Errorf("error checking for team existence: %w", err)
+	}
+	if !isExists {
+		return nil, errs.ErrTeamNotFound
+	}
+
+	var stats models.TeamStats
+
+	countsQuery := `WITH team_users AS (
+		SELECT u.user_id, (u.is_active AND tm.is_active) AS is_active
+		FROM team_memberships tm
+		INNER JOIN users u ON u.user_id = tm.user_id
+		WHERE tm.team_name = $1
+	),
+	team_prs AS (
+		SELECT pr.status,
+			(SELECT COUNT(*) FROM pr_reviewers rev WHERE rev.pull_request_id = pr.pull_request_id) AS reviewers
+		FROM pull_requests pr
+		WHERE pr.author_id IN (SELECT user_id FROM team_users)
+	)
+	SELECT
+		(SELECT COUNT(*) FROM team_prs WHERE status = 'OPEN') AS open_pull_requests,
+		(SELECT COUNT(*) FROM team_prs WHERE status = 'MERGED') AS merged_pull_requests,
+		(SELECT COUNT(*) FROM team_users) AS total_members,
+		(SELECT COUNT(*) FROM team_users WHERE is_active = TRUE) AS active_members,
+		(SELECT COALESCE(AVG(reviewers), 0)::float8 FROM team_prs) AS avg_reviewers_per_pr,
+		(SELECT COUNT(*) FROM team_prs WHERE status = 'OPEN' AND reviewers < $2) AS understaffed_pull_requests`
+
+	if err := sr.db.GetContext(ctx, &stats, countsQuery, teamName, requiredReviewers); err != nil {
+		return nil, fmt.Errorf("error getting stats for team %s: %w", teamName, err)
+	}
+
+	membersQuery := `SELECT u.user_id, u.username, (u.is_active AND tm.is_active) AS is_active,
+			COUNT(pr.pull_request_id) FILTER (WHERE pr.status = 'OPEN') AS open_reviews,
+			COUNT(pr.pull_request_id) AS total_reviews
+		FROM team_memberships tm
+		INNER JOIN users u ON u.user_id = tm.user_id
+		LEFT JOIN pr_reviewers rev ON rev.user_id = u.user_id
+		LEFT JOIN pull_requests pr ON pr.pull_request_id = rev.pull_request_id
+		WHERE tm.team_name = $1
+		GROUP BY u.user_id, u.username, u.is_active, tm.is_active
+		ORDER BY u.username, u.user_id`
+
+	var members []*models.TeamMemberStats
+
+	if err := sr.db.SelectContext(ctx, &members, membersQuery, teamName); err != nil {
+		return nil, fmt.Errorf("error getting member stats for team %s: %w", teamName, err)
+	}
+
+	if members == nil {
+		members = []*models.TeamMemberStats{}
+	}
+
+	stats.TeamName = teamName
+	stats.RequiredReviewers = requiredReviewers
+	stats.Members = members
+
+	return &stats, nil
+}
+
+// смерженные в периоде PR и длительности по ним: merge_times — от создания до merge,
+// hold_times — от назначения каждого текущего ревьювера до merge
+const latencyCTE = `WITH merged AS (
+		SELECT pull_request_id, author_id, created_at, merged_at FROM pull_requests
+		WHERE merged_at IS NOT NULL%s
+	),
+	merge_times AS (
+		SELECT pull_request_id, author_id, EXTRACT(EPOCH FROM merged_at - created_at)::float8 AS seconds
+		FROM merged
+	),
+	hold_times AS (
+		SELECT m.pull_request_id, m.author_id, rev.user_id,
+			GREATEST(EXTRACT(EPOCH FROM m.merged_at - rev.assigned_at), 0)::float8 AS seconds
+		FROM merged m
+		INNER JOIN pr_reviewers rev ON rev.pull_request_id = m.pull_request_id
+	)`
+
+const latencyPercentiles = `COUNT(*) AS count,
+		COALESCE(percentile_cont(0.5) WITHIN GROUP (ORDER BY seconds), 0) AS p50,
+		COALESCE(percentile_cont(0.9) WITHIN GROUP (ORDER BY seconds), 0) AS p90,
+		COALESCE(percentile_cont(0.99) WITHIN GROUP (ORDER BY seconds), 0) AS p99`
+
+type latencyRow struct {
+	Metric   string `db:"metric"`
+	Key      string `db:"key"`
+	UserName string `db:"username"`
+	models.LatencyPercentiles
+}
+
+// PR попадает в период по merged_at; PR команды — PR, автор которых состоит в команде
+func (sr *StatsRepository) GetLatencyStats(ctx context.Context, window models.StatsWindow) (*models.LatencyStats, error) {
+
+	mergedCond, args := windowCondition("merged_at", window, nil)
+	cte := fmt.Sprintf(latencyCTE, mergedCond)
+
+	overallQuery := cte + `
+	SELECT 'time_to_merge' AS metric, '' AS key, '' AS username, ` + latencyPercentiles + `
+	FROM merge_times
+	UNION ALL
+	SELECT 'assigned_to_merge', '', '', ` + latencyPercentiles + `
+	FROM hold_times`
+
+	var overall []latencyRow
+
+	if err := sr.db.SelectContext(ctx, &overall, overallQuery, args...); err != nil {
+		return nil, fmt.Errorf("error getting latency stats: %w", err)
+	}
+
+	var stats models.LatencyStats
+
+	for _, row := range overall {
+		if row.Metric == "time_to_merge" {
+			stats.TimeToMerge = row.LatencyPercentiles
+		} else {
+			stats.AssignedToMerge = row.LatencyPercentiles
+		}
+	}
+
+	teamsQuery := cte + `
+	SELECT 'time_to_merge' AS metric, tm.team_name AS key, '' AS username, ` + latencyPercentiles + `
+	FROM merge_times t
+	INNER JOIN team_memberships tm ON tm.user_id = t.author_id
+	GROUP BY tm.team_name
+	UNION ALL
+	SELECT 'assigned_to_merge', tm.team_name, '', ` + latencyPercentiles + `
+	FROM hold_times t
+	INNER JOIN team_memberships tm ON tm.user_id = t.author_id
+	GROUP BY tm.team_name
+	ORDER BY key, metric`
+
+	var teamRows []latencyRow
+
+	if err := sr.db.SelectContext(ctx, &teamRows, teamsQuery, args...); err != nil {
+		return nil, fmt.Errorf("error getting team latency stats: %w", err)
+	}
+
+	stats.Teams = []*models.TeamLatency{}
+
+	for _, row := range teamRows {
+		if len(stats.Teams) == 0 || stats.Teams[len(stats.Teams)-1].TeamName != row.Key {
+			stats.Teams = append(stats.Teams, &models.TeamLatency{TeamName: row.Key})
+		}
+
+		team := stats.Teams[len(stats.Teams)-1]
+		if row.Metric == "time_to_merge" {
+			team.TimeToMerge = row.LatencyPercentiles
+		} else {
+			team.AssignedToMerge = row.LatencyPercentiles
+		}
+	}
+
+	reviewersQuery := cte + `
+	SELECT 'assigned_to_merge' AS metric, u.user_id AS key, u.username, ` + latencyPercentiles + `
+	FROM hold_times t
+	INNER JOIN users u ON u.user_id = t.user_id
+	GROUP BY u.user_id, u.username
+	ORDER BY u.username, u.user_id`
+
+	var reviewerRows []latencyRow
+
+	if err := sr.db.SelectContext(ctx, &reviewerRows, reviewersQuery, args...); err != nil {
+		return nil, fmt.Errorf("error getting reviewer latency stats: %w", err)
+	}
+
+	stats.Reviewers = make([]*models.ReviewerLatency, 0, len(reviewerRows))
+
+	for _, row := range reviewerRows {
+		stats.Reviewers = append(stats.Reviewers, &models.ReviewerLatency{
+			UserID:          row.Key,
+			UserName:        row.UserName,
+			AssignedToMerge: row.LatencyPercentiles,
+		})
+	}
+
+	return &stats, nil
+}
+
+// нагрузка считается по событиям reviewer_assigned за период (включая назначения, которые потом сняли)
+// на PR, автор которых состоит в той же команде. В распределение входят только активные участники,
+// в том числе без назначений; без имени команды — все неархивные команды
+func (sr *StatsRepository) GetReviewerLoads(ctx context.Context, teamName string, window models.StatsWindow) ([]*models.ReviewerLoad, error) {
+
+	args := []any{teamName}
+
+	if teamName != "" {
+		var isExists bool
+		checkTeamQuery := `SELECT EXISTS(SELECT 1 FROM teams WHERE team_name = $1)`
+
+		if err := sr.db.GetContext(ctx, &isExists, checkTeamQuery, teamName); err != nil {
+			return nil, fmt.Errorf("error checking for team existence: %w", err)
+		}
+		if !isExists {
+			return nil, errs.ErrTeamNotFound
+		}
+	}
+
+	assignedCond, args := windowCondition("e.created_at", window, args)
+
+	loadsQuery := `WITH members AS (
+		SELECT tm.team_name, u.user_id, u.username
+		FROM team_memberships tm
+		INNER JOIN users u ON u.user_id = tm.user_id
+		INNER JOIN teams t ON t.team_name = tm.team_name
+		WHERE u.is_active = TRUE AND tm.is_active = TRUE
+			AND (($1::text = '' AND t.archived_at IS NULL) OR t.team_name = $1)
+	),
+	assignments AS (
+		SELECT e.user_id, e.pull_request_id FROM pr_events e
+		WHERE e.event_type = 'reviewer_assigned'` + assignedCond + `
+	)
+	SELECT m.team_name, m.user_id, m.username, COUNT(a.pull_request_id) AS assignments
+	FROM members m
+	LEFT JOIN assignments a ON a.user_id = m.user_id AND EXISTS(
+		SELECT 1 FROM pull_requests pr
+		INNER JOIN team_memberships atm ON atm.user_id = pr.author_id
+		WHERE pr.pull_request_id = a.pull_request_id AND atm.team_name = m.team_name
+	)
+	GROUP BY m.team_name, m.user_id, m.username
+	ORDER BY m.team_name, m.user_id`
+
+	var loads []*models.ReviewerLoad
+
+	if err := sr.db.SelectContext(ctx, &loads, loadsQuery, args...); err != nil {
+		return nil, fmt.Errorf("error getting reviewer loads: %w", err)
+	}
+
+	if loads == nil {
+		loads = []*models.ReviewerLoad{}
+	}
+
+	return loads, nil
+}
+
+// источник точек ряда: таблица с условием, колонка времени и пользователь, по которому фильтруется команда
+type timeseriesSource struct {
+	from       string
+	timeColumn string
+	userColumn string
+}
+
+var timeseriesSources = map[models.TimeseriesMetric]timeseriesSource{
+	models.TimeseriesPullRequestsCreated: {from: "pull_requests WHERE TRUE", timeColumn: "created_at", userColumn: "author_id"},
+	models.TimeseriesPullRequestsMerged:  {from: "pull_requests WHERE merged_at IS NOT NULL", timeColumn: "merged_at", userColumn: "author_id"},
+	models.TimeseriesAssignments:         {from: "pr_events WHERE event_type = 'reviewer_assigned'", timeColumn: "created_at", userColumn: "user_id"},
+}
+
+// интервалы без событий заполняются нулями через generate_series; для команды PR считаются по автору,
+// назначения — по ревьюверу
+func (sr *StatsRepository) GetTimeseries(ctx context.Context, filter models.TimeseriesFilter) ([]*models.TimeseriesPoint, error) {
+
+	source, ok := timeseriesSources[filter.Metric]
+	if !ok {
+		return nil, errs.ErrBadRequest
+	}
+
+	if filter.TeamName != "" {
+		var isExists bool
+		checkTeamQuery := `SELECT EXISTS(SELECT 1 FROM teams WHERE team_name = $1)`
+
+		if err := sr.db.GetContext(ctx, &isExists, checkTeamQuery, filter.TeamName); err != nil {
+			return nil, fmt.Errorf("error checking for team existence: %w", err)
+		}
+		if !isExists {
+			return nil, errs.ErrTeamNotFound
+		}
+	}
+
+	teamCond := "TRUE"
+	if filter.TeamName != "" {
+		teamCond = source.userColumn + ` IN (
+			SELECT tm.user_id FROM team_memberships tm
+			INNER JOIN subtree s ON tm.team_name = s.team_name
+		)`
+	}
+
+	timeseriesQuery := teamSubtreeCTE + `,
+	buckets AS (
+		SELECT b AT TIME ZONE 'UTC' AS bucket_start
+		FROM generate_series(
+			date_trunc($2, $3::timestamptz AT TIME ZONE 'UTC'),
+			$4::timestamptz AT TIME ZONE 'UTC',
+			('1 ' || $2)::interval
+		) AS b
+		WHERE b < $4::timestamptz AT TIME ZONE 'UTC'
+	),
+	points AS (
+		SELECT date_trunc($2, ` + source.timeColumn + ` AT TIME ZONE 'UTC') AT TIME ZONE 'UTC' AS bucket_start
+		FROM ` + source.from + `
+			AND ` + source.timeColumn + ` >= $3 AND ` + source.timeColumn + ` < $4
+			AND ` + teamCond + `
+	)
+	SELECT bk.bucket_start, COUNT(p.bucket_start) AS value
+	FROM buckets bk
+	LEFT JOIN points p ON p.bucket_start = bk.bucket_start
+	GROUP BY bk.bucket_start
+	ORDER BY bk.bucket_start`
+
+	var points []*models.TimeseriesPoint
+
+	if err := sr.db.SelectContext(ctx, &points, timeseriesQuery, filter.TeamName, string(filter.Bucket), filter.From, filter.To); err != nil {
+		return nil, fmt.Errorf("error getting %s timeseries: %w", filter.Metric, err)
+	}
+
+	if points == nil {
+		points = []*models.TimeseriesPoint{}
+	}
+
+	return points, nil
+}
+
+// медиана удержания считается по PR, смерженным при пользователе-ревьювере: от assigned_at до merged_at.
+// Команды, для которых пользователь ревьюил, — команды авторов PR, на которые он назначался
+func (sr *StatsRepository) GetUserStats(ctx context.Context, userID string) (*models.UserStats, error) {
+
+	var stats models.UserStats
+
+	statsQuery := `SELECT u.user_id, u.username,
+		(SELECT COUNT(*) FROM pull_requests WHERE author_id = u.user_id) AS authored_pull_requests,
+		(SELECT COUNT(*) FROM pr_reviewers rev
+			INNER JOIN pull_requests pr ON pr.pull_request_id = rev.pull_request_id
+			WHERE rev.user_id = u.user_id AND pr.status = 'OPEN') AS open_reviews,
+		(SELECT COUNT(DISTINCT pull_request_id) FROM pr_events
+			WHERE user_id = u.user_id AND event_type = 'reviewer_assigned') AS total_reviews,
+		(SELECT COUNT(*) FROM pr_events
+			WHERE user_id = u.user_id AND event_type = 'reviewer_removed') AS reassigned_away,
+		(SELECT COUNT(*) FROM pr_reviewers rev
+			INNER JOIN pull_requests pr ON pr.pull_request_id = rev.pull_request_id
+			WHERE rev.user_id = u.user_id AND pr.status = 'MERGED') AS merged_while_reviewer,
+		(SELECT percentile_cont(0.5) WITHIN GROUP (
+				ORDER BY GREATEST(EXTRACT(EPOCH FROM pr.merged_at - rev.assigned_at), 0)::float8)
+			FROM pr_reviewers rev
+			INNER JOIN pull_requests pr ON pr.pull_request_id = rev.pull_request_id
+			WHERE rev.user_id = u.user_id AND pr.status = 'MERGED') AS median_held_seconds
+		FROM users u
+		WHERE u.user_id = $1`
+
+	if err := sr.db.GetContext(ctx, &stats, statsQuery, userID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errs.ErrUserNotFound
+		}
+		return nil, fmt.Errorf("error getting stats for user %s: %w", userID, err)
+	}
+
+	teamsQuery := `SELECT tm.team_name, COUNT(DISTINCT e.pull_request_id) AS reviews
+		FROM pr_events e
+		INNER JOIN pull_requests pr ON pr.pull_request_id = e.pull_request_id
+		INNER JOIN team_memberships tm ON tm.user_id = pr.author_id
+		WHERE e.user_id = $1 AND e.event_type = 'reviewer_assigned'
+		GROUP BY tm.team_name
+		ORDER BY reviews DESC, tm.team_name`
+
+	var teams []*models.TeamReviewCount
+
+	if err := sr.db.SelectContext(ctx, &teams, teamsQuery, userID); err != nil {
+		return nil, fmt.Errorf("error getting reviewed teams for user %s: %w", userID, err)
+	}
+
+	if teams == nil {
+		teams = []*models.TeamReviewCount{}
+	}
+
+	stats.ReviewedTeams = teams
+
+	return &stats, nil
+}
+
+// читает материализованные представления, а не журнал: опрос /metrics не должен сканировать pr_events
+func (sr *StatsRepository) GetDomainCounters(ctx context.Context) (*models.DomainCounters, error) {
+
+	var counters models.DomainCounters
+
+	summaryQuery := `SELECT open_pull_requests FROM stats_summary`
+	if err := sr.db.GetContext(ctx, &counters.OpenPullRequests, summaryQuery); err != nil {
+		return nil, fmt.Errorf("error getting domain counters: %w", err)
+	}
+
+	eventsQuery := `SELECT event_type, reason, event_count
+		FROM stats_event_counts
+		WHERE event_type IN ('reviewer_assigned', 'reviewer_removed')`
+
+	var rows []struct {
+		EventType models.PullRequestEventType   `db:"event_type"`
+		Reason    models.PullRequestEventReason `db:"reason"`
+		Count     int                           `db:"event_count"`
+	}
+
+	if err := sr.db.SelectContext(ctx, &rows, eventsQuery); err != nil {
+		return nil, fmt.Errorf("error getting event counters: %w", err)
+	}
+
+	counters.Reassignments = make(map[models.PullRequestEventReason]int, len(rows))
+	for _, row := range rows {
+		switch {
+		case row.EventType == models.PullRequestEventReviewerAssigned:
+			counters.Assignments += row.Count
+		case row.Reason != "":
+			counters.Reassignments[row.Reason] = row.Count
+		}
+	}
+
+	return &counters, nil
+}
+
+// открытые PR и их возраст на момент начала транзакции; с именем команды — только PR авторов из её поддерева
+const agingCTE = teamSubtreeCTE + `,
+	open_prs AS (
+		SELECT pr.pull_request_id, pr.pull_request_name, pr.author_id, pr.created_at,
+			now() - pr.created_at AS age
+		FROM pull_requests pr
+		WHERE pr.status = 'OPEN' AND ($1::text = '' OR pr.author_id IN (
+			SELECT tm.user_id FROM team_memberships tm
+			INNER JOIN subtree s ON tm.team_name = s.team_name
+		))
+	)`
+
+const agingBucketCounts = `COUNT(*) FILTER (WHERE p.age < interval '1 day') AS under_1d,
+		COUNT(*) FILTER (WHERE p.age >= interval '1 day' AND p.age < interval '3 days') AS days_1_3,
+		COUNT(*) FILTER (WHERE p.age >= interval '3 days' AND p.age < interval '7 days') AS days_3_7,
+		COUNT(*) FILTER (WHERE p.age >= interval '7 days') AS over_7d,
+		COUNT(*) AS total`
+
+type agingOldestRow struct {
+	models.AgingPullRequest
+	Reviewers pq.StringArray `db:"assigned_reviewers"`
+}
+
+// все запросы идут в одной read-only транзакции REPEATABLE READ: снимок и now() у них общие,
+// поэтому сумма по корзинам совпадает с total, а возраст считается от одного момента.
+// PR автора из нескольких команд учитывается в каждой из них
+func (sr *StatsRepository) GetAgingStats(ctx context.Context, teamName string, oldest int) (*models.AgingStats, error) {
+
+	tx, err := sr.db.BeginTxx(ctx, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		return nil, fmt.Errorf("error begining transaction aging stats: %w", err)
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil && !errors.Is(err, sql.ErrTxDone) {
+			log.Printf("rollback error: %v", err)
+		}
+	}()
+
+	if teamName != "" {
+		var isExists bool
+		checkTeamQuery := `SELECT EXISTS(SELECT 1 FROM teams WHERE team_name = $1)`
+
+		if err := tx.GetContext(ctx, &isExists, checkTeamQuery, teamName); err != nil {
+			return nil, fmt.Errorf("error checking for team existence: %w", err)
+		}
+		if !isExists {
+			return nil, errs.ErrTeamNotFound
+		}
+	}
+
+	var total struct {
+		AsOf time.Time `db:"as_of"`
+		models.AgingBuckets
+	}
+
+	totalQuery := agingCTE + `
+		SELECT now() AS as_of, ` + agingBucketCounts + `
+		FROM open_prs p`
+
+	if err := tx.GetContext(ctx, &total, totalQuery, teamName); err != nil {
+		return nil, fmt.Errorf("error getting open pull requests aging: %w", err)
+	}
+
+	teamsQuery := agingCTE + `
+		SELECT tm.team_name, ` + agingBucketCounts + `
+		FROM open_prs p
+		INNER JOIN team_memberships tm ON tm.user_id = p.author_id
+		WHERE $1::text = '' OR tm.team_name IN (SELECT team_name FROM subtree)
+		GROUP BY tm.team_name
+		ORDER BY over_7d DESC, days_3_7 DESC, total DESC, tm.team_name`
+
+	teams := []*models.TeamAging{}
+
+	if err := tx.SelectContext(ctx, &teams, teamsQuery, teamName); err != nil {
+		return nil, fmt.Errorf("error getting teams aging: %w", err)
+	}
+
+	reviewersQuery := agingCTE + `
+		SELECT u.user_id, u.username, ` + agingBucketCounts + `
+		FROM open_prs p
+		INNER JOIN pr_reviewers rev ON rev.pull_request_id = p.pull_request_id
+		INNER JOIN users u ON u.user_id = rev.user_id
+		GROUP BY u.user_id, u.username
+		ORDER BY over_7d DESC, days_3_7 DESC, total DESC, u.user_id`
+
+	reviewers := []*models.ReviewerAging{}
+
+	if err := tx.SelectContext(ctx, &reviewers, reviewersQuery, teamName); err != nil {
+		return nil, fmt.Errorf("error getting reviewers aging: %w", err)
+	}
+
+	oldestQuery := agingCTE + `
+		SELECT p.pull_request_id, p.pull_request_name, p.author_id, p.created_at,
+			EXTRACT(EPOCH FROM p.age)::bigint AS age_seconds,
+			COALESCE(array_agg(rev.user_id ORDER BY rev.assigned_at) FILTER (WHERE rev.user_id IS NOT NULL), '{}') AS assigned_reviewers
+		FROM open_prs p
+		LEFT JOIN pr_reviewers rev ON rev.pull_request_id = p.pull_request_id
+		GROUP BY p.pull_request_id, p.pull_request_name, p.author_id, p.created_at, p.age
+		ORDER BY p.created_at, p.pull_request_id
+		LIMIT $2`
+
+	var rows []*agingOldestRow
+
+	if err := tx.SelectContext(ctx, &rows, oldestQuery, teamName, oldest); err != nil {
+		return nil, fmt.Errorf("error getting oldest open pull requests: %w", err)
+	}
+
+	prs := make([]*models.AgingPullRequest, 0, len(rows))
+	for _, row := range rows {
+		pr := row.AgingPullRequest
+		pr.AssignedReviewers = []string(row.Reviewers)
+		prs = append(prs, &pr)
+	}
+
+	return &models.AgingStats{
+		AsOf:      total.AsOf,
+		Total:     total.AgingBuckets,
+		Teams:     teams,
+		Reviewers: reviewers,
+		Oldest:    prs,
+	}, nil
+}
@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"time"
 
 	"github.com/guarref/pr-service-assignment/internal/errs"
 	"github.com/guarref/pr-service-assignment/internal/models"
@@ -21,8 +22,15 @@ func NewTeamRepository(db *sqlx.DB) *TeamRepository {
 	return &TeamRepository{db: db}
 }
 
+const teamMembersQuery = `SELECT u.user_id, u.username, (u.is_active AND tm.is_active) AS is_active, tm.is_primary, tm.role
+	FROM team_memberships tm
+	INNER JOIN users u ON u.user_id = tm.user_id
+	WHERE tm.team_name = $1
+	ORDER BY u.username`
+
 // если есть команда, то TEAM_EXISTS, если нет, то создаем
-// если есть пользователь, то обновляем данные, если нет, то создаем
+// отсутствующие пользователи создаются, существующие не меняются
+// команда становится основной, если передан is_primary (сервис выставляет его только новым пользователям) и основной ещё нет
 func (tr *TeamRepository) CreateTeam(ctx context.Context, team *models.Team) error {
 
 	tx, err := tr.db.BeginTxx(ctx, nil)
@@ -45,24 +53,38 @@ func (tr *TeamRepository) CreateTeam(ctx context.Context, team *models.Team) err
 		return errs.ErrTeamExists
 	}
 
-	creationTeamQuery := `INSERT INTO teams (team_name, created_at, updated_at) VALUES ($1, NOW(), NOW())`
+	if team.ParentTeam != nil {
+		var isParentExists bool
+		if err := tx.GetContext(ctx, &isParentExists, checkTeamQuery, *team.ParentTeam); err != nil {
+			return fmt.Errorf("error checking for parent team existence: %w", err)
+		}
+		if !isParentExists {
+			return errs.ErrParentTeamNotFound
+		}
+	}
+
+	creationTeamQuery := `INSERT INTO teams (team_name, parent_team, created_at, updated_at) VALUES ($1, $2, NOW(), NOW())`
 
-	if _, err := tx.ExecContext(ctx, creationTeamQuery, team.TeamName); err != nil {
+	if _, err := tx.ExecContext(ctx, creationTeamQuery, team.TeamName, team.ParentTeam); err != nil {
 		return fmt.Errorf("error team creation: %w", err)
 	}
 
-	additionUserQuery := `INSERT INTO users (user_id, username, team_name, is_active, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, NOW(), NOW()) ON CONFLICT (user_id) 
-		DO UPDATE SET
-		username   = EXCLUDED.username,
-		team_name  = EXCLUDED.team_name,
-		is_active  = EXCLUDED.is_active,
-		updated_at = NOW()`
+	// существующие пользователи не меняются: имя, активность и основная команда остаются прежними
+	additionUserQuery := `INSERT INTO users (user_id, username, is_active, created_at, updated_at)
+		VALUES ($1, $2, $3, NOW(), NOW()) ON CONFLICT (user_id) DO NOTHING`
+
+	additionMembershipQuery := `INSERT INTO team_memberships (user_id, team_name, is_primary, is_active, role, created_at, updated_at)
+		VALUES ($1, $2, $4 AND NOT EXISTS(SELECT 1 FROM team_memberships WHERE user_id = $1 AND is_primary), true, $3, NOW(), NOW())
+		ON CONFLICT (user_id, team_name) DO NOTHING`
 
 	for _, m := range team.Members {
-		if _, err := tx.ExecContext(ctx, additionUserQuery, m.UserID, m.UserName, team.TeamName, m.IsActive); err != nil {
+		if _, err := tx.ExecContext(ctx, additionUserQuery, m.UserID, m.UserName, m.IsActive); err != nil {
 			return fmt.Errorf("error addition user with id %s: %w", m.UserID, err)
 		}
+
+		if _, err := tx.ExecContext(ctx, additionMembershipQuery, m.UserID, team.TeamName, m.Role, m.IsPrimary); err != nil {
+			return fmt.Errorf("error addition membership of user %s: %w", m.UserID, err)
+		}
 	}
 
 	if err := tx.Commit(); err != nil {
@@ -76,7 +98,7 @@ func (tr *TeamRepository) GetTeamByName(ctx context.Context, teamName string) (*
 
 	var team models.Team
 
-	teamQuery := `SELECT team_name, created_at, updated_at
+	teamQuery := `SELECT team_name, parent_team, is_active, archived_at, created_at, updated_at
 		FROM teams
 		WHERE team_name = $1`
 
@@ -87,13 +109,8 @@ func (tr *TeamRepository) GetTeamByName(ctx context.Context, teamName string) (*
 		return nil, fmt.Errorf("error gettig team: %w", err)
 	}
 
-	membersQuery := `SELECT user_id, username, is_active
-		FROM users
-		WHERE team_name = $1
-		ORDER BY username`
-
 	var members []models.TeamMember
-	if err := tr.db.SelectContext(ctx, &members, membersQuery, teamName); err != nil {
+	if err := tr.db.SelectContext(ctx, &members, teamMembersQuery, teamName); err != nil {
 		return nil, fmt.Errorf("error getting team members: %w", err)
 	}
 	team.Members = members
@@ -101,11 +118,71 @@ func (tr *TeamRepository) GetTeamByName(ctx context.Context, teamName string) (*
 	return &team, nil
 }
 
-func (tr *TeamRepository) DeactivateUsersAndReassignPRs(ctx context.Context, teamName string, userIDs []string) ([]string, error) {
+// роль пользователя в команде; пустая, если он не состоит в ней или неактивен
+func (tr *TeamRepository) GetMemberRole(ctx context.Context, teamName string, userID string) (models.TeamRole, error) {
+
+	roleQuery := `SELECT tm.role
+		FROM team_memberships tm
+		INNER JOIN users u ON u.user_id = tm.user_id
+		WHERE tm.team_name = $1 AND tm.user_id = $2 AND tm.is_active AND u.is_active`
+
+	var role models.TeamRole
+	if err := tr.db.GetContext(ctx, &role, roleQuery, teamName, userID); err != nil {
+		if err == sql.ErrNoRows {
+			return "", nil
+		}
+		return "", fmt.Errorf("error getting member role: %w", err)
+	}
+
+	return role, nil
+}
+
+func (tr *TeamRepository) ListTeams(ctx context.Context, filter models.TeamListFilter) ([]*models.TeamSummary, error) {
+
+	// открытые PR считаются по авторам, состоящим в команде (в том числе не основной)
+	listQuery := `SELECT t.team_name, t.parent_team, t.is_active, t.created_at,
+			(SELECT COUNT(*) FROM team_memberships tm WHERE tm.team_name = t.team_name) AS member_count,
+			(SELECT COUNT(*) FROM team_memberships tm
+				INNER JOIN users u ON u.user_id = tm.user_id
+				WHERE tm.team_name = t.team_name AND tm.is_active AND u.is_active) AS active_member_count,
+			(SELECT COUNT(*) FROM pull_requests pr
+				WHERE pr.status = 'OPEN' AND EXISTS(
+					SELECT 1 FROM team_memberships tm WHERE tm.user_id = pr.author_id AND tm.team_name = t.team_name
+				)) AS open_pull_requests
+		FROM teams t
+		WHERE ($1::text = '' OR t.team_name ILIKE '%' || $1::text || '%')
+		AND ($2::boolean IS NULL OR t.is_active = $2::boolean)`
+
+	args := []any{escapeLike(filter.Search), filter.IsActive}
+
+	if filter.After != nil {
+		args = append(args, filter.After.ID)
+		listQuery += `
+		AND t.team_name > $3`
+	}
+
+	args = append(args, filter.Limit)
+	listQuery += fmt.Sprintf(`
+		ORDER BY t.team_name
+		LIMIT $%d`, len(args))
+
+	var teams []*models.TeamSummary
+	if err := tr.db.SelectContext(ctx, &teams, listQuery, args...); err != nil {
+		return nil, fmt.Errorf("error listing teams: %w", err)
+	}
+
+	if teams == nil {
+		teams = []*models.TeamSummary{}
+	}
+
+	return teams, nil
+}
+
+func (tr *TeamRepository) DeactivateUsersAndReassignPRs(ctx context.Context, teamName string, userIDs []string) ([]string, int, error) {
 
 	tx, err := tr.db.BeginTxx(ctx, nil)
 	if err != nil {
-		return nil, fmt.Errorf("error begining transaction team: %w", err)
+		return nil, 0, fmt.Errorf("error begining transaction team: %w", err)
 	}
 	defer func() {
 		if err := tx.Rollback(); err != nil && !errors.Is(err, sql.ErrTxDone) {
@@ -116,132 +193,313 @@ func (tr *TeamRepository) DeactivateUsersAndReassignPRs(ctx context.Context, tea
 	var isExists bool
 	checkTeamQuery := `SELECT EXISTS(SELECT 1 FROM teams WHERE team_name = $1)`
 	if err := tx.GetContext(ctx, &isExists, checkTeamQuery, teamName); err != nil {
-		return nil, fmt.Errorf("error checking for team existence: %w", err)
+		return nil, 0, fmt.Errorf("error checking for team existence: %w", err)
 	}
 	if !isExists {
-		return nil, errs.ErrNotFound
+		return nil, 0, errs.ErrNotFound
 	}
 
 	var usersToDeactivate []string
 
 	if len(userIDs) > 0 {
-		deactivateQuery := `SELECT user_id 
-			FROM users 
-			WHERE team_name = $1 
-			AND user_id = ANY($2) 
+		deactivateQuery := `SELECT user_id
+			FROM team_memberships
+			WHERE team_name = $1
+			AND user_id = ANY($2)
 			AND is_active = true`
 
 		if err := tx.SelectContext(ctx, &usersToDeactivate, deactivateQuery, teamName, pq.Array(userIDs)); err != nil {
-			return nil, fmt.Errorf("error getting users to deactivate: %w", err)
+			return nil, 0, fmt.Errorf("error getting users to deactivate: %w", err)
 		}
 	} else {
-		deactivateQuery := `SELECT user_id 
-			FROM users 
-			WHERE team_name = $1 
+		deactivateQuery := `SELECT user_id
+			FROM team_memberships
+			WHERE team_name = $1
 			AND is_active = true`
 
 		if err := tx.SelectContext(ctx, &usersToDeactivate, deactivateQuery, teamName); err != nil {
-			return nil, fmt.Errorf("error getting all team users: %w", err)
+			return nil, 0, fmt.Errorf("error getting all team users: %w", err)
 		}
 	}
 
 	if len(usersToDeactivate) == 0 {
 		if err := tx.Commit(); err != nil {
-			return nil, fmt.Errorf("error committing transaction team: %w", err)
+			return nil, 0, fmt.Errorf("error committing transaction team: %w", err)
 		}
-		return []string{}, nil
+		return []string{}, 0, nil
 	}
 
-	type prInfo struct {
-		PullRequestID string `db:"pull_request_id"`
-		AuthorID      string `db:"author_id"`
+	// деактивируется только членство в этой команде; users.is_active меняется лишь при глобальной деактивации
+	deactivateQuery := `UPDATE team_memberships
+		SET is_active = false, updated_at = NOW()
+		WHERE team_name = $1 AND user_id = ANY($2)`
+
+	if _, err := tx.ExecContext(ctx, deactivateQuery, teamName, pq.Array(usersToDeactivate)); err != nil {
+		return nil, 0, fmt.Errorf("error deactivating team memberships: %w", err)
 	}
 
-	affectedPRsQuery := `SELECT DISTINCT pr.pull_request_id, pr.author_id
-		FROM pull_requests pr
-		INNER JOIN pr_reviewers rev ON pr.pull_request_id = rev.pull_request_id
-		WHERE pr.status = 'OPEN' 
-		AND rev.user_id = ANY($1)`
+	dropped, err := reassignTeamReviews(ctx, tx, teamName, usersToDeactivate, models.EventReasonDeactivation)
+	if err != nil {
+		return nil, 0, err
+	}
 
-	var affectedPRs []prInfo
-	if err := tx.SelectContext(ctx, &affectedPRs, affectedPRsQuery, pq.Array(usersToDeactivate)); err != nil {
-		return nil, fmt.Errorf("error getting affected PRs: %w", err)
+	if err := tx.Commit(); err != nil {
+		return nil, 0, fmt.Errorf("error committing transaction team: %w", err)
 	}
 
-	for _, pr := range affectedPRs {
-		var currentReviewers []string
-		reviewersQuery := `SELECT user_id FROM pr_reviewers WHERE pull_request_id = $1`
-		if err := tx.SelectContext(ctx, &currentReviewers, reviewersQuery, pr.PullRequestID); err != nil {
-			return nil, fmt.Errorf("error getting current reviewers for PR %s: %w", pr.PullRequestID, err)
+	return usersToDeactivate, dropped, nil
+}
+
+// архивирует команду: помечает её и все членства в ней неактивными, деактивирует участников,
+// у которых не осталось других активных команд, и переназначает (или снимает) их ревью в открытых PR;
+// повторный вызов ничего не меняет
+func (tr *TeamRepository) ArchiveTeam(ctx context.Context, teamName string) (*models.TeamArchive, int, error) {
+
+	tx, err := tr.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, 0, fmt.Errorf("error begining transaction archive_team: %w", err)
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil && !errors.Is(err, sql.ErrTxDone) {
+			log.Printf("rollback error: %v", err)
 		}
+	}()
 
-		var reviewersToReplace []string
-		for _, reviewerID := range currentReviewers {
-			for _, deactivateID := range usersToDeactivate {
-				if reviewerID == deactivateID {
-					reviewersToReplace = append(reviewersToReplace, reviewerID)
-					break
-				}
-			}
+	teamQuery := `SELECT team_name, parent_team, is_active, archived_at, created_at, updated_at
+		FROM teams
+		WHERE team_name = $1
+		FOR UPDATE`
+
+	var team models.Team
+	if err := tx.GetContext(ctx, &team, teamQuery, teamName); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, 0, errs.ErrTeamNotFound
+		}
+		return nil, 0, fmt.Errorf("error getting team: %w", err)
+	}
+
+	if !team.IsActive && team.ArchivedAt != nil {
+		if err := tx.Commit(); err != nil {
+			return nil, 0, fmt.Errorf("error committing transaction archive_team: %w", err)
+		}
+		return &models.TeamArchive{TeamName: team.TeamName, DeactivatedUsers: []string{}, ArchivedAt: *team.ArchivedAt}, 0, nil
+	}
+
+	usersQuery := `SELECT u.user_id
+		FROM users u
+		INNER JOIN team_memberships tm ON tm.user_id = u.user_id
+		WHERE tm.team_name = $1
+		AND u.is_active = true
+		AND NOT EXISTS(
+			SELECT 1 FROM team_memberships o
+			WHERE o.user_id = u.user_id AND o.team_name <> $1 AND o.is_active = true
+		)`
+
+	var usersToDeactivate []string
+	if err := tx.SelectContext(ctx, &usersToDeactivate, usersQuery, teamName); err != nil {
+		return nil, 0, fmt.Errorf("error getting team users: %w", err)
+	}
+
+	dropped := 0
+	if len(usersToDeactivate) > 0 {
+		if dropped, err = reassignOpenReviews(ctx, tx, usersToDeactivate, models.EventReasonDeactivation); err != nil {
+			return nil, 0, err
 		}
 
-		if len(reviewersToReplace) == 0 {
-			continue
+		deactivateQuery := `UPDATE users
+			SET is_active = false, updated_at = NOW()
+			WHERE user_id = ANY($1)`
+
+		if _, err := tx.ExecContext(ctx, deactivateQuery, pq.Array(usersToDeactivate)); err != nil {
+			return nil, 0, fmt.Errorf("error deactivating users: %w", err)
 		}
+	} else {
+		usersToDeactivate = []string{}
+	}
+
+	membershipsQuery := `UPDATE team_memberships
+		SET is_active = false, updated_at = NOW()
+		WHERE team_name = $1`
+
+	if _, err := tx.ExecContext(ctx, membershipsQuery, teamName); err != nil {
+		return nil, 0, fmt.Errorf("error deactivating team memberships: %w", err)
+	}
+
+	archiveQuery := `UPDATE teams
+		SET is_active = false, archived_at = NOW(), updated_at = NOW()
+		WHERE team_name = $1
+		RETURNING archived_at`
+
+	var archivedAt time.Time
+	if err := tx.GetContext(ctx, &archivedAt, archiveQuery, teamName); err != nil {
+		return nil, 0, fmt.Errorf("error archiving team: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, 0, fmt.Errorf("error committing transaction archive_team: %w", err)
+	}
 
-		var authorTeam string
-		teamQuery := `SELECT team_name FROM users WHERE user_id = $1`
-		if err := tx.GetContext(ctx, &authorTeam, teamQuery, pr.AuthorID); err != nil {
-			return nil, fmt.Errorf("error getting author team: %w", err)
+	return &models.TeamArchive{TeamName: teamName, DeactivatedUsers: usersToDeactivate, ArchivedAt: archivedAt}, dropped, nil
+}
+
+// удаляет команду; участники, у которых нет других команд, удаляются вместе с ней,
+// остальные просто теряют членство (при необходимости им назначается новая основная команда).
+// Если у удаляемых участников есть история PR или ревью, удаление без force запрещено,
+// а с force перед удалением собирается выгрузка этой истории
+func (tr *TeamRepository) DeleteTeam(ctx context.Context, teamName string, force bool) (*models.TeamExport, int, error) {
+
+	tx, err := tr.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, 0, fmt.Errorf("error begining transaction delete_team: %w", err)
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil && !errors.Is(err, sql.ErrTxDone) {
+			log.Printf("rollback error: %v", err)
 		}
+	}()
 
-		excludeUsers := append(currentReviewers, pr.AuthorID)
-		excludeUsers = append(excludeUsers, usersToDeactivate...)
+	teamQuery := `SELECT team_name, parent_team, is_active, archived_at, created_at, updated_at
+		FROM teams
+		WHERE team_name = $1
+		FOR UPDATE`
 
-		usersQuery := `SELECT user_id 
-			FROM users 
-			WHERE team_name = $1 
-			AND is_active = true 
-			AND user_id != ALL($2)
-			ORDER BY RANDOM()`
+	var team models.Team
+	if err := tx.GetContext(ctx, &team, teamQuery, teamName); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, 0, errs.ErrTeamNotFound
+		}
+		return nil, 0, fmt.Errorf("error getting team: %w", err)
+	}
+
+	var members []models.TeamMember
+	if err := tx.SelectContext(ctx, &members, teamMembersQuery, teamName); err != nil {
+		return nil, 0, fmt.Errorf("error getting team members: %w", err)
+	}
+	team.Members = members
+
+	exclusiveQuery := `SELECT tm.user_id
+		FROM team_memberships tm
+		WHERE tm.team_name = $1
+		AND NOT EXISTS(
+			SELECT 1 FROM team_memberships o
+			WHERE o.user_id = tm.user_id AND o.team_name <> $1
+		)`
+
+	var exclusiveIDs []string
+	if err := tx.SelectContext(ctx, &exclusiveIDs, exclusiveQuery, teamName); err != nil {
+		return nil, 0, fmt.Errorf("error getting exclusive team members: %w", err)
+	}
+
+	historyQuery := `SELECT EXISTS(
+			SELECT 1 FROM pull_requests WHERE author_id = ANY($1)
+		) OR EXISTS(
+			SELECT 1 FROM pr_reviewers WHERE user_id = ANY($1)
+		)`
+
+	var hasHistory bool
+	if err := tx.GetContext(ctx, &hasHistory, historyQuery, pq.Array(exclusiveIDs)); err != nil {
+		return nil, 0, fmt.Errorf("error checking team history: %w", err)
+	}
+	if hasHistory && !force {
+		return nil, 0, errs.ErrTeamHasHistory
+	}
+
+	export := &models.TeamExport{
+		Team:         &team,
+		PullRequests: []*models.PullRequest{},
+		Reviews:      []*models.ReviewAssignment{},
+	}
 
-		var users []string
-		if err := tx.SelectContext(ctx, &users, usersQuery, authorTeam, pq.Array(excludeUsers)); err != nil {
-			return nil, fmt.Errorf("error getting users: %w", err)
+	dropped := 0
+	if hasHistory {
+		prsQuery := `SELECT pull_request_id, pull_request_name, author_id, status, created_at, merged_at
+			FROM pull_requests
+			WHERE author_id = ANY($1)
+			ORDER BY created_at, pull_request_id`
+
+		if err := tx.SelectContext(ctx, &export.PullRequests, prsQuery, pq.Array(exclusiveIDs)); err != nil {
+			return nil, 0, fmt.Errorf("error exporting team pull requests: %w", err)
 		}
 
-		userIdx := 0
-		for _, oldReviewerID := range reviewersToReplace {
-			deleteQuery := `DELETE FROM pr_reviewers WHERE pull_request_id = $1 AND user_id = $2`
+		reviewersQuery := `SELECT user_id
+			FROM pr_reviewers
+			WHERE pull_request_id = $1
+			ORDER BY assigned_at`
 
-			if _, err := tx.ExecContext(ctx, deleteQuery, pr.PullRequestID, oldReviewerID); err != nil {
-				return nil, fmt.Errorf("error removing reviewer %s from PR %s: %w", oldReviewerID, pr.PullRequestID, err)
+		for _, pr := range export.PullRequests {
+			var reviewers []string
+			if err := tx.SelectContext(ctx, &reviewers, reviewersQuery, pr.PullRequestID); err != nil {
+				return nil, 0, fmt.Errorf("error exporting reviewers for PR %s: %w", pr.PullRequestID, err)
 			}
+			pr.AssignedReviewers = reviewers
+		}
 
-			if userIdx < len(users) {
-				newReviewerID := users[userIdx]
-				userIdx++
+		reviewsQuery := `SELECT pull_request_id, user_id, assigned_at
+			FROM pr_reviewers
+			WHERE user_id = ANY($1)
+			ORDER BY assigned_at, pull_request_id`
 
-				insertQuery := `INSERT INTO pr_reviewers (pull_request_id, user_id, assigned_at) VALUES ($1, $2, NOW())`
+		if err := tx.SelectContext(ctx, &export.Reviews, reviewsQuery, pq.Array(exclusiveIDs)); err != nil {
+			return nil, 0, fmt.Errorf("error exporting team reviews: %w", err)
+		}
 
-				if _, err := tx.ExecContext(ctx, insertQuery, pr.PullRequestID, newReviewerID); err != nil {
-					return nil, fmt.Errorf("error assigning new reviewer %s to PR %s: %w", newReviewerID, pr.PullRequestID, err)
-				}
-			}
+		// открытые PR других команд не должны остаться без ревьюверов после каскадного удаления
+		if dropped, err = reassignOpenReviews(ctx, tx, exclusiveIDs, models.EventReasonTeamDeletion); err != nil {
+			return nil, 0, err
+		}
+
+		// назначения в PR других авторов исчезнут каскадно вместе с пользователями, история должна это отразить
+		removedEventsQuery := `INSERT INTO pr_events (pull_request_id, event_type, user_id, reason, created_at)
+			SELECT rev.pull_request_id, $2::text, rev.user_id, $3::text, NOW()
+			FROM pr_reviewers rev
+			INNER JOIN pull_requests pr ON pr.pull_request_id = rev.pull_request_id
+			WHERE rev.user_id = ANY($1)
+			AND pr.author_id <> ALL($1)
+			ORDER BY rev.pull_request_id, rev.user_id`
+
+		if _, err := tx.ExecContext(ctx, removedEventsQuery, pq.Array(exclusiveIDs),
+			models.PullRequestEventReviewerRemoved, models.EventReasonTeamDeletion); err != nil {
+			return nil, 0, fmt.Errorf("error writing reviewer removal events: %w", err)
+		}
+	}
+
+	deleteQuery := `DELETE FROM teams WHERE team_name = $1`
+
+	if _, err := tx.ExecContext(ctx, deleteQuery, teamName); err != nil {
+		return nil, 0, fmt.Errorf("error deleting team: %w", err)
+	}
+
+	if len(exclusiveIDs) > 0 {
+		deleteUsersQuery := `DELETE FROM users WHERE user_id = ANY($1)`
+
+		if _, err := tx.ExecContext(ctx, deleteUsersQuery, pq.Array(exclusiveIDs)); err != nil {
+			return nil, 0, fmt.Errorf("error deleting team users: %w", err)
 		}
 	}
 
-	deactivateQuery := `UPDATE users 
-		SET is_active = false, updated_at = NOW() 
-		WHERE user_id = ANY($1)`
+	memberIDs := make([]string, 0, len(members))
+	for _, m := range members {
+		memberIDs = append(memberIDs, m.UserID)
+	}
 
-	if _, err := tx.ExecContext(ctx, deactivateQuery, pq.Array(usersToDeactivate)); err != nil {
-		return nil, fmt.Errorf("error deactivating users: %w", err)
+	promotePrimaryQuery := `UPDATE team_memberships tm
+		SET is_primary = true, updated_at = NOW()
+		FROM (
+			SELECT DISTINCT ON (user_id) user_id, team_name
+			FROM team_memberships
+			WHERE user_id = ANY($1)
+			ORDER BY user_id, is_active DESC, created_at, team_name
+		) next
+		WHERE tm.user_id = next.user_id AND tm.team_name = next.team_name
+		AND NOT EXISTS(SELECT 1 FROM team_memberships p WHERE p.user_id = tm.user_id AND p.is_primary)`
+
+	if _, err := tx.ExecContext(ctx, promotePrimaryQuery, pq.Array(memberIDs)); err != nil {
+		return nil, 0, fmt.Errorf("error promoting primary teams: %w", err)
 	}
 
 	if err := tx.Commit(); err != nil {
-		return nil, fmt.Errorf("error committing transaction team: %w", err)
+		return nil, 0, fmt.Errorf("error committing transaction delete_team: %w", err)
 	}
 
-	return usersToDeactivate, nil
+	return export, dropped, nil
 }
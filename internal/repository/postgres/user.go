@@ -3,7 +3,10 @@ package postgres
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+	"log"
+	"strings"
 
 	"github.com/guarref/pr-service-assignment/internal/errs"
 	"github.com/guarref/pr-service-assignment/internal/models"
@@ -20,10 +23,15 @@ func NewUserRepository(db *sqlx.DB) *UserRepository {
 
 func (ur *UserRepository) SetFlagIsActive(ctx context.Context, userID string, isActive bool) (*models.User, error) {
 
-	flagQuery := `UPDATE users
-		SET is_active = $2, updated_at = NOW()
-		WHERE user_id = $1
-		RETURNING user_id, username, team_name, is_active, created_at, updated_at`
+	flagQuery := `WITH updated AS (
+			UPDATE users
+			SET is_active = $2, updated_at = NOW()
+			WHERE user_id = $1
+			RETURNING user_id, username, is_active, created_at, updated_at
+		)
+		SELECT u.user_id, u.username, COALESCE(tm.team_name, '') AS team_name, u.is_active, u.created_at, u.updated_at
+		FROM updated u
+		LEFT JOIN team_memberships tm ON tm.user_id = u.user_id AND tm.is_primary`
 
 	var user models.User
 	err := ur.db.GetContext(ctx, &user, flagQuery, userID, isActive)
@@ -34,14 +42,22 @@ func (ur *UserRepository) SetFlagIsActive(ctx context.Context, userID string, is
 		return nil, fmt.Errorf("error updating is_active field: %w", err)
 	}
 
+	teams, err := ur.getUserTeams(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	user.Teams = teams
+
 	return &user, nil
 }
 
 func (ur *UserRepository) GetUserByID(ctx context.Context, userID string) (*models.User, error) {
 
-	userQuery := `SELECT user_id, username, team_name, is_active, created_at, updated_at
-		FROM users
-		WHERE user_id = $1`
+	userQuery := `SELECT u.user_id, u.username, COALESCE(tm.team_name, '') AS team_name, u.is_active, u.created_at, u.updated_at,
+			` + openReviewCountColumn + `
+		FROM users u
+		LEFT JOIN team_memberships tm ON tm.user_id = u.user_id AND tm.is_primary
+		WHERE u.user_id = $1`
 
 	var user models.User
 	if err := ur.db.GetContext(ctx, &user, userQuery, userID); err != nil {
@@ -51,15 +67,26 @@ func (ur *UserRepository) GetUserByID(ctx context.Context, userID string) (*mode
 		return nil, fmt.Errorf("error getting user: %w", err)
 	}
 
+	teams, err := ur.getUserTeams(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	user.Teams = teams
+
 	return &user, nil
 }
 
+// кандидаты — активные пользователи с активным членством в команде (в том числе не основной);
+// в поле team_name возвращается команда, по которой пользователь попал в выборку
+// open_review_count — нагрузка по всем командам пользователя, по ней выбираются ревьюверы нового PR
 func (ur *UserRepository) GetActiveUsersByTeam(ctx context.Context, teamName string, exceptUserID string) ([]*models.User, error) {
 
-	activeUsersQuery := `SELECT user_id, username, team_name, is_active, created_at, updated_at
-		FROM users
-		WHERE team_name = $1 AND is_active = true AND user_id != $2
-		ORDER BY user_id`
+	activeUsersQuery := `SELECT u.user_id, u.username, tm.team_name, u.is_active, u.created_at, u.updated_at,
+			` + openReviewCountColumn + `
+		FROM users u
+		INNER JOIN team_memberships tm ON tm.user_id = u.user_id
+		WHERE tm.team_name = $1 AND tm.is_active = true AND u.is_active = true AND u.user_id != $2
+		ORDER BY u.user_id`
 
 	var users []*models.User
 	if err := ur.db.SelectContext(ctx, &users, activeUsersQuery, teamName, exceptUserID); err != nil {
@@ -68,3 +95,227 @@ func (ur *UserRepository) GetActiveUsersByTeam(ctx context.Context, teamName str
 
 	return users, nil
 }
+
+// сортировка: колонка выборки и тип для приведения значения из курсора
+var userSortColumns = map[models.UserSort]struct {
+	column string
+	cast   string
+}{
+	models.UserSortUserID:          {column: "user_id", cast: "text"},
+	models.UserSortUserName:        {column: "username", cast: "text"},
+	models.UserSortOpenReviewCount: {column: "open_review_count", cast: "bigint"},
+}
+
+func (ur *UserRepository) ListUsers(ctx context.Context, filter models.UserListFilter) ([]*models.User, error) {
+
+	sortColumn, ok := userSortColumns[filter.Sort]
+	if !ok {
+		sortColumn = userSortColumns[models.UserSortUserName]
+	}
+
+	direction, op := "ASC", ">"
+	if filter.Desc {
+		direction, op = "DESC", "<"
+	}
+
+	listQuery := `WITH list AS (
+			SELECT u.user_id, u.username, COALESCE(tm.team_name, '') AS team_name, u.is_active, u.created_at, u.updated_at,
+				` + openReviewCountColumn + `
+			FROM users u
+			LEFT JOIN team_memberships tm ON tm.user_id = u.user_id AND tm.is_primary
+			WHERE ($1::text = '' OR EXISTS(
+				SELECT 1 FROM team_memberships f WHERE f.user_id = u.user_id AND f.team_name = $1::text
+			))
+			AND ($2::boolean IS NULL OR u.is_active = $2::boolean)
+			AND ($3::text = '' OR u.username ILIKE $3::text || '%')
+		)
+		SELECT user_id, username, team_name, is_active, created_at, updated_at, open_review_count
+		FROM list`
+
+	args := []any{filter.TeamName, filter.IsActive, escapeLike(filter.NamePrefix)}
+
+	if filter.After != nil {
+		args = append(args, filter.After.Key, filter.After.ID)
+		listQuery += fmt.Sprintf(`
+		WHERE (%s, user_id) %s ($4::%s, $5::text)`, sortColumn.column, op, sortColumn.cast)
+	}
+
+	args = append(args, filter.Limit)
+	listQuery += fmt.Sprintf(`
+		ORDER BY %s %s, user_id %s
+		LIMIT $%d`, sortColumn.column, direction, direction, len(args))
+
+	var users []*models.User
+	if err := ur.db.SelectContext(ctx, &users, listQuery, args...); err != nil {
+		return nil, fmt.Errorf("error listing users: %w", err)
+	}
+
+	if users == nil {
+		users = []*models.User{}
+	}
+
+	return users, nil
+}
+
+// managerID — активный lead или maintainer в одной из команд пользователя
+func (ur *UserRepository) IsManagerOf(ctx context.Context, managerID string, userID string) (bool, error) {
+
+	managerQuery := `SELECT EXISTS(
+			SELECT 1
+			FROM team_memberships mgr
+			INNER JOIN users u ON u.user_id = mgr.user_id
+			INNER JOIN team_memberships sub ON sub.team_name = mgr.team_name
+			WHERE mgr.user_id = $1 AND mgr.is_active AND u.is_active
+			AND mgr.role IN ('lead', 'maintainer')
+			AND sub.user_id = $2 AND sub.is_active
+		)`
+
+	var isManager bool
+	if err := ur.db.GetContext(ctx, &isManager, managerQuery, managerID, userID); err != nil {
+		return false, fmt.Errorf("error checking manager of user: %w", err)
+	}
+
+	return isManager, nil
+}
+
+// последнее удаление по исходному id (через subjectHash) или по псевдониму;
+// если с этим user_id снова есть живой пользователь, прежнее удаление к нему не относится
+const userErasureQuery = `SELECT pseudonym, requested_by, reassigned_reviews, erased_at
+	FROM user_erasures
+	WHERE (subject_hash = $2 OR pseudonym = $1)
+	AND NOT EXISTS (SELECT 1 FROM users WHERE user_id = $1 AND erased_at IS NULL)
+	ORDER BY erased_at DESC
+	LIMIT 1`
+
+func (ur *UserRepository) GetUserErasure(ctx context.Context, userID string, subjectHash string) (*models.UserErasure, error) {
+
+	var erasure models.UserErasure
+
+	if err := ur.db.GetContext(ctx, &erasure, userErasureQuery, userID, subjectHash); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errs.ErrNotFound
+		}
+		return nil, fmt.Errorf("error getting erasure: %w", err)
+	}
+
+	return &erasure, nil
+}
+
+// user_id и username заменяются псевдонимом, PR и ревью переезжают за счёт ON UPDATE CASCADE
+// открытые ревью пользователя переназначаются, членства в командах деактивируются
+// если живого пользователя с таким id нет, повторный вызов (по исходному id или по псевдониму) возвращает прежнюю запись аудита
+func (ur *UserRepository) EraseUser(ctx context.Context, userID string, subjectHash string, pseudonym string, requestedBy string) (*models.UserErasure, int, error) {
+
+	tx, err := ur.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, 0, fmt.Errorf("error begining transaction erase: %w", err)
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil && !errors.Is(err, sql.ErrTxDone) {
+			log.Printf("rollback error: %v", err)
+		}
+	}()
+
+	var erasure models.UserErasure
+
+	// параллельное удаление того же пользователя ждёт блокировку и после неё уже не находит живую строку
+	var lockedID string
+	lockQuery := `SELECT user_id FROM users WHERE user_id = $1 AND erased_at IS NULL FOR UPDATE`
+	if err := tx.GetContext(ctx, &lockedID, lockQuery, userID); err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			return nil, 0, fmt.Errorf("error locking user: %w", err)
+		}
+
+		if err := tx.GetContext(ctx, &erasure, userErasureQuery, userID, subjectHash); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return nil, 0, errs.ErrUserNotFound
+			}
+			return nil, 0, fmt.Errorf("error getting erasure: %w", err)
+		}
+
+		return &erasure, 0, nil
+	}
+
+	var openReviews int
+	openReviewsQuery := `SELECT COUNT(*)
+		FROM pr_reviewers rev
+		INNER JOIN pull_requests pr ON pr.pull_request_id = rev.pull_request_id
+		WHERE rev.user_id = $1 AND pr.status = 'OPEN'`
+	if err := tx.GetContext(ctx, &openReviews, openReviewsQuery, userID); err != nil {
+		return nil, 0, fmt.Errorf("error counting open reviews: %w", err)
+	}
+
+	dropped, err := reassignOpenReviews(ctx, tx, []string{userID}, models.EventReasonErasure)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	membershipsQuery := `UPDATE team_memberships SET is_active = false, updated_at = NOW() WHERE user_id = $1`
+	if _, err := tx.ExecContext(ctx, membershipsQuery, userID); err != nil {
+		return nil, 0, fmt.Errorf("error deactivating memberships: %w", err)
+	}
+
+	pseudonymizeQuery := `UPDATE users
+		SET user_id = $2, username = $2, is_active = false, erased_at = NOW(), updated_at = NOW()
+		WHERE user_id = $1`
+	if _, err := tx.ExecContext(ctx, pseudonymizeQuery, userID, pseudonym); err != nil {
+		return nil, 0, fmt.Errorf("error pseudonymizing user: %w", err)
+	}
+
+	// журнал PR пополняется только вставками; исключение — замена id удалённого пользователя псевдонимом
+	eventsQuery := `UPDATE pr_events SET user_id = $2 WHERE user_id = $1`
+	if _, err := tx.ExecContext(ctx, eventsQuery, userID, pseudonym); err != nil {
+		return nil, 0, fmt.Errorf("error pseudonymizing pull request events: %w", err)
+	}
+
+	// исходный id не должен остаться и в аудите чужих удалений
+	requesterQuery := `UPDATE user_erasures SET requested_by = $2 WHERE requested_by = $1`
+	if _, err := tx.ExecContext(ctx, requesterQuery, userID, pseudonym); err != nil {
+		return nil, 0, fmt.Errorf("error pseudonymizing erasure requester: %w", err)
+	}
+	if requestedBy == userID {
+		requestedBy = pseudonym
+	}
+
+	auditQuery := `INSERT INTO user_erasures (pseudonym, subject_hash, requested_by, reassigned_reviews, erased_at)
+		VALUES ($1, $2, $3, $4, NOW())
+		RETURNING pseudonym, requested_by, reassigned_reviews, erased_at`
+	if err := tx.GetContext(ctx, &erasure, auditQuery, pseudonym, subjectHash, requestedBy, openReviews); err != nil {
+		return nil, 0, fmt.Errorf("error writing erasure audit: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, 0, fmt.Errorf("error committing transaction erase: %w", err)
+	}
+
+	return &erasure, dropped, nil
+}
+
+func (ur *UserRepository) getUserTeams(ctx context.Context, userID string) ([]string, error) {
+
+	teamsQuery := `SELECT team_name
+		FROM team_memberships
+		WHERE user_id = $1
+		ORDER BY is_primary DESC, team_name`
+
+	var teams []string
+	if err := ur.db.SelectContext(ctx, &teams, teamsQuery, userID); err != nil {
+		return nil, fmt.Errorf("error getting user teams: %w", err)
+	}
+
+	if teams == nil {
+		teams = []string{}
+	}
+
+	return teams, nil
+}
+
+// число открытых PR, где пользователь (u) назначен ревьювером
+const openReviewCountColumn = `(SELECT COUNT(*) FROM pr_reviewers rev
+				INNER JOIN pull_requests pr ON pr.pull_request_id = rev.pull_request_id
+				WHERE rev.user_id = u.user_id AND pr.status = 'OPEN') AS open_review_count`
+
+// экранирует спецсимволы LIKE, чтобы префикс искался буквально
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
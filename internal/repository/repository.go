@@ -9,22 +9,59 @@ import (
 type TeamRepository interface {
 	CreateTeam(ctx context.Context, team *models.Team) error
 	GetTeamByName(ctx context.Context, name string) (*models.Team, error)
-	DeactivateUsersAndReassignPRs(ctx context.Context, teamName string, userIDs []string) ([]string, error)
+	DeactivateUsersAndReassignPRs(ctx context.Context, teamName string, userIDs []string) ([]string, int, error)
+	ArchiveTeam(ctx context.Context, teamName string) (*models.TeamArchive, int, error)
+	DeleteTeam(ctx context.Context, teamName string, force bool) (*models.TeamExport, int, error)
+	ListTeams(ctx context.Context, filter models.TeamListFilter) ([]*models.TeamSummary, error)
+	GetMemberRole(ctx context.Context, teamName string, userID string) (models.TeamRole, error)
 }
 
 type UserRepository interface {
 	SetFlagIsActive(ctx context.Context, userID string, isActive bool) (*models.User, error)
 	GetUserByID(ctx context.Context, userID string) (*models.User, error)
 	GetActiveUsersByTeam(ctx context.Context, teamName string, exceptUserID string) ([]*models.User, error)
+	ListUsers(ctx context.Context, filter models.UserListFilter) ([]*models.User, error)
+	IsManagerOf(ctx context.Context, managerID string, userID string) (bool, error)
+	EraseUser(ctx context.Context, userID string, subjectHash string, pseudonym string, requestedBy string) (*models.UserErasure, int, error)
+	GetUserErasure(ctx context.Context, userID string, subjectHash string) (*models.UserErasure, error)
 }
 
 type PullRequestRepository interface {
 	CreatePullRequest(ctx context.Context, pr *models.PullRequest) (*models.PullRequest, error)
 	MergePullRequestByID(ctx context.Context, prID string) (*models.PullRequest, error)
 	ReassignToPullRequest(ctx context.Context, prID string, oldUserID string) (*models.PullRequest, string, error)
-	GetPullRequestByReviewerID(ctx context.Context, userID string) ([]*models.PullRequestShort, error)
+	GetPullRequestByReviewerID(ctx context.Context, filter models.ReviewQueueFilter) ([]*models.PullRequestShort, error)
+	ListPullRequests(ctx context.Context, filter models.PullRequestListFilter) ([]*models.PullRequest, error)
+	SearchPullRequests(ctx context.Context, query string, limit int) ([]*models.PullRequest, error)
+	GetPullRequestTimeline(ctx context.Context, prID string) ([]*models.PullRequestEvent, error)
+	GetPullRequestsByIDs(ctx context.Context, prIDs []string) ([]*models.PullRequestDetails, error)
+	GetPullRequestsByAuthorID(ctx context.Context, filter models.AuthoredPullRequestsFilter) ([]*models.PullRequestDetails, error)
+}
+
+type ImportRepository interface {
+	GetAffectedTeams(ctx context.Context, rows []models.ImportRow) ([]*models.ImportAffectedTeam, error)
+	ImportTeams(ctx context.Context, rows []models.ImportRow, dryRun bool) (*models.ImportDiff, int, error)
+}
+
+type ScimRepository interface {
+	ListUsers(ctx context.Context, filter models.ScimUserFilter) ([]*models.User, int, error)
+	GetUser(ctx context.Context, userID string) (*models.User, error)
+	CreateUser(ctx context.Context, user *models.User) error
+	UpdateUser(ctx context.Context, userID string, userName string, isActive bool) (int, error)
+	ListGroups(ctx context.Context, filter models.ScimGroupFilter) ([]*models.Team, int, error)
+	GetGroup(ctx context.Context, teamName string) (*models.Team, error)
+	CreateGroup(ctx context.Context, teamName string, memberIDs []string) error
+	UpdateGroupMembers(ctx context.Context, teamName string, update models.ScimMembersUpdate) (int, error)
 }
 
 type StatsRepository interface {
-	GetStats(ctx context.Context, top int) (*models.Stats, error)
+	GetStats(ctx context.Context, top int, teamName string, window models.StatsWindow) (*models.Stats, error)
+	RefreshStatsSummary(ctx context.Context) error
+	GetTeamStats(ctx context.Context, teamName string, requiredReviewers int) (*models.TeamStats, error)
+	GetLatencyStats(ctx context.Context, window models.StatsWindow) (*models.LatencyStats, error)
+	GetReviewerLoads(ctx context.Context, teamName string, window models.StatsWindow) ([]*models.ReviewerLoad, error)
+	GetTimeseries(ctx context.Context, filter models.TimeseriesFilter) ([]*models.TimeseriesPoint, error)
+	GetUserStats(ctx context.Context, userID string) (*models.UserStats, error)
+	GetAgingStats(ctx context.Context, teamName string, oldest int) (*models.AgingStats, error)
+	GetDomainCounters(ctx context.Context) (*models.DomainCounters, error)
 }
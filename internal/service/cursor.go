@@ -0,0 +1,62 @@
+package service
+
+import (
+	"encoding/base64"
+	"encoding/json"
+
+	"github.com/guarref/pr-service-assignment/internal/errs"
+	"github.com/guarref/pr-service-assignment/internal/models"
+)
+
+var (
+	DefaultPageLimit = 50
+	MaxPageLimit     = 200
+)
+
+// курсор привязан к сортировке, чтобы его нельзя было применить к выборке с другим порядком
+type pageCursor struct {
+	Sort string `json:"s"`
+	Key  string `json:"k"`
+	ID   string `json:"id"`
+}
+
+func encodeCursor(sort string, c models.PageCursor) string {
+
+	raw, err := json.Marshal(pageCursor{Sort: sort, Key: c.Key, ID: c.ID})
+	if err != nil {
+		return ""
+	}
+
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+func decodeCursor(value string, sort string) (*models.PageCursor, error) {
+
+	if value == "" {
+		return nil, nil
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, errs.ErrInvalidCursor
+	}
+
+	var c pageCursor
+	if err := json.Unmarshal(raw, &c); err != nil || c.ID == "" || c.Sort != sort {
+		return nil, errs.ErrInvalidCursor
+	}
+
+	return &models.PageCursor{Key: c.Key, ID: c.ID}, nil
+}
+
+func pageLimit(limit *int) (int, error) {
+
+	if limit == nil {
+		return DefaultPageLimit, nil
+	}
+	if *limit <= 0 {
+		return 0, errs.ErrBadRequest
+	}
+
+	return min(*limit, MaxPageLimit), nil
+}
@@ -0,0 +1,356 @@
+package service
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/guarref/pr-service-assignment/internal/auth"
+	"github.com/guarref/pr-service-assignment/internal/errs"
+	"github.com/guarref/pr-service-assignment/internal/models"
+	"github.com/guarref/pr-service-assignment/internal/repository"
+)
+
+var MaxImportRows = 10000
+
+type ImportService struct {
+	importRepo repository.ImportRepository
+	teamRepo   repository.TeamRepository
+	dropped    DroppedReviewersRecorder
+}
+
+func NewImportService(importRepo repository.ImportRepository, teamRepo repository.TeamRepository, dropped DroppedReviewersRecorder) *ImportService {
+	return &ImportService{importRepo: importRepo, teamRepo: teamRepo, dropped: dropped}
+}
+
+// вызывающий должен быть lead или maintainer каждой существующей команды, которую затрагивает импорт
+func (is *ImportService) ImportTeams(ctx context.Context, format models.ImportFormat, r io.Reader, dryRun bool) (*models.ImportDiff, error) {
+
+	if _, ok := auth.CallerFromContext(ctx); !ok {
+		return nil, errs.ErrUnauthorized
+	}
+
+	return is.importTeams(ctx, format, r, dryRun, true)
+}
+
+// импорт от имени оператора (CLI с прямым доступом к БД), без проверки ролей в командах
+func (is *ImportService) ImportTeamsAsOperator(ctx context.Context, format models.ImportFormat, r io.Reader, dryRun bool) (*models.ImportDiff, error) {
+	return is.importTeams(ctx, format, r, dryRun, false)
+}
+
+// сначала файл целиком проверяется, и только без ошибок применяется одной транзакцией
+func (is *ImportService) importTeams(ctx context.Context, format models.ImportFormat, r io.Reader, dryRun bool, checkRoles bool) (*models.ImportDiff, error) {
+
+	var (
+		rows   []models.ImportRow
+		issues []errs.Issue
+	)
+
+	switch format {
+	case models.ImportFormatCSV:
+		rows, issues = parseImportCSV(r)
+	case models.ImportFormatYAML:
+		rows, issues = parseImportYAML(r)
+	default:
+		return nil, errs.ErrBadRequest
+	}
+
+	if len(issues) == 0 && len(rows) == 0 {
+		issues = append(issues, errs.Issue{Line: 1, Message: "file contains no rows"})
+	}
+	if len(rows) > MaxImportRows {
+		issues = append(issues, errs.Issue{Line: rows[MaxImportRows].Line, Message: fmt.Sprintf("too many rows, at most %d allowed", MaxImportRows)})
+	}
+
+	issues = append(issues, validateImportRows(rows)...)
+	if len(issues) > 0 {
+		slices.SortStableFunc(issues, func(a, b errs.Issue) int { return a.Line - b.Line })
+		return nil, errs.NewValidationError(errs.ErrInvalidImport, issues)
+	}
+
+	affected, err := is.importRepo.GetAffectedTeams(ctx, rows)
+	if err != nil {
+		return nil, fmt.Errorf("error getting teams affected by import: %w", err)
+	}
+
+	if issues := archivedTeamIssues(rows, affected); len(issues) > 0 {
+		return nil, errs.NewValidationError(errs.ErrInvalidImport, issues)
+	}
+
+	if checkRoles {
+		for _, team := range affected {
+			if err := authorize(ctx, is.teamRepo, team.TeamName, models.TeamRoleLead, models.TeamRoleMaintainer); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	diff, dropped, err := is.importRepo.ImportTeams(ctx, rows, dryRun)
+	if err != nil {
+		return nil, fmt.Errorf("error importing teams: %w", err)
+	}
+	recordDroppedReviewers(is.dropped, dropped)
+
+	return diff, nil
+}
+
+const (
+	importColumnTeam     = "team"
+	importColumnUserID   = "user_id"
+	importColumnUserName = "username"
+	importColumnActive   = "active"
+)
+
+var importColumns = []string{importColumnTeam, importColumnUserID, importColumnUserName, importColumnActive}
+
+// первая строка — заголовок с именами колонок, колонка active необязательна
+func parseImportCSV(r io.Reader) ([]models.ImportRow, []errs.Issue) {
+
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, []errs.Issue{csvIssue(err)}
+	}
+
+	var issues []errs.Issue
+
+	columns := make(map[string]int, len(header))
+	// индекс хранится со сдвигом на 1, чтобы 0 означал отсутствие колонки
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(name))
+		switch {
+		case !slices.Contains(importColumns, name):
+			issues = append(issues, errs.Issue{Line: 1, Message: fmt.Sprintf("unknown column %q", name)})
+		case columns[name] != 0:
+			issues = append(issues, errs.Issue{Line: 1, Message: fmt.Sprintf("duplicate column %q", name)})
+		default:
+			columns[name] = i + 1
+		}
+	}
+	for _, name := range []string{importColumnTeam, importColumnUserID, importColumnUserName} {
+		if columns[name] == 0 {
+			issues = append(issues, errs.Issue{Line: 1, Message: fmt.Sprintf("missing column %q", name)})
+		}
+	}
+	if len(issues) > 0 {
+		return nil, issues
+	}
+
+	field := func(record []string, name string) string {
+		if columns[name] == 0 {
+			return ""
+		}
+		return strings.TrimSpace(record[columns[name]-1])
+	}
+
+	var rows []models.ImportRow
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			// после ошибки разбора кавычек дальнейшие строки недостоверны
+			return rows, append(issues, csvIssue(err))
+		}
+
+		line, _ := reader.FieldPos(0)
+		if len(record) != len(header) {
+			issues = append(issues, errs.Issue{Line: line, Message: fmt.Sprintf("expected %d fields, got %d", len(header), len(record))})
+			continue
+		}
+
+		row := models.ImportRow{
+			Line:     line,
+			TeamName: field(record, importColumnTeam),
+			UserID:   field(record, importColumnUserID),
+			UserName: field(record, importColumnUserName),
+			IsActive: true,
+		}
+
+		if active := field(record, importColumnActive); active != "" {
+			isActive, err := strconv.ParseBool(strings.ToLower(active))
+			if err != nil {
+				issues = append(issues, errs.Issue{Line: line, Message: fmt.Sprintf("invalid active value %q", active)})
+				continue
+			}
+			row.IsActive = isActive
+		}
+
+		rows = append(rows, row)
+	}
+
+	return rows, issues
+}
+
+func csvIssue(err error) errs.Issue {
+
+	var parseErr *csv.ParseError
+	if errors.As(err, &parseErr) {
+		return errs.Issue{Line: parseErr.Line, Message: parseErr.Err.Error()}
+	}
+
+	return errs.Issue{Line: 1, Message: err.Error()}
+}
+
+// документ — список объектов с полями team, user_id, username, active
+func parseImportYAML(r io.Reader) ([]models.ImportRow, []errs.Issue) {
+
+	var doc yaml.Node
+	if err := yaml.NewDecoder(r).Decode(&doc); err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, nil
+		}
+		return nil, []errs.Issue{yamlIssue(err)}
+	}
+
+	if len(doc.Content) == 0 {
+		return nil, nil
+	}
+
+	root := doc.Content[0]
+	if root.Kind != yaml.SequenceNode {
+		return nil, []errs.Issue{{Line: root.Line, Message: "expected a list of rows"}}
+	}
+
+	var (
+		rows   []models.ImportRow
+		issues []errs.Issue
+	)
+
+	for _, item := range root.Content {
+		if item.Kind != yaml.MappingNode {
+			issues = append(issues, errs.Issue{Line: item.Line, Message: "expected a mapping with team, user_id, username, active"})
+			continue
+		}
+
+		row := models.ImportRow{Line: item.Line, IsActive: true}
+		valid := true
+
+		for i := 0; i+1 < len(item.Content); i += 2 {
+			key, value := item.Content[i], item.Content[i+1]
+
+			if value.Kind != yaml.ScalarNode {
+				issues = append(issues, errs.Issue{Line: value.Line, Message: fmt.Sprintf("field %q must be a scalar", key.Value)})
+				valid = false
+				continue
+			}
+
+			switch key.Value {
+			case importColumnTeam:
+				row.TeamName = strings.TrimSpace(value.Value)
+			case importColumnUserID:
+				row.UserID = strings.TrimSpace(value.Value)
+			case importColumnUserName:
+				row.UserName = strings.TrimSpace(value.Value)
+			case importColumnActive:
+				if err := value.Decode(&row.IsActive); err != nil {
+					issues = append(issues, errs.Issue{Line: value.Line, Message: fmt.Sprintf("invalid active value %q", value.Value)})
+					valid = false
+				}
+			default:
+				issues = append(issues, errs.Issue{Line: key.Line, Message: fmt.Sprintf("unknown field %q", key.Value)})
+				valid = false
+			}
+		}
+
+		if valid {
+			rows = append(rows, row)
+		}
+	}
+
+	return rows, issues
+}
+
+var yamlLineRe = regexp.MustCompile(`line (\d+)`)
+
+func yamlIssue(err error) errs.Issue {
+
+	line := 1
+	if m := yamlLineRe.FindStringSubmatch(err.Error()); m != nil {
+		line, _ = strconv.Atoi(m[1])
+	}
+
+	return errs.Issue{Line: line, Message: err.Error()}
+}
+
+// строки, ссылающиеся на архивную команду, отклоняются
+func archivedTeamIssues(rows []models.ImportRow, affected []*models.ImportAffectedTeam) []errs.Issue {
+
+	archived := make(map[string]bool)
+	for _, team := range affected {
+		if !team.IsActive {
+			archived[team.TeamName] = true
+		}
+	}
+	if len(archived) == 0 {
+		return nil
+	}
+
+	var issues []errs.Issue
+	for _, row := range rows {
+		if archived[row.TeamName] {
+			issues = append(issues, errs.Issue{Line: row.Line, Message: fmt.Sprintf("team %q is archived", row.TeamName)})
+		}
+	}
+
+	return issues
+}
+
+// общие правила для обоих форматов: обязательные поля, дубликаты и противоречия между строками
+func validateImportRows(rows []models.ImportRow) []errs.Issue {
+
+	var issues []errs.Issue
+
+	type membershipKey struct{ team, user string }
+	seenMemberships := make(map[membershipKey]int, len(rows))
+	seenUsers := make(map[string]models.ImportRow, len(rows))
+
+	for _, row := range rows {
+		if !IsValidTeamName(row.TeamName) {
+			issues = append(issues, errs.Issue{Line: row.Line, Message: fmt.Sprintf("invalid team name %q", row.TeamName)})
+		}
+		if row.UserID == "" {
+			issues = append(issues, errs.Issue{Line: row.Line, Message: "user_id is required"})
+			continue
+		}
+		if row.UserName == "" {
+			issues = append(issues, errs.Issue{Line: row.Line, Message: "username is required"})
+		}
+
+		key := membershipKey{team: row.TeamName, user: row.UserID}
+		if first, ok := seenMemberships[key]; ok {
+			issues = append(issues, errs.Issue{Line: row.Line, Message: fmt.Sprintf("duplicate row for user %q in team %q (first at line %d)", row.UserID, row.TeamName, first)})
+			continue
+		}
+		seenMemberships[key] = row.Line
+
+		first, ok := seenUsers[row.UserID]
+		if !ok {
+			seenUsers[row.UserID] = row
+			continue
+		}
+		if first.UserName != row.UserName {
+			issues = append(issues, errs.Issue{Line: row.Line, Message: fmt.Sprintf("username %q of user %q conflicts with %q at line %d", row.UserName, row.UserID, first.UserName, first.Line)})
+		}
+		if first.IsActive != row.IsActive {
+			issues = append(issues, errs.Issue{Line: row.Line, Message: fmt.Sprintf("active flag of user %q conflicts with line %d", row.UserID, first.Line)})
+		}
+	}
+
+	return issues
+}
@@ -4,20 +4,33 @@ import (
 	"context"
 	"fmt"
 	"math/rand"
+	"slices"
+	"strings"
 	"time"
+	"unicode/utf8"
 
+	"github.com/guarref/pr-service-assignment/internal/auth"
 	"github.com/guarref/pr-service-assignment/internal/errs"
 	"github.com/guarref/pr-service-assignment/internal/models"
 	"github.com/guarref/pr-service-assignment/internal/repository"
 )
 
+var (
+	// сколько ревьюверов назначается на новый PR
+	RequiredReviewers = 2
+
+	MaxBatchPullRequests = 100
+	MaxSearchQueryLength = 200
+)
+
 type PullRequestService struct {
 	prRepo   repository.PullRequestRepository
 	userRepo repository.UserRepository
+	teamRepo repository.TeamRepository
 }
 
-func NewPullRequestService(prRepo repository.PullRequestRepository, userRepo repository.UserRepository) *PullRequestService {
-	return &PullRequestService{prRepo: prRepo, userRepo: userRepo}
+func NewPullRequestService(prRepo repository.PullRequestRepository, userRepo repository.UserRepository, teamRepo repository.TeamRepository) *PullRequestService {
+	return &PullRequestService{prRepo: prRepo, userRepo: userRepo, teamRepo: teamRepo}
 }
 
 func (prs *PullRequestService) CreatePullRequest(ctx context.Context, pr *models.PullRequest) (*models.PullRequest, error) {
@@ -36,7 +49,7 @@ func (prs *PullRequestService) CreatePullRequest(ctx context.Context, pr *models
 		return nil, fmt.Errorf("error getting active users for team %s: %w", author.TeamName, err)
 	}
 
-	reviewers := randomUserSelection(activeUsers, 2)
+	reviewers := leastLoadedUserSelection(activeUsers, RequiredReviewers)
 	pr.AssignedReviewers = reviewers
 	pr.Status = models.PullRequestOpen
 
@@ -72,6 +85,11 @@ func (prs *PullRequestService) ReassignToPullRequest(ctx context.Context, prID s
 		return nil, "", errs.ErrBadRequest
 	}
 
+	// ручное переназначение — явный выбор ревьювера, поэтому доступно только lead основной команды автора
+	if err := prs.authorizeAuthorTeam(ctx, prID, models.TeamRoleLead); err != nil {
+		return nil, "", err
+	}
+
 	pr, newReviewerID, err := prs.prRepo.ReassignToPullRequest(ctx, prID, oldUserID)
 	if err != nil {
 		return nil, "", fmt.Errorf("error reassigning reviewer %s for pull request %s: %w", oldUserID, prID, err)
@@ -80,21 +98,351 @@ func (prs *PullRequestService) ReassignToPullRequest(ctx context.Context, prID s
 	return pr, newReviewerID, nil
 }
 
-func (prs *PullRequestService) GetPullRequestsByReviewer(ctx context.Context, userID string) ([]*models.PullRequestShort, error) {
+func (prs *PullRequestService) authorizeAuthorTeam(ctx context.Context, prID string, roles ...models.TeamRole) error {
+
+	// без токена отвечаем 401 до поиска PR, чтобы не раскрывать, существует ли он
+	if _, ok := auth.CallerFromContext(ctx); !ok {
+		return errs.ErrUnauthorized
+	}
+
+	found, err := prs.prRepo.GetPullRequestsByIDs(ctx, []string{prID})
+	if err != nil {
+		return fmt.Errorf("error getting pull request with id %s: %w", prID, err)
+	}
+	if len(found) == 0 {
+		return errs.ErrPullRequestNotFound
+	}
+
+	author, err := prs.userRepo.GetUserByID(ctx, found[0].AuthorID)
+	if err != nil {
+		return fmt.Errorf("error getting author of pull request %s: %w", prID, err)
+	}
+
+	return authorize(ctx, prs.teamRepo, author.TeamName, roles...)
+}
+
+func (prs *PullRequestService) GetPullRequestsByReviewer(ctx context.Context, filter models.ReviewQueueFilter, limit *int, cursor string) ([]*models.PullRequestShort, string, error) {
+
+	if filter.ReviewerID == "" {
+		return nil, "", errs.ErrBadRequest
+	}
+
+	switch filter.Sort {
+	case "":
+		filter.Sort = models.ReviewQueueSortNewest
+	case models.ReviewQueueSortOldest, models.ReviewQueueSortNewest, models.ReviewQueueSortPriority:
+	default:
+		return nil, "", errs.ErrBadRequest
+	}
+
+	switch filter.Status {
+	case "", models.PullRequestOpen, models.PullRequestMerged:
+	default:
+		return nil, "", errs.ErrBadRequest
+	}
+
+	pageSize, err := pageLimit(limit)
+	if err != nil {
+		return nil, "", err
+	}
+
+	sortKey := "review:" + string(filter.Sort)
+
+	after, err := decodeCursor(cursor, sortKey)
+	if err != nil {
+		return nil, "", err
+	}
+	if after != nil {
+		if filter.After, err = parseReviewQueueCursor(filter.Sort, after); err != nil {
+			return nil, "", err
+		}
+	}
+	filter.Limit = pageSize + 1
+
+	prsList, err := prs.prRepo.GetPullRequestByReviewerID(ctx, filter)
+	if err != nil {
+		return nil, "", fmt.Errorf("error getting pull requests for reviewer %s: %w", filter.ReviewerID, err)
+	}
+
+	var next string
+	if len(prsList) > pageSize {
+		prsList = prsList[:pageSize]
+		last := prsList[len(prsList)-1]
+
+		key := last.CreatedAt.Format(time.RFC3339Nano)
+		if filter.Sort == models.ReviewQueueSortPriority {
+			rank := "0"
+			if last.Status != models.PullRequestOpen {
+				rank = "1"
+			}
+			key = rank + "|" + last.AssignedAt.Format(time.RFC3339Nano)
+		}
+		next = encodeCursor(sortKey, models.PageCursor{Key: key, ID: last.PullRequestID})
+	}
+
+	return prsList, next, nil
+}
+
+// ключ курсора: created_at для oldest/newest, "<ранг статуса>|<assigned_at>" для priority
+func parseReviewQueueCursor(sort models.ReviewQueueSort, c *models.PageCursor) (*models.ReviewQueueCursor, error) {
+
+	key := c.Key
+	parsed := &models.ReviewQueueCursor{ID: c.ID}
+
+	if sort == models.ReviewQueueSortPriority {
+		rank, at, ok := strings.Cut(key, "|")
+		if !ok {
+			return nil, errs.ErrInvalidCursor
+		}
+		switch rank {
+		case "0":
+		case "1":
+			parsed.StatusRank = 1
+		default:
+			return nil, errs.ErrInvalidCursor
+		}
+		key = at
+	}
+
+	t, err := time.Parse(time.RFC3339Nano, key)
+	if err != nil {
+		return nil, errs.ErrInvalidCursor
+	}
+	parsed.Time = t
+
+	return parsed, nil
+}
+
+func (prs *PullRequestService) GetPullRequest(ctx context.Context, prID string) (*models.PullRequestDetails, error) {
+
+	if prID == "" {
+		return nil, errs.ErrBadRequest
+	}
+
+	found, err := prs.prRepo.GetPullRequestsByIDs(ctx, []string{prID})
+	if err != nil {
+		return nil, fmt.Errorf("error getting pull request with id %s: %w", prID, err)
+	}
+	if len(found) == 0 {
+		return nil, errs.ErrPullRequestNotFound
+	}
+
+	setReviewStates(found[0])
+
+	return found[0], nil
+}
+
+// PR возвращаются в порядке запроса (без повторов), ненайденные id — отдельным списком
+func (prs *PullRequestService) GetPullRequestsBatch(ctx context.Context, prIDs []string) ([]*models.PullRequestDetails, []string, error) {
+
+	ids := make([]string, 0, len(prIDs))
+	for _, id := range prIDs {
+		if id == "" {
+			return nil, nil, errs.ErrBadRequest
+		}
+		if !slices.Contains(ids, id) {
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) == 0 || len(ids) > MaxBatchPullRequests {
+		return nil, nil, errs.ErrBadRequest
+	}
+
+	found, err := prs.prRepo.GetPullRequestsByIDs(ctx, ids)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error getting pull requests batch: %w", err)
+	}
+
+	byID := make(map[string]*models.PullRequestDetails, len(found))
+	for _, pr := range found {
+		setReviewStates(pr)
+		byID[pr.PullRequestID] = pr
+	}
+
+	result := make([]*models.PullRequestDetails, 0, len(found))
+	notFound := []string{}
+	for _, id := range ids {
+		if pr, ok := byID[id]; ok {
+			result = append(result, pr)
+			continue
+		}
+		notFound = append(notFound, id)
+	}
+
+	return result, notFound, nil
+}
+
+func (prs *PullRequestService) GetPullRequestsByAuthor(ctx context.Context, filter models.AuthoredPullRequestsFilter, limit *int, cursor string) ([]*models.PullRequestDetails, string, error) {
+
+	if filter.AuthorID == "" {
+		return nil, "", errs.ErrBadRequest
+	}
+
+	switch filter.Status {
+	case "", models.PullRequestOpen, models.PullRequestMerged:
+	default:
+		return nil, "", errs.ErrBadRequest
+	}
+
+	pageSize, err := pageLimit(limit)
+	if err != nil {
+		return nil, "", err
+	}
+
+	const sortKey = "authored:newest"
+
+	after, err := decodeCursor(cursor, sortKey)
+	if err != nil {
+		return nil, "", err
+	}
+	filter.After = after
+	filter.Limit = pageSize + 1
+
+	list, err := prs.prRepo.GetPullRequestsByAuthorID(ctx, filter)
+	if err != nil {
+		return nil, "", fmt.Errorf("error getting pull requests for author %s: %w", filter.AuthorID, err)
+	}
+
+	var next string
+	if len(list) > pageSize {
+		list = list[:pageSize]
+		last := list[len(list)-1]
+		next = encodeCursor(sortKey, models.PageCursor{Key: last.CreatedAt.Format(time.RFC3339Nano), ID: last.PullRequestID})
+	}
+
+	for _, pr := range list {
+		setReviewStates(pr)
+	}
+
+	return list, next, nil
+}
+
+func setReviewStates(pr *models.PullRequestDetails) {
+
+	state := models.ReviewStatePending
+	if pr.Status == models.PullRequestMerged {
+		state = models.ReviewStateCompleted
+	}
 
-	if userID == "" {
+	for i := range pr.Reviewers {
+		pr.Reviewers[i].State = state
+	}
+}
+
+func (prs *PullRequestService) ListPullRequests(ctx context.Context, filter models.PullRequestListFilter, limit *int, cursor string) ([]*models.PullRequest, string, error) {
+
+	switch filter.Sort {
+	case "":
+		filter.Sort = models.PullRequestSortCreatedAt
+	case models.PullRequestSortCreatedAt, models.PullRequestSortMergedAt,
+		models.PullRequestSortPullRequestID, models.PullRequestSortPullRequestName:
+	default:
+		return nil, "", errs.ErrBadRequest
+	}
+
+	switch filter.Status {
+	case "", models.PullRequestOpen, models.PullRequestMerged:
+	default:
+		return nil, "", errs.ErrBadRequest
+	}
+
+	if filter.TeamName != "" && !IsValidTeamName(filter.TeamName) {
+		return nil, "", errs.ErrBadRequest
+	}
+	if isEmptyRange(filter.CreatedFrom, filter.CreatedTo) || isEmptyRange(filter.MergedFrom, filter.MergedTo) {
+		return nil, "", errs.ErrBadRequest
+	}
+
+	pageSize, err := pageLimit(limit)
+	if err != nil {
+		return nil, "", err
+	}
+
+	sortKey := string(filter.Sort) + ":asc"
+	if filter.Desc {
+		sortKey = string(filter.Sort) + ":desc"
+	}
+
+	after, err := decodeCursor(cursor, sortKey)
+	if err != nil {
+		return nil, "", err
+	}
+	filter.After = after
+	filter.Limit = pageSize + 1
+
+	list, err := prs.prRepo.ListPullRequests(ctx, filter)
+	if err != nil {
+		return nil, "", fmt.Errorf("error listing pull requests: %w", err)
+	}
+
+	var next string
+	if len(list) > pageSize {
+		list = list[:pageSize]
+		last := list[len(list)-1]
+
+		var key string
+		switch filter.Sort {
+		case models.PullRequestSortCreatedAt:
+			key = last.CreatedAt.Format(time.RFC3339Nano)
+		case models.PullRequestSortMergedAt:
+			// так же, как в выборке: открытые PR стоят после всех смерженных
+			key = "infinity"
+			if last.MergedAt != nil {
+				key = last.MergedAt.Format(time.RFC3339Nano)
+			}
+		case models.PullRequestSortPullRequestID:
+			key = last.PullRequestID
+		case models.PullRequestSortPullRequestName:
+			key = last.PullRequestName
+		}
+		next = encodeCursor(sortKey, models.PageCursor{Key: key, ID: last.PullRequestID})
+	}
+
+	return list, next, nil
+}
+
+func (prs *PullRequestService) GetPullRequestTimeline(ctx context.Context, prID string) ([]*models.PullRequestEvent, error) {
+
+	if prID == "" {
+		return nil, errs.ErrBadRequest
+	}
+
+	events, err := prs.prRepo.GetPullRequestTimeline(ctx, prID)
+	if err != nil {
+		return nil, fmt.Errorf("error getting timeline of pull request %s: %w", prID, err)
+	}
+
+	return events, nil
+}
+
+func (prs *PullRequestService) SearchPullRequests(ctx context.Context, query string, limit *int) ([]*models.PullRequest, error) {
+
+	query = strings.TrimSpace(query)
+	if query == "" || utf8.RuneCountInString(query) > MaxSearchQueryLength {
 		return nil, errs.ErrBadRequest
 	}
 
-	prsList, err := prs.prRepo.GetPullRequestByReviewerID(ctx, userID)
+	pageSize, err := pageLimit(limit)
 	if err != nil {
-		return nil, fmt.Errorf("error getting pull requests for reviewer %s: %w", userID, err)
+		return nil, err
 	}
 
-	return prsList, nil
+	found, err := prs.prRepo.SearchPullRequests(ctx, query, pageSize)
+	if err != nil {
+		return nil, fmt.Errorf("error searching pull requests: %w", err)
+	}
+
+	return found, nil
+}
+
+// полуоткрытый диапазон [from, to) пуст, если to не позже from
+func isEmptyRange(from, to *time.Time) bool {
+	return from != nil && to != nil && !to.After(*from)
 }
 
-func randomUserSelection(activeUsers []*models.User, num int) []string {
+// выбирает num наименее загруженных пользователей (OpenReviewCount считается по всем их командам),
+// при равной нагрузке — случайно
+func leastLoadedUserSelection(activeUsers []*models.User, num int) []string {
 
 	if len(activeUsers) == 0 {
 		return []string{}
@@ -106,6 +454,9 @@ func randomUserSelection(activeUsers []*models.User, num int) []string {
 	r.Shuffle(len(activeUsers), func(i, j int) {
 		activeUsers[i], activeUsers[j] = activeUsers[j], activeUsers[i]
 	})
+	slices.SortStableFunc(activeUsers, func(a, b *models.User) int {
+		return a.OpenReviewCount - b.OpenReviewCount
+	})
 
 	result := make([]string, count)
 	for i := 0; i < count; i++ {
@@ -0,0 +1,604 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/guarref/pr-service-assignment/internal/errs"
+	"github.com/guarref/pr-service-assignment/internal/models"
+	"github.com/guarref/pr-service-assignment/internal/repository"
+)
+
+// SCIM 2.0 (RFC 7643/7644): User — пользователь, id и userName совпадают с user_id,
+// displayName — username; Group — команда, id и displayName совпадают с team_name
+type ScimService struct {
+	scimRepo repository.ScimRepository
+	teamRepo repository.TeamRepository
+	dropped  DroppedReviewersRecorder
+}
+
+func NewScimService(scimRepo repository.ScimRepository, teamRepo repository.TeamRepository, dropped DroppedReviewersRecorder) *ScimService {
+	return &ScimService{scimRepo: scimRepo, teamRepo: teamRepo, dropped: dropped}
+}
+
+// startIndex считается с 1; count=0 допустим и возвращает только totalResults
+func scimPage(startIndex, count *int) (offset int, limit int) {
+
+	offset, limit = 0, DefaultPageLimit
+	if startIndex != nil && *startIndex > 1 {
+		offset = *startIndex - 1
+	}
+	if count != nil {
+		limit = min(max(*count, 0), MaxPageLimit)
+	}
+
+	return offset, limit
+}
+
+func (ss *ScimService) ListUsers(ctx context.Context, filter string, startIndex, count *int) ([]*models.User, int, error) {
+
+	conditions, err := parseScimFilter(filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var f models.ScimUserFilter
+	matchable := true
+	for _, cond := range conditions {
+		switch cond.attr {
+		case "id", "username":
+			matchable = setScimFilterValue(&f.UserID, cond.value) && matchable
+		case "displayname":
+			matchable = setScimFilterValue(&f.UserName, cond.value) && matchable
+		case "active":
+			isActive, ok := cond.value.(bool)
+			if !ok {
+				return nil, 0, errs.ErrInvalidFilter
+			}
+			if f.IsActive != nil && *f.IsActive != isActive {
+				matchable = false
+			}
+			f.IsActive = &isActive
+		default:
+			return nil, 0, errs.ErrInvalidFilter
+		}
+	}
+	if !matchable {
+		return []*models.User{}, 0, nil
+	}
+
+	f.Offset, f.Limit = scimPage(startIndex, count)
+
+	users, total, err := ss.scimRepo.ListUsers(ctx, f)
+	if err != nil {
+		return nil, 0, fmt.Errorf("error listing scim users: %w", err)
+	}
+
+	return users, total, nil
+}
+
+func (ss *ScimService) GetUser(ctx context.Context, userID string) (*models.User, error) {
+
+	user, err := ss.scimRepo.GetUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("error receiving scim user: %w", err)
+	}
+
+	return user, nil
+}
+
+func (ss *ScimService) CreateUser(ctx context.Context, user *models.User) (*models.User, error) {
+
+	user.UserID = strings.TrimSpace(user.UserID)
+	user.UserName = strings.TrimSpace(user.UserName)
+	if user.UserID == "" {
+		return nil, errs.ErrBadRequest
+	}
+	if user.UserName == "" {
+		user.UserName = user.UserID
+	}
+
+	if err := ss.scimRepo.CreateUser(ctx, user); err != nil {
+		return nil, fmt.Errorf("error creating scim user: %w", err)
+	}
+
+	return ss.GetUser(ctx, user.UserID)
+}
+
+// PUT: userName менять нельзя, остальные атрибуты заменяются целиком
+func (ss *ScimService) ReplaceUser(ctx context.Context, userID string, user *models.User) (*models.User, error) {
+
+	if user.UserID != "" && user.UserID != userID {
+		return nil, errs.ErrImmutableAttribute
+	}
+
+	userName := strings.TrimSpace(user.UserName)
+	if userName == "" {
+		userName = userID
+	}
+
+	dropped, err := ss.scimRepo.UpdateUser(ctx, userID, userName, user.IsActive)
+	if err != nil {
+		return nil, fmt.Errorf("error replacing scim user: %w", err)
+	}
+	recordDroppedReviewers(ss.dropped, dropped)
+
+	return ss.GetUser(ctx, userID)
+}
+
+// поддерживаются add/replace для active, displayName и name.formatted;
+// атрибуты, которых нет в модели (emails, name.givenName и т.п.), игнорируются
+func (ss *ScimService) PatchUser(ctx context.Context, userID string, ops []models.ScimPatchOperation) (*models.User, error) {
+
+	if len(ops) == 0 {
+		return nil, errs.ErrBadRequest
+	}
+
+	user, err := ss.scimRepo.GetUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("error receiving scim user: %w", err)
+	}
+
+	userName, isActive := user.UserName, user.IsActive
+
+	apply := func(attr string, value any) error {
+		switch strings.ToLower(attr) {
+		case "active":
+			v, ok := scimBool(value)
+			if !ok {
+				return errs.ErrBadRequest
+			}
+			isActive = v
+		case "displayname", "name.formatted":
+			v, ok := value.(string)
+			if !ok || strings.TrimSpace(v) == "" {
+				return errs.ErrBadRequest
+			}
+			userName = strings.TrimSpace(v)
+		case "username", "id":
+			if v, ok := value.(string); !ok || v != userID {
+				return errs.ErrImmutableAttribute
+			}
+		}
+		return nil
+	}
+
+	for _, op := range ops {
+		switch strings.ToLower(op.Op) {
+		case "add", "replace":
+		default:
+			return nil, errs.ErrBadRequest
+		}
+
+		if op.Path != "" {
+			if err := apply(op.Path, op.Value); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		values, ok := op.Value.(map[string]any)
+		if !ok {
+			return nil, errs.ErrBadRequest
+		}
+		for attr, value := range values {
+			if err := apply(attr, value); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	dropped, err := ss.scimRepo.UpdateUser(ctx, userID, userName, isActive)
+	if err != nil {
+		return nil, fmt.Errorf("error patching scim user: %w", err)
+	}
+	recordDroppedReviewers(ss.dropped, dropped)
+
+	return ss.GetUser(ctx, userID)
+}
+
+// DELETE не удаляет пользователя (на него ссылается история PR), а деактивирует его
+func (ss *ScimService) DeprovisionUser(ctx context.Context, userID string) error {
+
+	user, err := ss.scimRepo.GetUser(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("error receiving scim user: %w", err)
+	}
+
+	dropped, err := ss.scimRepo.UpdateUser(ctx, userID, user.UserName, false)
+	if err != nil {
+		return fmt.Errorf("error deprovisioning scim user: %w", err)
+	}
+	recordDroppedReviewers(ss.dropped, dropped)
+
+	return nil
+}
+
+func (ss *ScimService) ListGroups(ctx context.Context, filter string, startIndex, count *int) ([]*models.Team, int, error) {
+
+	conditions, err := parseScimFilter(filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var f models.ScimGroupFilter
+	matchable := true
+	for _, cond := range conditions {
+		switch cond.attr {
+		case "id", "displayname":
+			matchable = setScimFilterValue(&f.TeamName, cond.value) && matchable
+		case "members", "members.value":
+			matchable = setScimFilterValue(&f.MemberID, cond.value) && matchable
+		default:
+			return nil, 0, errs.ErrInvalidFilter
+		}
+	}
+	if !matchable {
+		return []*models.Team{}, 0, nil
+	}
+
+	f.Offset, f.Limit = scimPage(startIndex, count)
+
+	teams, total, err := ss.scimRepo.ListGroups(ctx, f)
+	if err != nil {
+		return nil, 0, fmt.Errorf("error listing scim groups: %w", err)
+	}
+
+	return teams, total, nil
+}
+
+func (ss *ScimService) GetGroup(ctx context.Context, teamName string) (*models.Team, error) {
+
+	team, err := ss.scimRepo.GetGroup(ctx, teamName)
+	if err != nil {
+		return nil, fmt.Errorf("error receiving scim group: %w", err)
+	}
+
+	return team, nil
+}
+
+func (ss *ScimService) CreateGroup(ctx context.Context, teamName string, memberIDs []string) (*models.Team, error) {
+
+	if !IsValidTeamName(teamName) {
+		return nil, errs.ErrBadRequest
+	}
+
+	if err := ss.scimRepo.CreateGroup(ctx, teamName, uniqueScimIDs(memberIDs)); err != nil {
+		return nil, fmt.Errorf("error creating scim group: %w", err)
+	}
+
+	return ss.GetGroup(ctx, teamName)
+}
+
+// PUT: displayName менять нельзя, состав участников заменяется целиком
+func (ss *ScimService) ReplaceGroup(ctx context.Context, teamName string, displayName string, memberIDs []string) (*models.Team, error) {
+
+	if displayName != "" && displayName != teamName {
+		return nil, errs.ErrImmutableAttribute
+	}
+
+	update := models.ScimMembersUpdate{Add: uniqueScimIDs(memberIDs), Replace: true}
+	dropped, err := ss.scimRepo.UpdateGroupMembers(ctx, teamName, update)
+	if err != nil {
+		return nil, fmt.Errorf("error replacing scim group: %w", err)
+	}
+	recordDroppedReviewers(ss.dropped, dropped)
+
+	return ss.GetGroup(ctx, teamName)
+}
+
+// операции применяются по порядку и сворачиваются в одно изменение состава группы
+func (ss *ScimService) PatchGroup(ctx context.Context, teamName string, ops []models.ScimPatchOperation) (*models.Team, error) {
+
+	if len(ops) == 0 {
+		return nil, errs.ErrBadRequest
+	}
+
+	var update models.ScimMembersUpdate
+
+	addMembers := func(ids []string) {
+		for _, id := range ids {
+			update.Remove = slices.DeleteFunc(update.Remove, func(r string) bool { return r == id })
+			if !slices.Contains(update.Add, id) {
+				update.Add = append(update.Add, id)
+			}
+		}
+	}
+	removeMembers := func(ids []string) {
+		for _, id := range ids {
+			update.Add = slices.DeleteFunc(update.Add, func(a string) bool { return a == id })
+			if !slices.Contains(update.Remove, id) {
+				update.Remove = append(update.Remove, id)
+			}
+		}
+	}
+	replaceMembers := func(ids []string) {
+		update = models.ScimMembersUpdate{Replace: true}
+		addMembers(ids)
+	}
+
+	checkDisplayName := func(value any) error {
+		if v, ok := value.(string); !ok || v != teamName {
+			return errs.ErrImmutableAttribute
+		}
+		return nil
+	}
+
+	for _, op := range ops {
+		opName := strings.ToLower(op.Op)
+		path := strings.ToLower(op.Path)
+
+		switch {
+		case path == "displayname" && (opName == "add" || opName == "replace"):
+			if err := checkDisplayName(op.Value); err != nil {
+				return nil, err
+			}
+
+		case path == "members" && opName == "add":
+			ids, err := scimMemberIDs(op.Value)
+			if err != nil {
+				return nil, err
+			}
+			addMembers(ids)
+
+		case path == "members" && opName == "replace":
+			ids, err := scimMemberIDs(op.Value)
+			if err != nil {
+				return nil, err
+			}
+			replaceMembers(ids)
+
+		case path == "members" && opName == "remove":
+			// без value удаляются все участники
+			if op.Value == nil {
+				replaceMembers(nil)
+				continue
+			}
+			ids, err := scimMemberIDs(op.Value)
+			if err != nil {
+				return nil, err
+			}
+			removeMembers(ids)
+
+		case strings.HasPrefix(path, "members[") && opName == "remove":
+			id, err := scimMemberPathValue(op.Path)
+			if err != nil {
+				return nil, err
+			}
+			removeMembers([]string{id})
+
+		case path == "" && (opName == "add" || opName == "replace"):
+			values, ok := op.Value.(map[string]any)
+			if !ok {
+				return nil, errs.ErrBadRequest
+			}
+			for attr, value := range values {
+				switch strings.ToLower(attr) {
+				case "displayname":
+					if err := checkDisplayName(value); err != nil {
+						return nil, err
+					}
+				case "members":
+					ids, err := scimMemberIDs(value)
+					if err != nil {
+						return nil, err
+					}
+					if opName == "replace" {
+						replaceMembers(ids)
+					} else {
+						addMembers(ids)
+					}
+				}
+			}
+
+		default:
+			return nil, errs.ErrBadRequest
+		}
+	}
+
+	dropped, err := ss.scimRepo.UpdateGroupMembers(ctx, teamName, update)
+	if err != nil {
+		return nil, fmt.Errorf("error patching scim group: %w", err)
+	}
+	recordDroppedReviewers(ss.dropped, dropped)
+
+	return ss.GetGroup(ctx, teamName)
+}
+
+// DELETE архивирует команду: история сохраняется, участники без других команд деактивируются
+func (ss *ScimService) DeleteGroup(ctx context.Context, teamName string) error {
+
+	_, dropped, err := ss.teamRepo.ArchiveTeam(ctx, teamName)
+	if err != nil {
+		return fmt.Errorf("error deleting scim group: %w", err)
+	}
+	recordDroppedReviewers(ss.dropped, dropped)
+
+	return nil
+}
+
+func setScimFilterValue(dst *string, value any) bool {
+
+	v, ok := value.(string)
+	if !ok {
+		return false
+	}
+	if *dst != "" && *dst != v {
+		return false
+	}
+	*dst = v
+
+	return true
+}
+
+// IdP присылают active и как bool, и как строку ("False" у Azure AD)
+func scimBool(value any) (bool, bool) {
+
+	switch v := value.(type) {
+	case bool:
+		return v, true
+	case string:
+		switch strings.ToLower(v) {
+		case "true":
+			return true, true
+		case "false":
+			return false, true
+		}
+	}
+
+	return false, false
+}
+
+// members: [{"value": "u1"}, ...]
+func scimMemberIDs(value any) ([]string, error) {
+
+	items, ok := value.([]any)
+	if !ok {
+		return nil, errs.ErrBadRequest
+	}
+
+	ids := make([]string, 0, len(items))
+	for _, item := range items {
+		member, ok := item.(map[string]any)
+		if !ok {
+			return nil, errs.ErrBadRequest
+		}
+		id, ok := member["value"].(string)
+		if !ok || id == "" {
+			return nil, errs.ErrBadRequest
+		}
+		ids = append(ids, id)
+	}
+
+	return uniqueScimIDs(ids), nil
+}
+
+// path вида members[value eq "u1"]
+func scimMemberPathValue(path string) (string, error) {
+
+	// префикс уже проверен без учёта регистра
+	inner := path[len("members["):]
+	if !strings.HasSuffix(inner, "]") {
+		return "", errs.ErrInvalidFilter
+	}
+
+	conditions, err := parseScimFilter(strings.TrimSuffix(inner, "]"))
+	if err != nil {
+		return "", err
+	}
+	if len(conditions) != 1 || conditions[0].attr != "value" {
+		return "", errs.ErrInvalidFilter
+	}
+
+	id, ok := conditions[0].value.(string)
+	if !ok {
+		return "", errs.ErrInvalidFilter
+	}
+
+	return id, nil
+}
+
+func uniqueScimIDs(ids []string) []string {
+
+	unique := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if !slices.Contains(unique, id) {
+			unique = append(unique, id)
+		}
+	}
+
+	return unique
+}
+
+// условие фильтра: имя атрибута в нижнем регистре и значение (string или bool)
+type scimCondition struct {
+	attr  string
+	value any
+}
+
+// разбирает фильтр вида `attr eq "value" and attr eq true`; другие операторы не поддерживаются
+func parseScimFilter(filter string) ([]scimCondition, error) {
+
+	tokens, err := scimFilterTokens(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	var conditions []scimCondition
+	for i := 0; i < len(tokens); i += 4 {
+		if i+3 > len(tokens) || tokens[i].quoted || tokens[i+1].quoted || !strings.EqualFold(tokens[i+1].text, "eq") {
+			return nil, errs.ErrInvalidFilter
+		}
+
+		cond := scimCondition{attr: strings.ToLower(tokens[i].text)}
+
+		value := tokens[i+2]
+		switch {
+		case value.quoted:
+			cond.value = value.text
+		case strings.EqualFold(value.text, "true"):
+			cond.value = true
+		case strings.EqualFold(value.text, "false"):
+			cond.value = false
+		default:
+			return nil, errs.ErrInvalidFilter
+		}
+		conditions = append(conditions, cond)
+
+		if i+3 < len(tokens) && (tokens[i+3].quoted || !strings.EqualFold(tokens[i+3].text, "and") || i+4 == len(tokens)) {
+			return nil, errs.ErrInvalidFilter
+		}
+	}
+
+	return conditions, nil
+}
+
+type scimToken struct {
+	text   string
+	quoted bool
+}
+
+func scimFilterTokens(filter string) ([]scimToken, error) {
+
+	var tokens []scimToken
+
+	for i := 0; i < len(filter); {
+		switch c := filter[i]; {
+		case c == ' ' || c == '\t':
+			i++
+
+		case c == '"':
+			// строка в кавычках — JSON-строка, ищем закрывающую кавычку с учётом экранирования
+			end := i + 1
+			for end < len(filter) && filter[end] != '"' {
+				if filter[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			if end >= len(filter) {
+				return nil, errs.ErrInvalidFilter
+			}
+
+			var text string
+			if err := json.Unmarshal([]byte(filter[i:end+1]), &text); err != nil {
+				return nil, errs.ErrInvalidFilter
+			}
+			tokens = append(tokens, scimToken{text: text, quoted: true})
+			i = end + 1
+
+		default:
+			end := i
+			for end < len(filter) && filter[end] != ' ' && filter[end] != '\t' && filter[end] != '"' {
+				end++
+			}
+			tokens = append(tokens, scimToken{text: filter[i:end]})
+			i = end
+		}
+	}
+
+	return tokens, nil
+}
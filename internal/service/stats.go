@@ -1,25 +1,56 @@
 package service
 
 import (
+	"cmp"
 	"context"
 	"fmt"
+	"log"
+	"math"
+	"slices"
+	"sync"
+	"time"
 
 	"github.com/guarref/pr-service-assignment/internal/errs"
 	"github.com/guarref/pr-service-assignment/internal/models"
 	"github.com/guarref/pr-service-assignment/internal/repository"
 )
 
-var Limit = 10
+var (
+	Limit = 10
+
+	// сколько самых перегруженных и недогруженных ревьюверов показывать по команде
+	FairnessOutliers = 3
+
+	// число интервалов временного ряда, если начало периода не задано, и максимум для одного запроса
+	DefaultTimeseriesBuckets = 30
+	MaxTimeseriesBuckets     = 400
+
+	// сколько самых старых открытых PR возвращает отчёт по возрасту
+	DefaultAgingOldest = 10
+	MaxAgingOldest     = 100
+
+	// сколько живёт ответ GetStats в кэше процесса и сколько разных запросов в нём хранится
+	StatsCacheTTL        = 5 * time.Second
+	StatsCacheMaxEntries = 1000
+)
+
+type statsCacheEntry struct {
+	stats   *models.Stats
+	expires time.Time
+}
 
 type StatsService struct {
 	repo repository.StatsRepository
+
+	mu    sync.Mutex
+	cache map[string]statsCacheEntry
 }
 
 func NewStatsService(repo repository.StatsRepository) *StatsService {
-	return &StatsService{repo: repo}
+	return &StatsService{repo: repo, cache: make(map[string]statsCacheEntry)}
 }
 
-func (s *StatsService) GetStats(ctx context.Context, top *int) (*models.Stats, error) {
+func (s *StatsService) GetStats(ctx context.Context, top *int, teamName string, window models.StatsWindow) (*models.Stats, error) {
 
 	var topRew int
 
@@ -30,10 +61,343 @@ func (s *StatsService) GetStats(ctx context.Context, top *int) (*models.Stats, e
 		topRew = min(*top, Limit)
 	}
 
-	stats, err := s.repo.GetStats(ctx, topRew)
+	if teamName != "" && !IsValidTeamName(teamName) {
+		return nil, errs.ErrBadRequest
+	}
+	if isEmptyRange(window.From, window.To) {
+		return nil, errs.ErrBadRequest
+	}
+
+	key := statsCacheKey(topRew, teamName, window)
+	if stats, ok := s.cachedStats(key); ok {
+		return stats, nil
+	}
+
+	stats, err := s.repo.GetStats(ctx, topRew, teamName, window)
 	if err != nil {
 		return nil, fmt.Errorf("error getting stats: %w", err)
 	}
 
+	s.storeStats(key, stats)
+
+	return stats, nil
+}
+
+func statsCacheKey(top int, teamName string, window models.StatsWindow) string {
+
+	key := fmt.Sprintf("%d|%s", top, teamName)
+	for _, t := range []*time.Time{window.From, window.To} {
+		key += "|"
+		if t != nil {
+			key += t.UTC().Format(time.RFC3339Nano)
+		}
+	}
+
+	return key
+}
+
+func (s *StatsService) cachedStats(key string) (*models.Stats, bool) {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, ok := s.cache[key]
+	if !ok || time.Now().After(entry.expires) {
+		return nil, false
+	}
+
+	return entry.stats, true
+}
+
+// при переполнении сначала удаляются устаревшие записи; если места всё равно нет, ответ не кэшируется
+func (s *StatsService) storeStats(key string, stats *models.Stats) {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+
+	if len(s.cache) >= StatsCacheMaxEntries {
+		for k, entry := range s.cache {
+			if now.After(entry.expires) {
+				delete(s.cache, k)
+			}
+		}
+		if len(s.cache) >= StatsCacheMaxEntries {
+			return
+		}
+	}
+
+	s.cache[key] = statsCacheEntry{stats: stats, expires: now.Add(StatsCacheTTL)}
+}
+
+// пересчитывает сводную статистику в БД сразу при запуске и затем с заданным интервалом, пока не отменён ctx
+func (s *StatsService) RunSummaryRefresh(ctx context.Context, interval time.Duration) {
+
+	// представления после миграции пусты, без этого до первого тика статистика была бы нулевой
+	s.refreshSummary(ctx)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.refreshSummary(ctx)
+		}
+	}
+}
+
+func (s *StatsService) refreshSummary(ctx context.Context) {
+	if err := s.repo.RefreshStatsSummary(ctx); err != nil && ctx.Err() == nil {
+		log.Printf("error refreshing stats summary: %v", err)
+	}
+}
+
+func (s *StatsService) GetTeamStats(ctx context.Context, teamName string) (*models.TeamStats, error) {
+
+	if !IsValidTeamName(teamName) {
+		return nil, errs.ErrBadRequest
+	}
+
+	stats, err := s.repo.GetTeamStats(ctx, teamName, RequiredReviewers)
+	if err != nil {
+		return nil, fmt.Errorf("error getting stats for team %s: %w", teamName, err)
+	}
+
+	return stats, nil
+}
+
+func (s *StatsService) GetLatencyStats(ctx context.Context, window models.StatsWindow) (*models.LatencyStats, error) {
+
+	if isEmptyRange(window.From, window.To) {
+		return nil, errs.ErrBadRequest
+	}
+
+	stats, err := s.repo.GetLatencyStats(ctx, window)
+	if err != nil {
+		return nil, fmt.Errorf("error getting latency stats: %w", err)
+	}
+
+	return stats, nil
+}
+
+func (s *StatsService) GetFairnessStats(ctx context.Context, teamName string, window models.StatsWindow) ([]*models.TeamFairness, error) {
+
+	if teamName != "" && !IsValidTeamName(teamName) {
+		return nil, errs.ErrBadRequest
+	}
+	if isEmptyRange(window.From, window.To) {
+		return nil, errs.ErrBadRequest
+	}
+
+	loads, err := s.repo.GetReviewerLoads(ctx, teamName, window)
+	if err != nil {
+		return nil, fmt.Errorf("error getting reviewer loads: %w", err)
+	}
+
+	result := []*models.TeamFairness{}
+
+	// нагрузки приходят отсортированными по команде
+	for start := 0; start < len(loads); {
+		end := start
+		for end < len(loads) && loads[end].TeamName == loads[start].TeamName {
+			end++
+		}
+
+		result = append(result, teamFairness(loads[start].TeamName, loads[start:end]))
+		start = end
+	}
+
+	return result, nil
+}
+
+// стандартное отклонение — по генеральной совокупности; коэффициент Джини — 0 при равной нагрузке,
+// стремится к 1, когда все назначения приходятся на одного ревьювера
+func teamFairness(teamName string, loads []*models.ReviewerLoad) *models.TeamFairness {
+
+	sorted := slices.Clone(loads)
+	slices.SortStableFunc(sorted, func(a, b *models.ReviewerLoad) int {
+		return cmp.Compare(a.Assignments, b.Assignments)
+	})
+
+	n := len(sorted)
+	tf := &models.TeamFairness{
+		TeamName:    teamName,
+		Reviewers:   n,
+		Min:         sorted[0].Assignments,
+		Max:         sorted[n-1].Assignments,
+		Overloaded:  []*models.ReviewerLoad{},
+		Underloaded: []*models.ReviewerLoad{},
+	}
+
+	var weighted int
+	for i, l := range sorted {
+		tf.TotalAssignments += l.Assignments
+		weighted += (i + 1) * l.Assignments
+	}
+
+	tf.Mean = float64(tf.TotalAssignments) / float64(n)
+
+	var variance float64
+	for _, l := range sorted {
+		d := float64(l.Assignments) - tf.Mean
+		variance += d * d
+	}
+	tf.StdDev = math.Sqrt(variance / float64(n))
+
+	if tf.TotalAssignments > 0 {
+		tf.Gini = 2*float64(weighted)/(float64(n)*float64(tf.TotalAssignments)) - float64(n+1)/float64(n)
+	}
+
+	for i := n - 1; i >= 0 && len(tf.Overloaded) < FairnessOutliers; i-- {
+		if float64(sorted[i].Assignments) <= tf.Mean {
+			break
+		}
+		tf.Overloaded = append(tf.Overloaded, sorted[i])
+	}
+
+	for i := 0; i < n && len(tf.Underloaded) < FairnessOutliers; i++ {
+		if float64(sorted[i].Assignments) >= tf.Mean {
+			break
+		}
+		tf.Underloaded = append(tf.Underloaded, sorted[i])
+	}
+
+	return tf
+}
+
+// по умолчанию ряд строится по дням и заканчивается текущим моментом
+func (s *StatsService) GetTimeseries(ctx context.Context, metric models.TimeseriesMetric, bucket models.TimeseriesBucket, teamName string, window models.StatsWindow) (*models.Timeseries, error) {
+
+	switch metric {
+	case models.TimeseriesPullRequestsCreated, models.TimeseriesPullRequestsMerged, models.TimeseriesAssignments:
+	default:
+		return nil, errs.ErrBadRequest
+	}
+
+	switch bucket {
+	case "":
+		bucket = models.TimeseriesDay
+	case models.TimeseriesDay, models.TimeseriesWeek, models.TimeseriesMonth:
+	default:
+		return nil, errs.ErrBadRequest
+	}
+
+	if teamName != "" && !IsValidTeamName(teamName) {
+		return nil, errs.ErrBadRequest
+	}
+	if isEmptyRange(window.From, window.To) {
+		return nil, errs.ErrBadRequest
+	}
+
+	to := time.Now().UTC()
+	if window.To != nil {
+		to = window.To.UTC()
+	}
+
+	from := addBuckets(truncateToBucket(to, bucket), bucket, 1-DefaultTimeseriesBuckets)
+	if window.From != nil {
+		from = window.From.UTC()
+	}
+
+	if !to.After(from) {
+		return nil, errs.ErrBadRequest
+	}
+
+	buckets := 0
+	for start := truncateToBucket(from, bucket); start.Before(to); start = addBuckets(start, bucket, 1) {
+		buckets++
+		if buckets > MaxTimeseriesBuckets {
+			return nil, errs.ErrBadRequest
+		}
+	}
+
+	filter := models.TimeseriesFilter{
+		Metric:   metric,
+		Bucket:   bucket,
+		TeamName: teamName,
+		From:     from,
+		To:       to,
+	}
+
+	points, err := s.repo.GetTimeseries(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("error getting %s timeseries: %w", metric, err)
+	}
+
+	return &models.Timeseries{
+		Metric:   metric,
+		Bucket:   bucket,
+		TeamName: teamName,
+		From:     from,
+		To:       to,
+		Points:   points,
+	}, nil
+}
+
+// начало интервала, в который попадает t; совпадает с date_trunc в PostgreSQL для UTC
+func truncateToBucket(t time.Time, bucket models.TimeseriesBucket) time.Time {
+
+	t = t.UTC()
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+
+	switch bucket {
+	case models.TimeseriesWeek:
+		return day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+	case models.TimeseriesMonth:
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	default:
+		return day
+	}
+}
+
+func addBuckets(t time.Time, bucket models.TimeseriesBucket, n int) time.Time {
+
+	switch bucket {
+	case models.TimeseriesWeek:
+		return t.AddDate(0, 0, 7*n)
+	case models.TimeseriesMonth:
+		return t.AddDate(0, n, 0)
+	default:
+		return t.AddDate(0, 0, n)
+	}
+}
+
+func (s *StatsService) GetUserStats(ctx context.Context, userID string) (*models.UserStats, error) {
+
+	if userID == "" {
+		return nil, errs.ErrBadRequest
+	}
+
+	stats, err := s.repo.GetUserStats(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("error getting stats for user %s: %w", userID, err)
+	}
+
+	return stats, nil
+}
+
+func (s *StatsService) GetAgingStats(ctx context.Context, teamName string, limit *int) (*models.AgingStats, error) {
+
+	oldest := DefaultAgingOldest
+	if limit != nil {
+		if *limit <= 0 {
+			return nil, errs.ErrBadRequest
+		}
+		oldest = min(*limit, MaxAgingOldest)
+	}
+
+	if teamName != "" && !IsValidTeamName(teamName) {
+		return nil, errs.ErrBadRequest
+	}
+
+	stats, err := s.repo.GetAgingStats(ctx, teamName, oldest)
+	if err != nil {
+		return nil, fmt.Errorf("error getting aging stats: %w", err)
+	}
+
 	return stats, nil
 }
@@ -2,9 +2,12 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"slices"
 	"unicode"
 
+	"github.com/guarref/pr-service-assignment/internal/auth"
 	"github.com/guarref/pr-service-assignment/internal/errs"
 	"github.com/guarref/pr-service-assignment/internal/models"
 	"github.com/guarref/pr-service-assignment/internal/repository"
@@ -12,10 +15,24 @@ import (
 
 type TeamService struct {
 	teamRepo repository.TeamRepository
+	userRepo repository.UserRepository
+	dropped  DroppedReviewersRecorder
 }
 
-func NewTeamService(teamRepo repository.TeamRepository) *TeamService {
-	return &TeamService{teamRepo: teamRepo}
+func NewTeamService(teamRepo repository.TeamRepository, userRepo repository.UserRepository, dropped DroppedReviewersRecorder) *TeamService {
+	return &TeamService{teamRepo: teamRepo, userRepo: userRepo, dropped: dropped}
+}
+
+// учёт ревьюверов, снятых без замены (в приложении — метрика NO_CANDIDATE); nil отключает учёт
+type DroppedReviewersRecorder interface {
+	AddDroppedReviewers(n int)
+}
+
+// вызывается только после успешного коммита, чтобы откаченные изменения не попадали в метрику
+func recordDroppedReviewers(recorder DroppedReviewersRecorder, n int) {
+	if recorder != nil && n > 0 {
+		recorder.AddDroppedReviewers(n)
+	}
 }
 
 func (ts *TeamService) CreateTeam(ctx context.Context, team *models.Team) error {
@@ -26,6 +43,30 @@ func (ts *TeamService) CreateTeam(ctx context.Context, team *models.Team) error
 	if len(team.Members) == 0 {
 		return errs.ErrBadRequest
 	}
+	if team.ParentTeam != nil && (!IsValidTeamName(*team.ParentTeam) || *team.ParentTeam == team.TeamName) {
+		return errs.ErrBadRequest
+	}
+
+	for i := range team.Members {
+		switch team.Members[i].Role {
+		case "":
+			team.Members[i].Role = models.TeamRoleMember
+		case models.TeamRoleMember, models.TeamRoleLead, models.TeamRoleMaintainer:
+		default:
+			return errs.ErrBadRequest
+		}
+	}
+
+	callerID, ok := auth.CallerFromContext(ctx)
+	if !ok {
+		return errs.ErrUnauthorized
+	}
+
+	for i := range team.Members {
+		if err := ts.checkNewMember(ctx, callerID, &team.Members[i]); err != nil {
+			return err
+		}
+	}
 
 	if err := ts.teamRepo.CreateTeam(ctx, team); err != nil {
 		return fmt.Errorf("team creation error: %w", err)
@@ -34,6 +75,42 @@ func (ts *TeamService) CreateTeam(ctx context.Context, team *models.Team) error
 	return nil
 }
 
+// lead может быть только создатель команды; maintainer другому участнику выдаёт лишь тот,
+// кто уже lead или maintainer общей с ним команды. Существующих пользователей создание команды не меняет:
+// имя, флаг активности и основная команда должны совпадать с текущими, новая команда основной для них не становится
+func (ts *TeamService) checkNewMember(ctx context.Context, callerID string, member *models.TeamMember) error {
+
+	if member.UserID != callerID {
+		switch member.Role {
+		case models.TeamRoleLead:
+			return errs.ErrForbidden
+		case models.TeamRoleMaintainer:
+			isManager, err := ts.userRepo.IsManagerOf(ctx, callerID, member.UserID)
+			if err != nil {
+				return fmt.Errorf("error checking manager of user %s: %w", member.UserID, err)
+			}
+			if !isManager {
+				return errs.ErrForbidden
+			}
+		}
+	}
+
+	existing, err := ts.userRepo.GetUserByID(ctx, member.UserID)
+	if errors.Is(err, errs.ErrUserNotFound) {
+		member.IsPrimary = true
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("error getting user %s: %w", member.UserID, err)
+	}
+
+	if member.IsPrimary || existing.UserName != member.UserName || existing.IsActive != member.IsActive {
+		return errs.ErrImmutableAttribute
+	}
+
+	return nil
+}
+
 func (ts *TeamService) GetTeamByName(ctx context.Context, teamName string) (*models.Team, error) {
 
 	if !IsValidTeamName(teamName) {
@@ -48,20 +125,119 @@ func (ts *TeamService) GetTeamByName(ctx context.Context, teamName string) (*mod
 	return team, nil
 }
 
+func (ts *TeamService) ListTeams(ctx context.Context, filter models.TeamListFilter, limit *int, cursor string) ([]*models.TeamSummary, string, error) {
+
+	pageSize, err := pageLimit(limit)
+	if err != nil {
+		return nil, "", err
+	}
+
+	after, err := decodeCursor(cursor, teamSortKey)
+	if err != nil {
+		return nil, "", err
+	}
+	filter.After = after
+	filter.Limit = pageSize + 1
+
+	teams, err := ts.teamRepo.ListTeams(ctx, filter)
+	if err != nil {
+		return nil, "", fmt.Errorf("error listing teams: %w", err)
+	}
+
+	var next string
+	if len(teams) > pageSize {
+		teams = teams[:pageSize]
+		next = encodeCursor(teamSortKey, models.PageCursor{ID: teams[len(teams)-1].TeamName})
+	}
+
+	return teams, next, nil
+}
+
+// команды всегда отдаются по возрастанию имени
+const teamSortKey = "team_name:asc"
+
 func (ts *TeamService) DeactivateUsersAndReassignPRs(ctx context.Context, teamName string, userIDs []string) ([]string, error) {
 
 	if !IsValidTeamName(teamName) {
 		return nil, errs.ErrBadRequest
 	}
 
-	deactivated, err := ts.teamRepo.DeactivateUsersAndReassignPRs(ctx, teamName, userIDs)
+	if err := ts.authorize(ctx, teamName, models.TeamRoleLead); err != nil {
+		return nil, err
+	}
+
+	deactivated, dropped, err := ts.teamRepo.DeactivateUsersAndReassignPRs(ctx, teamName, userIDs)
 	if err != nil {
 		return nil, fmt.Errorf("error deactivating users for team %s: %w", teamName, err)
 	}
+	recordDroppedReviewers(ts.dropped, dropped)
 
 	return deactivated, nil
 }
 
+func (ts *TeamService) ArchiveTeam(ctx context.Context, teamName string) (*models.TeamArchive, error) {
+
+	if !IsValidTeamName(teamName) {
+		return nil, errs.ErrBadRequest
+	}
+
+	if err := ts.authorize(ctx, teamName, models.TeamRoleLead, models.TeamRoleMaintainer); err != nil {
+		return nil, err
+	}
+
+	archive, dropped, err := ts.teamRepo.ArchiveTeam(ctx, teamName)
+	if err != nil {
+		return nil, fmt.Errorf("error archiving team %s: %w", teamName, err)
+	}
+	recordDroppedReviewers(ts.dropped, dropped)
+
+	return archive, nil
+}
+
+func (ts *TeamService) DeleteTeam(ctx context.Context, teamName string, force bool) (*models.TeamExport, error) {
+
+	if !IsValidTeamName(teamName) {
+		return nil, errs.ErrBadRequest
+	}
+
+	if err := ts.authorize(ctx, teamName, models.TeamRoleLead, models.TeamRoleMaintainer); err != nil {
+		return nil, err
+	}
+
+	export, dropped, err := ts.teamRepo.DeleteTeam(ctx, teamName, force)
+	if err != nil {
+		return nil, fmt.Errorf("error deleting team %s: %w", teamName, err)
+	}
+	recordDroppedReviewers(ts.dropped, dropped)
+
+	return export, nil
+}
+
+// вызывающий должен быть активным участником команды с одной из ролей
+func (ts *TeamService) authorize(ctx context.Context, teamName string, roles ...models.TeamRole) error {
+	return authorize(ctx, ts.teamRepo, teamName, roles...)
+}
+
+// вызывающий должен быть активным участником команды с одной из ролей
+func authorize(ctx context.Context, teamRepo repository.TeamRepository, teamName string, roles ...models.TeamRole) error {
+
+	callerID, ok := auth.CallerFromContext(ctx)
+	if !ok {
+		return errs.ErrUnauthorized
+	}
+
+	role, err := teamRepo.GetMemberRole(ctx, teamName, callerID)
+	if err != nil {
+		return fmt.Errorf("error getting role of %s in team %s: %w", callerID, teamName, err)
+	}
+
+	if !slices.Contains(roles, role) {
+		return errs.ErrForbidden
+	}
+
+	return nil
+}
+
 func IsValidTeamName(name string) bool {
 
 	if name == "" {
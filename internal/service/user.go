@@ -2,8 +2,15 @@ package service
 
 import (
 	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"fmt"
+	"strconv"
 
+	"github.com/guarref/pr-service-assignment/internal/auth"
 	"github.com/guarref/pr-service-assignment/internal/errs"
 	"github.com/guarref/pr-service-assignment/internal/models"
 	"github.com/guarref/pr-service-assignment/internal/repository"
@@ -11,10 +18,15 @@ import (
 
 type UserService struct {
 	userRepo repository.UserRepository
+	teamRepo repository.TeamRepository
+	dropped  DroppedReviewersRecorder
+
+	// ключ HMAC для subjectHash удалённых пользователей
+	pseudonymKey []byte
 }
 
-func NewUserService(userRepo repository.UserRepository) *UserService {
-	return &UserService{userRepo: userRepo}
+func NewUserService(userRepo repository.UserRepository, teamRepo repository.TeamRepository, pseudonymSecret string, dropped DroppedReviewersRecorder) *UserService {
+	return &UserService{userRepo: userRepo, teamRepo: teamRepo, dropped: dropped, pseudonymKey: []byte(pseudonymSecret)}
 }
 
 func (us *UserService) SetFlagIsActive(ctx context.Context, userID string, isActive bool) (*models.User, error) {
@@ -58,3 +70,145 @@ func (us *UserService) GetActiveUsersByTeam(ctx context.Context, teamName string
 
 	return users, nil
 }
+
+func (us *UserService) ListUsers(ctx context.Context, filter models.UserListFilter, limit *int, cursor string) ([]*models.User, string, error) {
+
+	switch filter.Sort {
+	case "":
+		filter.Sort = models.UserSortUserName
+	case models.UserSortUserID, models.UserSortUserName, models.UserSortOpenReviewCount:
+	default:
+		return nil, "", errs.ErrBadRequest
+	}
+
+	if filter.TeamName != "" && !IsValidTeamName(filter.TeamName) {
+		return nil, "", errs.ErrBadRequest
+	}
+
+	pageSize, err := pageLimit(limit)
+	if err != nil {
+		return nil, "", err
+	}
+
+	sortKey := userSortKey(filter)
+	after, err := decodeCursor(cursor, sortKey)
+	if err != nil {
+		return nil, "", err
+	}
+	filter.After = after
+
+	// запрашиваем на одну запись больше, чтобы понять, есть ли следующая страница
+	filter.Limit = pageSize + 1
+
+	users, err := us.userRepo.ListUsers(ctx, filter)
+	if err != nil {
+		return nil, "", fmt.Errorf("error listing users: %w", err)
+	}
+
+	var next string
+	if len(users) > pageSize {
+		users = users[:pageSize]
+		last := users[len(users)-1]
+
+		key := last.UserID
+		switch filter.Sort {
+		case models.UserSortUserName:
+			key = last.UserName
+		case models.UserSortOpenReviewCount:
+			key = strconv.Itoa(last.OpenReviewCount)
+		}
+		next = encodeCursor(sortKey, models.PageCursor{Key: key, ID: last.UserID})
+	}
+
+	return users, next, nil
+}
+
+// удалить пользователя может он сам или lead/maintainer одной из его команд
+func (us *UserService) EraseUser(ctx context.Context, userID string) (*models.UserErasure, error) {
+
+	if userID == "" {
+		return nil, errs.ErrBadRequest
+	}
+
+	callerID, ok := auth.CallerFromContext(ctx)
+	if !ok {
+		return nil, errs.ErrUnauthorized
+	}
+
+	subjectHash := us.subjectHash(userID)
+
+	if callerID != userID {
+		if err := us.authorizeErasure(ctx, userID, subjectHash); err != nil {
+			return nil, err
+		}
+	}
+
+	pseudonym, err := newPseudonym()
+	if err != nil {
+		return nil, err
+	}
+
+	erasure, dropped, err := us.userRepo.EraseUser(ctx, userID, subjectHash, pseudonym, callerID)
+	if err != nil {
+		return nil, fmt.Errorf("error erasing user %s: %w", userID, err)
+	}
+	recordDroppedReviewers(us.dropped, dropped)
+
+	return erasure, nil
+}
+
+// удаление необратимо, поэтому чужой профиль может удалить только lead основной команды пользователя
+func (us *UserService) authorizeErasure(ctx context.Context, userID string, subjectHash string) error {
+
+	subject, err := us.userRepo.GetUserByID(ctx, userID)
+	if errors.Is(err, errs.ErrUserNotFound) {
+		// после удаления пользователь числится под псевдонимом — повторный запрос проверяется по нему
+		prior, priorErr := us.userRepo.GetUserErasure(ctx, userID, subjectHash)
+		if errors.Is(priorErr, errs.ErrNotFound) {
+			return errs.ErrUserNotFound
+		}
+		if priorErr != nil {
+			return fmt.Errorf("error getting erasure of %s: %w", userID, priorErr)
+		}
+		subject, err = us.userRepo.GetUserByID(ctx, prior.Pseudonym)
+	}
+	if err != nil {
+		return fmt.Errorf("error getting user %s: %w", userID, err)
+	}
+
+	if subject.TeamName == "" {
+		return errs.ErrForbidden
+	}
+
+	return authorize(ctx, us.teamRepo, subject.TeamName, models.TeamRoleLead)
+}
+
+// псевдоним случайный и не выводится из user_id, поэтому по нему нельзя подобрать исходный id
+func newPseudonym() (string, error) {
+
+	buf := make([]byte, 8)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("error generating pseudonym: %w", err)
+	}
+
+	return "erased-" + hex.EncodeToString(buf), nil
+}
+
+// по subjectHash повторный запрос с исходным user_id находит прежнее удаление;
+// без ключа хеш нельзя сопоставить перебором коротких user_id
+func (us *UserService) subjectHash(userID string) string {
+
+	mac := hmac.New(sha256.New, us.pseudonymKey)
+	mac.Write([]byte(userID))
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func userSortKey(filter models.UserListFilter) string {
+
+	if filter.Desc {
+		return string(filter.Sort) + ":desc"
+	}
+
+	return string(filter.Sort) + ":asc"
+}
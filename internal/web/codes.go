@@ -41,25 +41,46 @@ func mapErrorToHTTPResponse(c echo.Context, err error) error {
 			code = omodels.NOTASSIGNED
 		case errs.ErrNoCandidate:
 			code = omodels.NOCANDIDATE
+		case errs.ErrTeamHasHistory:
+			code = omodels.TEAMHASHISTORY
+		case errs.ErrUnauthorized:
+			code = omodels.UNAUTHORIZED
+		case errs.ErrForbidden:
+			code = omodels.FORBIDDEN
 
 		case errs.ErrTeamNotFound,
+			errs.ErrParentTeamNotFound,
 			errs.ErrUserNotFound,
 			errs.ErrPullRequestNotFound,
 			errs.ErrNotFound:
 			code = omodels.NOTFOUND
 
 		case errs.ErrBadRequest,
-			errs.ErrInvalidJSON:
+			errs.ErrInvalidJSON,
+			errs.ErrInvalidCursor,
+			errs.ErrInvalidImport:
 			code = omodels.BADREQUEST
 
 		default:
 			code = omodels.ErrorResponseErrorCode(respErr.Code)
 		}
 
+		c.Set(errorCodeKey, string(code))
 		resp := NewErrorResponse(code, respErr.Message)
+
+		var validationErr *errs.ValidationError
+		if errors.As(err, &validationErr) {
+			issues := make([]omodels.ImportIssue, 0, len(validationErr.Issues))
+			for _, issue := range validationErr.Issues {
+				issues = append(issues, omodels.ImportIssue{Line: issue.Line, Message: issue.Message})
+			}
+			resp.Error.Issues = &issues
+		}
+
 		return c.JSON(respErr.StatusCode, resp)
 	}
 
+	c.Set(errorCodeKey, internalErrorCode)
 	resp := NewErrorResponse(omodels.NOTFOUND, "internal server error")
 
 	return c.JSON(http.StatusInternalServerError, resp)
@@ -69,25 +90,74 @@ func toOAPITeam(t *models.Team) omodels.Team {
 
 	members := make([]omodels.TeamMember, 0, len(t.Members))
 	for _, m := range t.Members {
+		isPrimary := m.IsPrimary
+		role := omodels.TeamMemberRole(m.Role)
 		members = append(members, omodels.TeamMember{
-			UserId:   m.UserID,
-			Username: m.UserName,
-			IsActive: m.IsActive,
+			UserId:    m.UserID,
+			Username:  m.UserName,
+			IsActive:  m.IsActive,
+			IsPrimary: &isPrimary,
+			Role:      &role,
+		})
+	}
+
+	return omodels.Team{TeamName: t.TeamName, ParentTeam: t.ParentTeam, Members: members}
+}
+
+func toOAPITeamExport(e *models.TeamExport) omodels.TeamExport {
+
+	prs := make([]omodels.PullRequest, 0, len(e.PullRequests))
+	for _, pr := range e.PullRequests {
+		prs = append(prs, toOAPIPullRequest(pr))
+	}
+
+	reviews := make([]omodels.ReviewAssignment, 0, len(e.Reviews))
+	for _, r := range e.Reviews {
+		reviews = append(reviews, omodels.ReviewAssignment{
+			PullRequestId: r.PullRequestID,
+			UserId:        r.UserID,
+			AssignedAt:    r.AssignedAt,
 		})
 	}
 
-	return omodels.Team{TeamName: t.TeamName, Members: members}
+	return omodels.TeamExport{
+		Team:         toOAPITeam(e.Team),
+		PullRequests: prs,
+		Reviews:      reviews,
+	}
 }
 
 func toOAPIUser(u *models.User) omodels.User {
+
+	var teams *[]string
+	if u.Teams != nil {
+		t := append([]string(nil), u.Teams...)
+		teams = &t
+	}
+
 	return omodels.User{
 		UserId:   u.UserID,
 		Username: u.UserName,
 		TeamName: u.TeamName,
+		Teams:    teams,
 		IsActive: u.IsActive,
 	}
 }
 
+func toOAPIUserDetails(u *models.User) omodels.UserDetails {
+
+	teams := append([]string{}, u.Teams...)
+
+	return omodels.UserDetails{
+		UserId:          u.UserID,
+		Username:        u.UserName,
+		TeamName:        u.TeamName,
+		Teams:           teams,
+		IsActive:        u.IsActive,
+		OpenReviewCount: u.OpenReviewCount,
+	}
+}
+
 func toOAPIPullRequest(pr *models.PullRequest) omodels.PullRequest {
 
 	var mergedAt *time.Time
@@ -109,12 +179,61 @@ func toOAPIPullRequest(pr *models.PullRequest) omodels.PullRequest {
 	}
 }
 
+func toOAPIPullRequestDetails(pr *models.PullRequestDetails) omodels.PullRequestDetails {
+
+	base := toOAPIPullRequest(&pr.PullRequest)
+
+	reviewers := make([]omodels.PullRequestReviewer, 0, len(pr.Reviewers))
+	for _, r := range pr.Reviewers {
+		reviewers = append(reviewers, omodels.PullRequestReviewer{
+			UserId:      r.UserID,
+			Username:    r.UserName,
+			TeamName:    r.TeamName,
+			IsActive:    r.IsActive,
+			AssignedAt:  r.AssignedAt,
+			HeldSeconds: r.HeldSeconds,
+			ReviewState: omodels.PullRequestReviewerReviewState(r.State),
+		})
+	}
+
+	return omodels.PullRequestDetails{
+		PullRequestId:     base.PullRequestId,
+		PullRequestName:   base.PullRequestName,
+		AuthorId:          base.AuthorId,
+		Status:            omodels.PullRequestDetailsStatus(base.Status),
+		AssignedReviewers: base.AssignedReviewers,
+		Reviewers:         reviewers,
+		CreatedAt:         base.CreatedAt,
+		MergedAt:          base.MergedAt,
+	}
+}
+
+func toOAPIPullRequestEvent(e *models.PullRequestEvent) omodels.PullRequestEvent {
+
+	var reason *omodels.PullRequestEventReason
+	if e.Reason != nil {
+		r := omodels.PullRequestEventReason(*e.Reason)
+		reason = &r
+	}
+
+	return omodels.PullRequestEvent{
+		EventId:   e.EventID,
+		Type:      omodels.PullRequestEventType(e.Type),
+		UserId:    e.UserID,
+		Reason:    reason,
+		CreatedAt: e.CreatedAt,
+	}
+}
+
 func toOAPIPullRequestShort(pr *models.PullRequestShort) omodels.PullRequestShort {
 	return omodels.PullRequestShort{
 		PullRequestId:   pr.PullRequestID,
 		PullRequestName: pr.PullRequestName,
 		AuthorId:        pr.AuthorID,
 		Status:          omodels.PullRequestShortStatus(pr.Status),
+		CreatedAt:       pr.CreatedAt,
+		AssignedAt:      pr.AssignedAt,
+		AgeSeconds:      pr.AgeSeconds,
 	}
 }
 
@@ -127,3 +246,64 @@ func toOAPIPullRequestShortList(prs []*models.PullRequestShort) []omodels.PullRe
 
 	return result
 }
+
+func toOAPIImportDiff(d *models.ImportDiff) omodels.ImportDiff {
+
+	updated := make([]omodels.ImportUserUpdate, 0, len(d.UsersUpdated))
+	for _, u := range d.UsersUpdated {
+		updated = append(updated, omodels.ImportUserUpdate{
+			UserId:      u.UserID,
+			OldUsername: u.OldUserName,
+			NewUsername: u.NewUserName,
+			OldIsActive: u.OldIsActive,
+			NewIsActive: u.NewIsActive,
+		})
+	}
+
+	memberships := make([]omodels.ImportMembership, 0, len(d.MembershipsAdded))
+	for _, m := range d.MembershipsAdded {
+		memberships = append(memberships, omodels.ImportMembership{TeamName: m.TeamName, UserId: m.UserID})
+	}
+
+	return omodels.ImportDiff{
+		DryRun:           d.DryRun,
+		TeamsCreated:     append([]string{}, d.TeamsCreated...),
+		UsersCreated:     append([]string{}, d.UsersCreated...),
+		UsersUpdated:     updated,
+		MembershipsAdded: memberships,
+		Unchanged:        d.Unchanged,
+	}
+}
+
+func toOAPILatencyPercentiles(p models.LatencyPercentiles) omodels.LatencyPercentiles {
+	return omodels.LatencyPercentiles{
+		Count: p.Count,
+		P50:   p.P50,
+		P90:   p.P90,
+		P99:   p.P99,
+	}
+}
+
+func toOAPIReviewerLoads(loads []*models.ReviewerLoad) []omodels.ReviewerLoad {
+
+	result := make([]omodels.ReviewerLoad, 0, len(loads))
+	for _, l := range loads {
+		result = append(result, omodels.ReviewerLoad{
+			UserId:      l.UserID,
+			Username:    l.UserName,
+			Assignments: l.Assignments,
+		})
+	}
+
+	return result
+}
+
+func toOAPIAgingBuckets(b models.AgingBuckets) omodels.AgingBuckets {
+	return omodels.AgingBuckets{
+		Under1d: b.Under1Day,
+		Days13:  b.Days1To3,
+		Days37:  b.Days3To7,
+		Over7d:  b.Over7Days,
+		Total:   b.Total,
+	}
+}
@@ -0,0 +1,164 @@
+package web
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/guarref/pr-service-assignment/internal/errs"
+	"github.com/guarref/pr-service-assignment/internal/web/omodels"
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	mimeTextCSV = "text/csv"
+	mimeNDJSON  = "application/x-ndjson"
+)
+
+// формат из параметра format, иначе — поддерживаемый тип из заголовка Accept с наибольшим q
+// (при равных q — первый, q=0 означает «не принимается»); по умолчанию JSON
+func exportFormat(ctx echo.Context, param *omodels.FormatQuery) (omodels.ExportFormat, error) {
+
+	if param != nil {
+		switch *param {
+		case omodels.ExportFormatJson, omodels.ExportFormatCsv, omodels.ExportFormatNdjson:
+			return *param, nil
+		}
+		return "", errs.ErrBadRequest
+	}
+
+	format, bestQ := omodels.ExportFormatJson, 0.0
+	for _, part := range strings.Split(ctx.Request().Header.Get(echo.HeaderAccept), ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+
+		q := 1.0
+		if raw, ok := params["q"]; ok {
+			q, err = strconv.ParseFloat(raw, 64)
+			if err != nil || q < 0 || q > 1 {
+				continue
+			}
+		}
+		if q <= bestQ {
+			continue
+		}
+
+		switch mediaType {
+		case mimeTextCSV:
+			format, bestQ = omodels.ExportFormatCsv, q
+		case mimeNDJSON:
+			format, bestQ = omodels.ExportFormatNdjson, q
+		case echo.MIMEApplicationJSON, "application/*", "*/*":
+			format, bestQ = omodels.ExportFormatJson, q
+		}
+	}
+
+	return format, nil
+}
+
+// построчная выгрузка в CSV или NDJSON: строки уходят клиенту при каждом Flush, ответ целиком не буферизуется.
+// Статус 200 отправляется при создании, поэтому ошибки после этого уже не превращаются в JSON-ответ
+type exportWriter struct {
+	resp *echo.Response
+	csv  *csv.Writer
+	json *json.Encoder
+}
+
+func newExportWriter(ctx echo.Context, format omodels.ExportFormat, name string, header []string) (*exportWriter, error) {
+
+	w := &exportWriter{resp: ctx.Response()}
+
+	if format == omodels.ExportFormatCsv {
+		w.resp.Header().Set(echo.HeaderContentType, mimeTextCSV+"; charset=utf-8")
+		w.resp.Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", name+".csv"))
+		w.resp.WriteHeader(http.StatusOK)
+
+		w.csv = csv.NewWriter(w.resp)
+		if err := w.csv.Write(header); err != nil {
+			return nil, fmt.Errorf("error writing csv header: %w", err)
+		}
+
+		return w, nil
+	}
+
+	w.resp.Header().Set(echo.HeaderContentType, mimeNDJSON)
+	w.resp.WriteHeader(http.StatusOK)
+	w.json = json.NewEncoder(w.resp)
+
+	return w, nil
+}
+
+// record — строка CSV, obj — та же запись в виде объекта для NDJSON
+func (w *exportWriter) Write(record []string, obj any) error {
+
+	if w.csv != nil {
+		return w.csv.Write(record)
+	}
+
+	return w.json.Encode(obj)
+}
+
+func (w *exportWriter) Flush() error {
+
+	if w.csv != nil {
+		w.csv.Flush()
+		if err := w.csv.Error(); err != nil {
+			return fmt.Errorf("error writing csv: %w", err)
+		}
+	}
+
+	w.resp.Flush()
+
+	return nil
+}
+
+// пользовательский текст не должен начинаться с символов, которые табличные редакторы считают формулой
+func csvText(s string) string {
+
+	if s != "" && strings.ContainsRune("=+-@\t\r", rune(s[0])) {
+		return "'" + s
+	}
+
+	return s
+}
+
+// строка статистики в длинном формате: метрика сервиса/команды (без пользователя) или метрика участника
+type statsExportRow struct {
+	Metric   string `json:"metric"`
+	UserID   string `json:"user_id,omitempty"`
+	Username string `json:"username,omitempty"`
+	Value    any    `json:"value"`
+}
+
+var statsExportHeader = []string{"metric", "user_id", "username", "value"}
+
+func writeStatsExport(ctx echo.Context, format omodels.ExportFormat, name string, rows []statsExportRow) error {
+
+	w, err := newExportWriter(ctx, format, name, statsExportHeader)
+	if err != nil {
+		return err
+	}
+
+	for _, row := range rows {
+		var value string
+		switch v := row.Value.(type) {
+		case float64:
+			value = strconv.FormatFloat(v, 'f', -1, 64)
+		default:
+			value = fmt.Sprint(v)
+		}
+
+		record := []string{row.Metric, csvText(row.UserID), csvText(row.Username), value}
+		if err := w.Write(record, row); err != nil {
+			return fmt.Errorf("error writing stats export: %w", err)
+		}
+	}
+
+	return w.Flush()
+}
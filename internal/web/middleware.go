@@ -1,24 +1,96 @@
 package web
 
 import (
+	"crypto/subtle"
 	"log"
+	"strings"
 	"time"
 
+	"github.com/guarref/pr-service-assignment/internal/auth"
+	"github.com/guarref/pr-service-assignment/internal/errs"
+	"github.com/guarref/pr-service-assignment/internal/metrics"
 	"github.com/labstack/echo/v4"
 )
 
-func AccessLogMiddleware(next echo.HandlerFunc) echo.HandlerFunc {
-	return func(c echo.Context) error {
-		start := time.Now()
+// ключ контекста echo, под которым mapErrorToHTTPResponse оставляет код ошибки для метрик
+const errorCodeKey = "error_code"
 
-		err := next(c)
+const internalErrorCode = "INTERNAL"
 
-		stop := time.Since(start)
-		req := c.Request()
-		res := c.Response()
+// пишет access-лог и метрики запроса по одному замеру длительности
+func MetricsMiddleware(m *metrics.Metrics) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			start := time.Now()
 
-		log.Printf("%s %s %d %s", req.Method, req.URL.Path, res.Status, stop)
+			err := next(c)
+			// ошибку, не обработанную хендлером, echo превращает в ответ только после middleware — делаем это здесь,
+			// чтобы в метрики попал итоговый статус
+			if err != nil {
+				c.Error(err)
+			}
 
-		return err
+			stop := time.Since(start)
+			req := c.Request()
+			res := c.Response()
+
+			// у запроса без подходящего маршрута шаблона нет
+			route := c.Path()
+			if route == "" {
+				route = "unmatched"
+			}
+
+			m.ObserveRequest(req.Method, route, res.Status, stop)
+			if code, ok := c.Get(errorCodeKey).(string); ok {
+				m.IncError(code)
+			}
+
+			log.Printf("%s %s %d %s", req.Method, req.URL.Path, res.Status, stop)
+
+			return nil
+		}
+	}
+}
+
+// кладёт в контекст запроса пользователя из Bearer-токена
+// запрос без заголовка Authorization проходит анонимно, права проверяются в сервисах
+func AuthMiddleware(signer *auth.Signer) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			header := c.Request().Header.Get(echo.HeaderAuthorization)
+			// у SCIM свой статический токен, он проверяется в ScimAuthMiddleware
+			if header == "" || strings.HasPrefix(c.Request().URL.Path, ScimBasePath+"/") {
+				return next(c)
+			}
+
+			token, ok := strings.CutPrefix(header, "Bearer ")
+			if !ok {
+				return mapErrorToHTTPResponse(c, errs.ErrUnauthorized)
+			}
+
+			userID, err := signer.Verify(token)
+			if err != nil {
+				return mapErrorToHTTPResponse(c, errs.ErrUnauthorized)
+			}
+
+			req := c.Request()
+			c.SetRequest(req.WithContext(auth.WithCaller(req.Context(), userID)))
+
+			return next(c)
+		}
+	}
+}
+
+// SCIM-клиент (IdP) авторизуется статическим токеном SCIM_TOKEN
+func ScimAuthMiddleware(token string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			provided, ok := strings.CutPrefix(c.Request().Header.Get(echo.HeaderAuthorization), "Bearer ")
+			if !ok || subtle.ConstantTimeCompare([]byte(provided), []byte(token)) != 1 {
+				return scimErrorResponse(c, errs.ErrUnauthorized)
+			}
+
+			return next(c)
+		}
 	}
 }
@@ -12,15 +12,35 @@ import (
 	"github.com/oapi-codegen/runtime"
 )
 
+const (
+	BearerAuthScopes = "bearerAuth.Scopes"
+)
+
 // Defines values for ErrorResponseErrorCode.
 const (
-	BADREQUEST  ErrorResponseErrorCode = "BAD_REQUEST"
-	NOCANDIDATE ErrorResponseErrorCode = "NO_CANDIDATE"
-	NOTASSIGNED ErrorResponseErrorCode = "NOT_ASSIGNED"
-	NOTFOUND    ErrorResponseErrorCode = "NOT_FOUND"
-	PREXISTS    ErrorResponseErrorCode = "PR_EXISTS"
-	PRMERGED    ErrorResponseErrorCode = "PR_MERGED"
-	TEAMEXISTS  ErrorResponseErrorCode = "TEAM_EXISTS"
+	BADREQUEST     ErrorResponseErrorCode = "BAD_REQUEST"
+	FORBIDDEN      ErrorResponseErrorCode = "FORBIDDEN"
+	NOCANDIDATE    ErrorResponseErrorCode = "NO_CANDIDATE"
+	NOTASSIGNED    ErrorResponseErrorCode = "NOT_ASSIGNED"
+	NOTFOUND       ErrorResponseErrorCode = "NOT_FOUND"
+	PREXISTS       ErrorResponseErrorCode = "PR_EXISTS"
+	PRMERGED       ErrorResponseErrorCode = "PR_MERGED"
+	TEAMEXISTS     ErrorResponseErrorCode = "TEAM_EXISTS"
+	TEAMHASHISTORY ErrorResponseErrorCode = "TEAM_HAS_HISTORY"
+	UNAUTHORIZED   ErrorResponseErrorCode = "UNAUTHORIZED"
+)
+
+// Defines values for ExportFormat.
+const (
+	ExportFormatCsv    ExportFormat = "csv"
+	ExportFormatJson   ExportFormat = "json"
+	ExportFormatNdjson ExportFormat = "ndjson"
+)
+
+// Defines values for OrderQuery.
+const (
+	Asc  OrderQuery = "asc"
+	Desc OrderQuery = "desc"
 )
 
 // Defines values for PullRequestStatus.
@@ -29,12 +49,152 @@ const (
 	PullRequestStatusOPEN   PullRequestStatus = "OPEN"
 )
 
+// Defines values for PullRequestDetailsStatus.
+const (
+	PullRequestDetailsStatusMERGED PullRequestDetailsStatus = "MERGED"
+	PullRequestDetailsStatusOPEN   PullRequestDetailsStatus = "OPEN"
+)
+
+// Defines values for PullRequestEventReason.
+const (
+	Creation       PullRequestEventReason = "creation"
+	Deactivation   PullRequestEventReason = "deactivation"
+	Erasure        PullRequestEventReason = "erasure"
+	ManualReassign PullRequestEventReason = "manual_reassign"
+	TeamDeletion   PullRequestEventReason = "team_deletion"
+)
+
+// Defines values for PullRequestEventType.
+const (
+	Created          PullRequestEventType = "created"
+	Merged           PullRequestEventType = "merged"
+	ReviewerAssigned PullRequestEventType = "reviewer_assigned"
+	ReviewerRemoved  PullRequestEventType = "reviewer_removed"
+)
+
+// Defines values for PullRequestReviewerReviewState.
+const (
+	COMPLETED PullRequestReviewerReviewState = "COMPLETED"
+	PENDING   PullRequestReviewerReviewState = "PENDING"
+)
+
 // Defines values for PullRequestShortStatus.
 const (
 	PullRequestShortStatusMERGED PullRequestShortStatus = "MERGED"
 	PullRequestShortStatusOPEN   PullRequestShortStatus = "OPEN"
 )
 
+// Defines values for TeamMemberRole.
+const (
+	Lead       TeamMemberRole = "lead"
+	Maintainer TeamMemberRole = "maintainer"
+	Member     TeamMemberRole = "member"
+)
+
+// Defines values for TimeseriesBucket.
+const (
+	Day   TimeseriesBucket = "day"
+	Month TimeseriesBucket = "month"
+	Week  TimeseriesBucket = "week"
+)
+
+// Defines values for TimeseriesMetric.
+const (
+	Assignments TimeseriesMetric = "assignments"
+	PrsCreated  TimeseriesMetric = "prs_created"
+	PrsMerged   TimeseriesMetric = "prs_merged"
+)
+
+// Defines values for PostImportTeamsParamsFormat.
+const (
+	PostImportTeamsParamsFormatCsv  PostImportTeamsParamsFormat = "csv"
+	PostImportTeamsParamsFormatYaml PostImportTeamsParamsFormat = "yaml"
+)
+
+// Defines values for GetPullRequestListParamsStatus.
+const (
+	GetPullRequestListParamsStatusMERGED GetPullRequestListParamsStatus = "MERGED"
+	GetPullRequestListParamsStatusOPEN   GetPullRequestListParamsStatus = "OPEN"
+)
+
+// Defines values for GetPullRequestListParamsSort.
+const (
+	GetPullRequestListParamsSortCreatedAt       GetPullRequestListParamsSort = "created_at"
+	GetPullRequestListParamsSortMergedAt        GetPullRequestListParamsSort = "merged_at"
+	GetPullRequestListParamsSortPullRequestId   GetPullRequestListParamsSort = "pull_request_id"
+	GetPullRequestListParamsSortPullRequestName GetPullRequestListParamsSort = "pull_request_name"
+)
+
+// Defines values for GetUsersGetAuthoredParamsStatus.
+const (
+	GetUsersGetAuthoredParamsStatusMERGED GetUsersGetAuthoredParamsStatus = "MERGED"
+	GetUsersGetAuthoredParamsStatusOPEN   GetUsersGetAuthoredParamsStatus = "OPEN"
+)
+
+// Defines values for GetUsersGetReviewParamsStatus.
+const (
+	GetUsersGetReviewParamsStatusMERGED GetUsersGetReviewParamsStatus = "MERGED"
+	GetUsersGetReviewParamsStatusOPEN   GetUsersGetReviewParamsStatus = "OPEN"
+)
+
+// Defines values for GetUsersGetReviewParamsSort.
+const (
+	GetUsersGetReviewParamsSortNewest   GetUsersGetReviewParamsSort = "newest"
+	GetUsersGetReviewParamsSortOldest   GetUsersGetReviewParamsSort = "oldest"
+	GetUsersGetReviewParamsSortPriority GetUsersGetReviewParamsSort = "priority"
+)
+
+// Defines values for GetUsersListParamsSort.
+const (
+	GetUsersListParamsSortOpenReviewCount GetUsersListParamsSort = "open_review_count"
+	GetUsersListParamsSortUserId          GetUsersListParamsSort = "user_id"
+	GetUsersListParamsSortUsername        GetUsersListParamsSort = "username"
+)
+
+// AgingBuckets Число открытых PR по возрасту (от создания PR)
+type AgingBuckets struct {
+	// Days13 От 1 до 3 суток
+	Days13 int `json:"days_1_3"`
+
+	// Days37 От 3 до 7 суток
+	Days37 int `json:"days_3_7"`
+
+	// Over7d 7 суток и больше
+	Over7d int `json:"over_7d"`
+	Total  int `json:"total"`
+
+	// Under1d Меньше суток
+	Under1d int `json:"under_1d"`
+}
+
+// AgingPullRequest defines model for AgingPullRequest.
+type AgingPullRequest struct {
+	AgeSeconds        int64     `json:"age_seconds"`
+	AssignedReviewers []string  `json:"assigned_reviewers"`
+	AuthorId          string    `json:"author_id"`
+	CreatedAt         time.Time `json:"createdAt"`
+	PullRequestId     string    `json:"pull_request_id"`
+	PullRequestName   string    `json:"pull_request_name"`
+}
+
+// AgingStats defines model for AgingStats.
+type AgingStats struct {
+	// AsOf Момент, от которого считается возраст PR
+	AsOf time.Time `json:"as_of"`
+
+	// Oldest Самые старые открытые PR с текущими ревьюверами
+	Oldest []AgingPullRequest `json:"oldest"`
+
+	// Reviewers Текущие ревьюверы открытых PR, сначала с наибольшим числом PR старше 7 суток
+	Reviewers []ReviewerAging `json:"reviewers"`
+
+	// Teams Команды авторов PR, сначала с наибольшим числом PR старше 7 суток
+	Teams []TeamAging `json:"teams"`
+
+	// Total Число открытых PR по возрасту (от создания PR)
+	Total AgingBuckets `json:"total"`
+}
+
 // DeactivateUsersResponse defines model for DeactivateUsersResponse.
 type DeactivateUsersResponse struct {
 	// DeactivatedUsers Список деактивированных user_id
@@ -44,14 +204,83 @@ type DeactivateUsersResponse struct {
 // ErrorResponse defines model for ErrorResponse.
 type ErrorResponse struct {
 	Error struct {
-		Code    ErrorResponseErrorCode `json:"code"`
-		Message string                 `json:"message"`
+		Code ErrorResponseErrorCode `json:"code"`
+
+		// Issues Ошибки по строкам входного файла (только для импорта)
+		Issues  *[]ImportIssue `json:"issues,omitempty"`
+		Message string         `json:"message"`
 	} `json:"error"`
 }
 
 // ErrorResponseErrorCode defines model for ErrorResponse.Error.Code.
 type ErrorResponseErrorCode string
 
+// ExportFormat csv — text/csv с заголовком, ndjson — application/x-ndjson (объект на строку). Статистика выгружается в длинном формате (metric, user_id, username, value)
+type ExportFormat string
+
+// FairnessStats defines model for FairnessStats.
+type FairnessStats struct {
+	Teams []TeamFairness `json:"teams"`
+}
+
+// ImportDiff defines model for ImportDiff.
+type ImportDiff struct {
+	// DryRun Изменения только рассчитаны и не применены
+	DryRun           bool               `json:"dry_run"`
+	MembershipsAdded []ImportMembership `json:"memberships_added"`
+	TeamsCreated     []string           `json:"teams_created"`
+
+	// Unchanged Строки, не требующие изменений
+	Unchanged    int                `json:"unchanged"`
+	UsersCreated []string           `json:"users_created"`
+	UsersUpdated []ImportUserUpdate `json:"users_updated"`
+}
+
+// ImportIssue defines model for ImportIssue.
+type ImportIssue struct {
+	// Line Номер строки во входном файле
+	Line    int    `json:"line"`
+	Message string `json:"message"`
+}
+
+// ImportMembership defines model for ImportMembership.
+type ImportMembership struct {
+	TeamName string `json:"team_name"`
+	UserId   string `json:"user_id"`
+}
+
+// ImportUserUpdate defines model for ImportUserUpdate.
+type ImportUserUpdate struct {
+	NewIsActive bool   `json:"new_is_active"`
+	NewUsername string `json:"new_username"`
+	OldIsActive bool   `json:"old_is_active"`
+	OldUsername string `json:"old_username"`
+	UserId      string `json:"user_id"`
+}
+
+// LatencyPercentiles defines model for LatencyPercentiles.
+type LatencyPercentiles struct {
+	// Count Число наблюдений
+	Count int `json:"count"`
+
+	// P50 Медиана, секунды
+	P50 float64 `json:"p50"`
+
+	// P90 90-й перцентиль, секунды
+	P90 float64 `json:"p90"`
+
+	// P99 99-й перцентиль, секунды
+	P99 float64 `json:"p99"`
+}
+
+// LatencyStats defines model for LatencyStats.
+type LatencyStats struct {
+	AssignedToMerge LatencyPercentiles `json:"assigned_to_merge"`
+	Reviewers       []ReviewerLatency  `json:"reviewers"`
+	Teams           []TeamLatency      `json:"teams"`
+	TimeToMerge     LatencyPercentiles `json:"time_to_merge"`
+}
+
 // PullRequest defines model for PullRequest.
 type PullRequest struct {
 	// AssignedReviewers user_id назначенных ревьюверов (0..2)
@@ -67,9 +296,77 @@ type PullRequest struct {
 // PullRequestStatus defines model for PullRequest.Status.
 type PullRequestStatus string
 
+// PullRequestDetails defines model for PullRequestDetails.
+type PullRequestDetails struct {
+	// AssignedReviewers user_id назначенных ревьюверов (0..2)
+	AssignedReviewers []string                 `json:"assigned_reviewers"`
+	AuthorId          string                   `json:"author_id"`
+	CreatedAt         *time.Time               `json:"createdAt"`
+	MergedAt          *time.Time               `json:"mergedAt"`
+	PullRequestId     string                   `json:"pull_request_id"`
+	PullRequestName   string                   `json:"pull_request_name"`
+	Reviewers         []PullRequestReviewer    `json:"reviewers"`
+	Status            PullRequestDetailsStatus `json:"status"`
+}
+
+// PullRequestDetailsStatus defines model for PullRequestDetails.Status.
+type PullRequestDetailsStatus string
+
+// PullRequestEvent defines model for PullRequestEvent.
+type PullRequestEvent struct {
+	CreatedAt time.Time `json:"created_at"`
+	EventId   int64     `json:"event_id"`
+
+	// Reason Причина назначения или снятия ревьювера (нет у событий, перенесённых из данных до появления журнала)
+	Reason *PullRequestEventReason `json:"reason,omitempty"`
+	Type   PullRequestEventType    `json:"type"`
+
+	// UserId Автор для created, ревьювер для reviewer_assigned и reviewer_removed
+	UserId *string `json:"user_id,omitempty"`
+}
+
+// PullRequestEventReason Причина назначения или снятия ревьювера (нет у событий, перенесённых из данных до появления журнала)
+type PullRequestEventReason string
+
+// PullRequestEventType defines model for PullRequestEvent.Type.
+type PullRequestEventType string
+
+// PullRequestList defines model for PullRequestList.
+type PullRequestList struct {
+	// NextCursor Курсор следующей страницы (отсутствует на последней странице)
+	NextCursor   *string       `json:"next_cursor,omitempty"`
+	PullRequests []PullRequest `json:"pull_requests"`
+}
+
+// PullRequestReviewer defines model for PullRequestReviewer.
+type PullRequestReviewer struct {
+	AssignedAt time.Time `json:"assigned_at"`
+
+	// HeldSeconds Сколько ревьювер держит назначение (для смерженных PR — до merge)
+	HeldSeconds int64 `json:"held_seconds"`
+	IsActive    bool  `json:"is_active"`
+
+	// ReviewState PENDING, пока PR открыт; COMPLETED после merge
+	ReviewState PullRequestReviewerReviewState `json:"review_state"`
+
+	// TeamName Основная команда ревьювера
+	TeamName string `json:"team_name"`
+	UserId   string `json:"user_id"`
+	Username string `json:"username"`
+}
+
+// PullRequestReviewerReviewState PENDING, пока PR открыт; COMPLETED после merge
+type PullRequestReviewerReviewState string
+
 // PullRequestShort defines model for PullRequestShort.
 type PullRequestShort struct {
+	// AgeSeconds Возраст PR в секундах (для смерженных — время до merge)
+	AgeSeconds int64 `json:"age_seconds"`
+
+	// AssignedAt Когда пользователь назначен ревьювером
+	AssignedAt      time.Time              `json:"assigned_at"`
 	AuthorId        string                 `json:"author_id"`
+	CreatedAt       time.Time              `json:"createdAt"`
 	PullRequestId   string                 `json:"pull_request_id"`
 	PullRequestName string                 `json:"pull_request_name"`
 	Status          PullRequestShortStatus `json:"status"`
@@ -78,27 +375,237 @@ type PullRequestShort struct {
 // PullRequestShortStatus defines model for PullRequestShort.Status.
 type PullRequestShortStatus string
 
+// ReviewAssignment defines model for ReviewAssignment.
+type ReviewAssignment struct {
+	AssignedAt    time.Time `json:"assigned_at"`
+	PullRequestId string    `json:"pull_request_id"`
+	UserId        string    `json:"user_id"`
+}
+
+// ReviewerAging defines model for ReviewerAging.
+type ReviewerAging struct {
+	// Buckets Число открытых PR по возрасту (от создания PR)
+	Buckets  AgingBuckets `json:"buckets"`
+	UserId   string       `json:"user_id"`
+	Username string       `json:"username"`
+}
+
+// ReviewerLatency defines model for ReviewerLatency.
+type ReviewerLatency struct {
+	AssignedToMerge LatencyPercentiles `json:"assigned_to_merge"`
+	UserId          string             `json:"user_id"`
+	Username        string             `json:"username"`
+}
+
+// ReviewerLoad defines model for ReviewerLoad.
+type ReviewerLoad struct {
+	// Assignments Назначения на PR команды за период
+	Assignments int    `json:"assignments"`
+	UserId      string `json:"user_id"`
+	Username    string `json:"username"`
+}
+
 // Stats defines model for Stats.
 type Stats struct {
-	ActiveUsers       int           `json:"active_users"`
-	OpenPullRequests  int           `json:"open_pull_requests"`
-	TopReviewers      []TopReviewer `json:"top_reviewers"`
-	TotalPullRequests int           `json:"total_pull_requests"`
-	TotalTeams        int           `json:"total_teams"`
-	TotalUsers        int           `json:"total_users"`
+	ActiveUsers int `json:"active_users"`
+
+	// AsOf Момент, на который посчитаны данные. Без фильтров статистика берётся из сводки, которая пересчитывается раз в STATS_REFRESH_INTERVAL; ответы также кэшируются на несколько секунд
+	AsOf time.Time `json:"as_of"`
+
+	// MergedPullRequests PR, смерженные в периоде
+	MergedPullRequests int `json:"merged_pull_requests"`
+
+	// OpenPullRequests Открытые PR, созданные в периоде
+	OpenPullRequests int           `json:"open_pull_requests"`
+	TopReviewers     []TopReviewer `json:"top_reviewers"`
+
+	// TotalPullRequests PR, созданные в периоде
+	TotalPullRequests int `json:"total_pull_requests"`
+	TotalTeams        int `json:"total_teams"`
+	TotalUsers        int `json:"total_users"`
 }
 
 // Team defines model for Team.
 type Team struct {
-	Members  []TeamMember `json:"members"`
+	Members []TeamMember `json:"members"`
+
+	// ParentTeam Родительская команда (org → department → squad)
+	ParentTeam *string `json:"parent_team,omitempty"`
+	TeamName   string  `json:"team_name"`
+}
+
+// TeamAging defines model for TeamAging.
+type TeamAging struct {
+	// Buckets Число открытых PR по возрасту (от создания PR)
+	Buckets  AgingBuckets `json:"buckets"`
 	TeamName string       `json:"team_name"`
 }
 
+// TeamArchiveResponse defines model for TeamArchiveResponse.
+type TeamArchiveResponse struct {
+	ArchivedAt time.Time `json:"archived_at"`
+
+	// DeactivatedUsers Участники, деактивированные при архивации
+	DeactivatedUsers []string `json:"deactivated_users"`
+	TeamName         string   `json:"team_name"`
+}
+
+// TeamDeleteResponse defines model for TeamDeleteResponse.
+type TeamDeleteResponse struct {
+	Export   TeamExport `json:"export"`
+	TeamName string     `json:"team_name"`
+}
+
+// TeamExport defines model for TeamExport.
+type TeamExport struct {
+	// PullRequests PR, созданные участниками команды
+	PullRequests []PullRequest `json:"pull_requests"`
+
+	// Reviews Назначения участников команды ревьюверами
+	Reviews []ReviewAssignment `json:"reviews"`
+	Team    Team               `json:"team"`
+}
+
+// TeamFairness defines model for TeamFairness.
+type TeamFairness struct {
+	// Gini Коэффициент Джини (0 — нагрузка распределена поровну)
+	Gini float64 `json:"gini"`
+	Max  int     `json:"max"`
+	Mean float64 `json:"mean"`
+	Min  int     `json:"min"`
+
+	// Overloaded Ревьюверы с наибольшим превышением среднего
+	Overloaded []ReviewerLoad `json:"overloaded"`
+
+	// Reviewers Активные участники команды
+	Reviewers int `json:"reviewers"`
+
+	// Stddev Стандартное отклонение числа назначений
+	Stddev           float64 `json:"stddev"`
+	TeamName         string  `json:"team_name"`
+	TotalAssignments int     `json:"total_assignments"`
+
+	// Underloaded Ревьюверы с наибольшим отставанием от среднего
+	Underloaded []ReviewerLoad `json:"underloaded"`
+}
+
+// TeamLatency defines model for TeamLatency.
+type TeamLatency struct {
+	AssignedToMerge LatencyPercentiles `json:"assigned_to_merge"`
+	TeamName        string             `json:"team_name"`
+	TimeToMerge     LatencyPercentiles `json:"time_to_merge"`
+}
+
+// TeamList defines model for TeamList.
+type TeamList struct {
+	// NextCursor Курсор следующей страницы (отсутствует на последней странице)
+	NextCursor *string       `json:"next_cursor,omitempty"`
+	Teams      []TeamSummary `json:"teams"`
+}
+
 // TeamMember defines model for TeamMember.
 type TeamMember struct {
-	IsActive bool   `json:"is_active"`
-	UserId   string `json:"user_id"`
-	Username string `json:"username"`
+	IsActive bool `json:"is_active"`
+
+	// IsPrimary Команда является основной для пользователя (пользователь может состоять в нескольких командах)
+	IsPrimary *bool `json:"is_primary,omitempty"`
+
+	// Role Роль в команде
+	Role     *TeamMemberRole `json:"role,omitempty"`
+	UserId   string          `json:"user_id"`
+	Username string          `json:"username"`
+}
+
+// TeamMemberRole Роль в команде
+type TeamMemberRole string
+
+// TeamMemberStats defines model for TeamMemberStats.
+type TeamMemberStats struct {
+	IsActive bool `json:"is_active"`
+
+	// OpenReviews Назначения на открытые PR (включая PR других команд)
+	OpenReviews int `json:"open_reviews"`
+
+	// TotalReviews Все текущие назначения
+	TotalReviews int    `json:"total_reviews"`
+	UserId       string `json:"user_id"`
+	Username     string `json:"username"`
+}
+
+// TeamReviewCount defines model for TeamReviewCount.
+type TeamReviewCount struct {
+	// Reviews PR авторов из команды, на которые назначался пользователь
+	Reviews  int    `json:"reviews"`
+	TeamName string `json:"team_name"`
+}
+
+// TeamStats defines model for TeamStats.
+type TeamStats struct {
+	// ActiveMembers Участники, у которых активны и пользователь, и членство в команде
+	ActiveMembers int `json:"active_members"`
+
+	// AvgReviewersPerPr Среднее число назначенных ревьюверов на PR команды
+	AvgReviewersPerPr float64           `json:"avg_reviewers_per_pr"`
+	Members           []TeamMemberStats `json:"members"`
+
+	// MergedPullRequests Смерженные PR, авторы которых состоят в команде
+	MergedPullRequests int `json:"merged_pull_requests"`
+
+	// OpenPullRequests Открытые PR, авторы которых состоят в команде
+	OpenPullRequests int `json:"open_pull_requests"`
+
+	// RequiredReviewers Сколько ревьюверов назначается на новый PR
+	RequiredReviewers int    `json:"required_reviewers"`
+	TeamName          string `json:"team_name"`
+	TotalMembers      int    `json:"total_members"`
+
+	// UnderstaffedPullRequests Открытые PR, у которых ревьюверов меньше required_reviewers
+	UnderstaffedPullRequests int `json:"understaffed_pull_requests"`
+}
+
+// TeamSummary defines model for TeamSummary.
+type TeamSummary struct {
+	// ActiveMemberCount Активные участники (активны и пользователь, и членство в команде)
+	ActiveMemberCount int       `json:"active_member_count"`
+	CreatedAt         time.Time `json:"created_at"`
+
+	// IsActive false для архивированной команды
+	IsActive bool `json:"is_active"`
+
+	// MemberCount Всего участников команды
+	MemberCount int `json:"member_count"`
+
+	// OpenPullRequests Открытые PR, автор которых состоит в команде
+	OpenPullRequests int     `json:"open_pull_requests"`
+	ParentTeam       *string `json:"parent_team,omitempty"`
+	TeamName         string  `json:"team_name"`
+}
+
+// Timeseries defines model for Timeseries.
+type Timeseries struct {
+	// Bucket Размер интервала (границы по UTC, неделя начинается с понедельника)
+	Bucket TimeseriesBucket `json:"bucket"`
+	From   time.Time        `json:"from"`
+
+	// Metric Метрика ряда — созданные PR, смерженные PR или назначения ревьюверов
+	Metric TimeseriesMetric `json:"metric"`
+
+	// Points Все интервалы периода по порядку, интервалы без событий — с нулём
+	Points []TimeseriesPoint `json:"points"`
+	Team   *string           `json:"team,omitempty"`
+	To     time.Time         `json:"to"`
+}
+
+// TimeseriesBucket Размер интервала (границы по UTC, неделя начинается с понедельника)
+type TimeseriesBucket string
+
+// TimeseriesMetric Метрика ряда — созданные PR, смерженные PR или назначения ревьюверов
+type TimeseriesMetric string
+
+// TimeseriesPoint defines model for TimeseriesPoint.
+type TimeseriesPoint struct {
+	BucketStart time.Time `json:"bucket_start"`
+	Value       int       `json:"value"`
 }
 
 // TopReviewer defines model for TopReviewer.
@@ -110,18 +617,107 @@ type TopReviewer struct {
 
 // User defines model for User.
 type User struct {
-	IsActive bool   `json:"is_active"`
+	IsActive bool `json:"is_active"`
+
+	// TeamName Основная команда пользователя
 	TeamName string `json:"team_name"`
-	UserId   string `json:"user_id"`
-	Username string `json:"username"`
+
+	// Teams Все команды пользователя (основная первой)
+	Teams    *[]string `json:"teams,omitempty"`
+	UserId   string    `json:"user_id"`
+	Username string    `json:"username"`
+}
+
+// UserDetails defines model for UserDetails.
+type UserDetails struct {
+	IsActive bool `json:"is_active"`
+
+	// OpenReviewCount Число открытых PR, где пользователь назначен ревьювером
+	OpenReviewCount int `json:"open_review_count"`
+
+	// TeamName Основная команда пользователя
+	TeamName string `json:"team_name"`
+
+	// Teams Все команды пользователя (основная первой)
+	Teams    []string `json:"teams"`
+	UserId   string   `json:"user_id"`
+	Username string   `json:"username"`
+}
+
+// UserErasure defines model for UserErasure.
+type UserErasure struct {
+	ErasedAt time.Time `json:"erased_at"`
+
+	// ReassignedReviews Сколько открытых ревью было переназначено
+	ReassignedReviews int `json:"reassigned_reviews"`
+
+	// RequestedBy Кто выполнил удаление
+	RequestedBy string `json:"requested_by"`
+
+	// UserId Псевдоним, под которым пользователь остался в истории PR
+	UserId string `json:"user_id"`
+}
+
+// UserList defines model for UserList.
+type UserList struct {
+	// NextCursor Курсор следующей страницы (отсутствует на последней странице)
+	NextCursor *string       `json:"next_cursor,omitempty"`
+	Users      []UserDetails `json:"users"`
+}
+
+// UserStats defines model for UserStats.
+type UserStats struct {
+	AuthoredPullRequests int `json:"authored_pull_requests"`
+
+	// MedianHeldSeconds Медиана времени от назначения до merge, секунды (null, если смерженных PR нет)
+	MedianHeldSeconds *float64 `json:"median_held_seconds"`
+
+	// MergedWhileReviewer PR, смерженные, пока пользователь был их ревьювером
+	MergedWhileReviewer int `json:"merged_while_reviewer"`
+
+	// OpenReviews Текущие назначения на открытые PR
+	OpenReviews int `json:"open_reviews"`
+
+	// ReassignedAway Назначения, с которых пользователя сняли (переназначение, деактивация)
+	ReassignedAway int               `json:"reassigned_away"`
+	ReviewedTeams  []TeamReviewCount `json:"reviewed_teams"`
+
+	// TotalReviews Все назначения ревьювером, включая снятые
+	TotalReviews int    `json:"total_reviews"`
+	UserId       string `json:"user_id"`
+	Username     string `json:"username"`
 }
 
+// CursorQuery defines model for CursorQuery.
+type CursorQuery = string
+
+// FormatQuery defines model for FormatQuery.
+type FormatQuery = ExportFormat
+
+// LimitQuery defines model for LimitQuery.
+type LimitQuery = int
+
+// OrderQuery defines model for OrderQuery.
+type OrderQuery string
+
 // TeamNameQuery defines model for TeamNameQuery.
 type TeamNameQuery = string
 
 // UserIdQuery defines model for UserIdQuery.
 type UserIdQuery = string
 
+// PostImportTeamsParams defines parameters for PostImportTeams.
+type PostImportTeamsParams struct {
+	// DryRun Только показать изменения, не применяя их
+	DryRun *bool `form:"dry_run,omitempty" json:"dry_run,omitempty"`
+
+	// Format Формат файла (по умолчанию определяется по Content-Type)
+	Format *PostImportTeamsParamsFormat `form:"format,omitempty" json:"format,omitempty"`
+}
+
+// PostImportTeamsParamsFormat defines parameters for PostImportTeams.
+type PostImportTeamsParamsFormat string
+
 // PostPullRequestCreateJSONBody defines parameters for PostPullRequestCreate.
 type PostPullRequestCreateJSONBody struct {
 	AuthorId        string `json:"author_id"`
@@ -129,6 +725,65 @@ type PostPullRequestCreateJSONBody struct {
 	PullRequestName string `json:"pull_request_name"`
 }
 
+// GetPullRequestGetParams defines parameters for GetPullRequestGet.
+type GetPullRequestGetParams struct {
+	// PullRequestId Идентификатор PR
+	PullRequestId string `form:"pull_request_id" json:"pull_request_id"`
+}
+
+// PostPullRequestGetBatchJSONBody defines parameters for PostPullRequestGetBatch.
+type PostPullRequestGetBatchJSONBody struct {
+	PullRequestIds []string `json:"pull_request_ids"`
+}
+
+// GetPullRequestListParams defines parameters for GetPullRequestList.
+type GetPullRequestListParams struct {
+	// Status Фильтр по статусу
+	Status *GetPullRequestListParamsStatus `form:"status,omitempty" json:"status,omitempty"`
+
+	// Author user_id автора
+	Author *string `form:"author,omitempty" json:"author,omitempty"`
+
+	// Reviewer user_id назначенного ревьювера
+	Reviewer *string `form:"reviewer,omitempty" json:"reviewer,omitempty"`
+
+	// Team Только PR авторов, состоящих в команде (в том числе не основной)
+	Team *string `form:"team,omitempty" json:"team,omitempty"`
+
+	// CreatedFrom Создан не раньше (включительно)
+	CreatedFrom *time.Time `form:"created_from,omitempty" json:"created_from,omitempty"`
+
+	// CreatedTo Создан раньше (не включительно)
+	CreatedTo *time.Time `form:"created_to,omitempty" json:"created_to,omitempty"`
+
+	// MergedFrom Смержен не раньше (включительно)
+	MergedFrom *time.Time `form:"merged_from,omitempty" json:"merged_from,omitempty"`
+
+	// MergedTo Смержен раньше (не включительно)
+	MergedTo *time.Time `form:"merged_to,omitempty" json:"merged_to,omitempty"`
+
+	// Sort Поле сортировки (при сортировке по merged_at открытые PR идут после смерженных)
+	Sort *GetPullRequestListParamsSort `form:"sort,omitempty" json:"sort,omitempty"`
+
+	// Order Направление сортировки
+	Order *OrderQuery `form:"order,omitempty" json:"order,omitempty"`
+
+	// Limit Размер страницы (не более 200)
+	Limit *LimitQuery `form:"limit,omitempty" json:"limit,omitempty"`
+
+	// Cursor Курсор следующей страницы из предыдущего ответа (next_cursor)
+	Cursor *CursorQuery `form:"cursor,omitempty" json:"cursor,omitempty"`
+
+	// Format Формат ответа; если не задан, выбирается по заголовку Accept (по умолчанию JSON)
+	Format *FormatQuery `form:"format,omitempty" json:"format,omitempty"`
+}
+
+// GetPullRequestListParamsStatus defines parameters for GetPullRequestList.
+type GetPullRequestListParamsStatus string
+
+// GetPullRequestListParamsSort defines parameters for GetPullRequestList.
+type GetPullRequestListParamsSort string
+
 // PostPullRequestMergeJSONBody defines parameters for PostPullRequestMerge.
 type PostPullRequestMergeJSONBody struct {
 	PullRequestId string `json:"pull_request_id"`
@@ -140,33 +795,221 @@ type PostPullRequestReassignJSONBody struct {
 	PullRequestId string `json:"pull_request_id"`
 }
 
+// GetPullRequestSearchParams defines parameters for GetPullRequestSearch.
+type GetPullRequestSearchParams struct {
+	// Q Поисковая строка (слова или ключ задачи, например PAY-1234)
+	Q string `form:"q" json:"q"`
+
+	// Limit Размер страницы (не более 200)
+	Limit *LimitQuery `form:"limit,omitempty" json:"limit,omitempty"`
+}
+
+// GetPullRequestTimelineParams defines parameters for GetPullRequestTimeline.
+type GetPullRequestTimelineParams struct {
+	// PullRequestId Идентификатор PR
+	PullRequestId string `form:"pull_request_id" json:"pull_request_id"`
+}
+
 // GetStatsParams defines parameters for GetStats.
 type GetStatsParams struct {
 	// Top Максимальное количество топ-ревьюверов(10 по умолчанию)
 	Top *int `form:"top,omitempty" json:"top,omitempty"`
+
+	// Team Ограничить статистику командой и всеми её дочерними командами
+	Team *string `form:"team,omitempty" json:"team,omitempty"`
+
+	// From Начало периода (включительно)
+	From *time.Time `form:"from,omitempty" json:"from,omitempty"`
+
+	// To Конец периода (не включительно)
+	To *time.Time `form:"to,omitempty" json:"to,omitempty"`
+
+	// Format Формат ответа; если не задан, выбирается по заголовку Accept (по умолчанию JSON)
+	Format *FormatQuery `form:"format,omitempty" json:"format,omitempty"`
 }
 
-// PostTeamDeactivateJSONBody defines parameters for PostTeamDeactivate.
-type PostTeamDeactivateJSONBody struct {
-	// TeamName Имя команды для деактивации пользователей
-	TeamName string `json:"team_name"`
+// GetStatsAgingParams defines parameters for GetStatsAging.
+type GetStatsAgingParams struct {
+	// Team Ограничить отчёт PR авторов из команды и всех её дочерних команд
+	Team *string `form:"team,omitempty" json:"team,omitempty"`
 
-	// UserIds Список конкретных user_id для деактивации(если пустой, деактивируются все)
-	UserIds *[]string `json:"user_ids,omitempty"`
+	// Limit Сколько самых старых PR вернуть
+	Limit *int `form:"limit,omitempty" json:"limit,omitempty"`
 }
 
-// GetTeamGetParams defines parameters for GetTeamGet.
-type GetTeamGetParams struct {
-	// TeamName Уникальное имя команды
-	TeamName TeamNameQuery `form:"team_name" json:"team_name"`
+// GetStatsFairnessParams defines parameters for GetStatsFairness.
+type GetStatsFairnessParams struct {
+	// Team Ограничить отчёт одной командой (по умолчанию — все неархивные команды)
+	Team *string `form:"team,omitempty" json:"team,omitempty"`
+
+	// From Начало периода (включительно)
+	From *time.Time `form:"from,omitempty" json:"from,omitempty"`
+
+	// To Конец периода (не включительно)
+	To *time.Time `form:"to,omitempty" json:"to,omitempty"`
 }
 
-// GetUsersGetReviewParams defines parameters for GetUsersGetReview.
-type GetUsersGetReviewParams struct {
-	// UserId Идентификатор пользователя
-	UserId UserIdQuery `form:"user_id" json:"user_id"`
+// GetStatsLatencyParams defines parameters for GetStatsLatency.
+type GetStatsLatencyParams struct {
+	// From Начало периода по merged_at (включительно)
+	From *time.Time `form:"from,omitempty" json:"from,omitempty"`
+
+	// To Конец периода по merged_at (не включительно)
+	To *time.Time `form:"to,omitempty" json:"to,omitempty"`
 }
 
+// GetStatsTeamParams defines parameters for GetStatsTeam.
+type GetStatsTeamParams struct {
+	// TeamName Уникальное имя команды
+	TeamName TeamNameQuery `form:"team_name" json:"team_name"`
+
+	// Format Формат ответа; если не задан, выбирается по заголовку Accept (по умолчанию JSON)
+	Format *FormatQuery `form:"format,omitempty" json:"format,omitempty"`
+}
+
+// GetStatsTimeseriesParams defines parameters for GetStatsTimeseries.
+type GetStatsTimeseriesParams struct {
+	Metric TimeseriesMetric  `form:"metric" json:"metric"`
+	Bucket *TimeseriesBucket `form:"bucket,omitempty" json:"bucket,omitempty"`
+
+	// Team Ограничить ряд командой и всеми её дочерними командами
+	Team *string `form:"team,omitempty" json:"team,omitempty"`
+
+	// From Начало периода (включительно)
+	From *time.Time `form:"from,omitempty" json:"from,omitempty"`
+
+	// To Конец периода (не включительно)
+	To *time.Time `form:"to,omitempty" json:"to,omitempty"`
+}
+
+// GetStatsUserParams defines parameters for GetStatsUser.
+type GetStatsUserParams struct {
+	// UserId Идентификатор пользователя
+	UserId UserIdQuery `form:"user_id" json:"user_id"`
+}
+
+// PostTeamArchiveJSONBody defines parameters for PostTeamArchive.
+type PostTeamArchiveJSONBody struct {
+	TeamName string `json:"team_name"`
+}
+
+// PostTeamDeactivateJSONBody defines parameters for PostTeamDeactivate.
+type PostTeamDeactivateJSONBody struct {
+	// TeamName Имя команды для деактивации пользователей
+	TeamName string `json:"team_name"`
+
+	// UserIds Список конкретных user_id для деактивации(если пустой, деактивируются все)
+	UserIds *[]string `json:"user_ids,omitempty"`
+}
+
+// PostTeamDeleteJSONBody defines parameters for PostTeamDelete.
+type PostTeamDeleteJSONBody struct {
+	// Force Удалить команду, даже если у участников есть история PR (история возвращается в export)
+	Force    *bool  `json:"force,omitempty"`
+	TeamName string `json:"team_name"`
+}
+
+// GetTeamGetParams defines parameters for GetTeamGet.
+type GetTeamGetParams struct {
+	// TeamName Уникальное имя команды
+	TeamName TeamNameQuery `form:"team_name" json:"team_name"`
+}
+
+// GetTeamListParams defines parameters for GetTeamList.
+type GetTeamListParams struct {
+	// Search Подстрока имени команды (без учёта регистра)
+	Search *string `form:"search,omitempty" json:"search,omitempty"`
+
+	// IsActive Фильтр по активности (false — только архивированные)
+	IsActive *bool `form:"is_active,omitempty" json:"is_active,omitempty"`
+
+	// Limit Размер страницы (не более 200)
+	Limit *LimitQuery `form:"limit,omitempty" json:"limit,omitempty"`
+
+	// Cursor Курсор следующей страницы из предыдущего ответа (next_cursor)
+	Cursor *CursorQuery `form:"cursor,omitempty" json:"cursor,omitempty"`
+}
+
+// PostUsersEraseJSONBody defines parameters for PostUsersErase.
+type PostUsersEraseJSONBody struct {
+	UserId string `json:"user_id"`
+}
+
+// GetUsersGetParams defines parameters for GetUsersGet.
+type GetUsersGetParams struct {
+	// UserId Идентификатор пользователя
+	UserId UserIdQuery `form:"user_id" json:"user_id"`
+}
+
+// GetUsersGetAuthoredParams defines parameters for GetUsersGetAuthored.
+type GetUsersGetAuthoredParams struct {
+	// UserId Идентификатор пользователя
+	UserId UserIdQuery `form:"user_id" json:"user_id"`
+
+	// Status Фильтр по статусу PR
+	Status *GetUsersGetAuthoredParamsStatus `form:"status,omitempty" json:"status,omitempty"`
+
+	// Limit Размер страницы (не более 200)
+	Limit *LimitQuery `form:"limit,omitempty" json:"limit,omitempty"`
+
+	// Cursor Курсор следующей страницы из предыдущего ответа (next_cursor)
+	Cursor *CursorQuery `form:"cursor,omitempty" json:"cursor,omitempty"`
+}
+
+// GetUsersGetAuthoredParamsStatus defines parameters for GetUsersGetAuthored.
+type GetUsersGetAuthoredParamsStatus string
+
+// GetUsersGetReviewParams defines parameters for GetUsersGetReview.
+type GetUsersGetReviewParams struct {
+	// UserId Идентификатор пользователя
+	UserId UserIdQuery `form:"user_id" json:"user_id"`
+
+	// Status Фильтр по статусу PR
+	Status *GetUsersGetReviewParamsStatus `form:"status,omitempty" json:"status,omitempty"`
+
+	// Sort oldest/newest — по дате создания PR; priority — сначала открытые, затем дольше всех ожидающие ревью
+	Sort *GetUsersGetReviewParamsSort `form:"sort,omitempty" json:"sort,omitempty"`
+
+	// Limit Размер страницы (не более 200)
+	Limit *LimitQuery `form:"limit,omitempty" json:"limit,omitempty"`
+
+	// Cursor Курсор следующей страницы из предыдущего ответа (next_cursor)
+	Cursor *CursorQuery `form:"cursor,omitempty" json:"cursor,omitempty"`
+}
+
+// GetUsersGetReviewParamsStatus defines parameters for GetUsersGetReview.
+type GetUsersGetReviewParamsStatus string
+
+// GetUsersGetReviewParamsSort defines parameters for GetUsersGetReview.
+type GetUsersGetReviewParamsSort string
+
+// GetUsersListParams defines parameters for GetUsersList.
+type GetUsersListParams struct {
+	// Team Только участники команды (в том числе не основной)
+	Team *string `form:"team,omitempty" json:"team,omitempty"`
+
+	// IsActive Фильтр по активности
+	IsActive *bool `form:"is_active,omitempty" json:"is_active,omitempty"`
+
+	// NamePrefix Префикс username (без учёта регистра)
+	NamePrefix *string `form:"name_prefix,omitempty" json:"name_prefix,omitempty"`
+
+	// Sort Поле сортировки
+	Sort *GetUsersListParamsSort `form:"sort,omitempty" json:"sort,omitempty"`
+
+	// Order Направление сортировки
+	Order *OrderQuery `form:"order,omitempty" json:"order,omitempty"`
+
+	// Limit Размер страницы (не более 200)
+	Limit *LimitQuery `form:"limit,omitempty" json:"limit,omitempty"`
+
+	// Cursor Курсор следующей страницы из предыдущего ответа (next_cursor)
+	Cursor *CursorQuery `form:"cursor,omitempty" json:"cursor,omitempty"`
+}
+
+// GetUsersListParamsSort defines parameters for GetUsersList.
+type GetUsersListParamsSort string
+
 // PostUsersSetIsActiveJSONBody defines parameters for PostUsersSetIsActive.
 type PostUsersSetIsActiveJSONBody struct {
 	IsActive bool   `json:"is_active"`
@@ -176,6 +1019,9 @@ type PostUsersSetIsActiveJSONBody struct {
 // PostPullRequestCreateJSONRequestBody defines body for PostPullRequestCreate for application/json ContentType.
 type PostPullRequestCreateJSONRequestBody PostPullRequestCreateJSONBody
 
+// PostPullRequestGetBatchJSONRequestBody defines body for PostPullRequestGetBatch for application/json ContentType.
+type PostPullRequestGetBatchJSONRequestBody PostPullRequestGetBatchJSONBody
+
 // PostPullRequestMergeJSONRequestBody defines body for PostPullRequestMerge for application/json ContentType.
 type PostPullRequestMergeJSONRequestBody PostPullRequestMergeJSONBody
 
@@ -185,38 +1031,104 @@ type PostPullRequestReassignJSONRequestBody PostPullRequestReassignJSONBody
 // PostTeamAddJSONRequestBody defines body for PostTeamAdd for application/json ContentType.
 type PostTeamAddJSONRequestBody = Team
 
+// PostTeamArchiveJSONRequestBody defines body for PostTeamArchive for application/json ContentType.
+type PostTeamArchiveJSONRequestBody PostTeamArchiveJSONBody
+
 // PostTeamDeactivateJSONRequestBody defines body for PostTeamDeactivate for application/json ContentType.
 type PostTeamDeactivateJSONRequestBody PostTeamDeactivateJSONBody
 
+// PostTeamDeleteJSONRequestBody defines body for PostTeamDelete for application/json ContentType.
+type PostTeamDeleteJSONRequestBody PostTeamDeleteJSONBody
+
+// PostUsersEraseJSONRequestBody defines body for PostUsersErase for application/json ContentType.
+type PostUsersEraseJSONRequestBody PostUsersEraseJSONBody
+
 // PostUsersSetIsActiveJSONRequestBody defines body for PostUsersSetIsActive for application/json ContentType.
 type PostUsersSetIsActiveJSONRequestBody PostUsersSetIsActiveJSONBody
 
 // ServerInterface represents all server handlers.
 type ServerInterface interface {
+	// Массовый импорт команд и пользователей из CSV или YAML
+	// (POST /import/teams)
+	PostImportTeams(ctx echo.Context, params PostImportTeamsParams) error
 	// Создать PR и автоматически назначить до 2 ревьюверов из команды автора
 	// (POST /pullRequest/create)
 	PostPullRequestCreate(ctx echo.Context) error
+	// Получить PR с подробными данными ревьюверов
+	// (GET /pullRequest/get)
+	GetPullRequestGet(ctx echo.Context, params GetPullRequestGetParams) error
+	// Получить несколько PR по списку идентификаторов (не более 100)
+	// (POST /pullRequest/getBatch)
+	PostPullRequestGetBatch(ctx echo.Context) error
+	// Список PR с фильтрами, сортировкой и пагинацией
+	// (GET /pullRequest/list)
+	GetPullRequestList(ctx echo.Context, params GetPullRequestListParams) error
 	// Пометить PR как MERGED (идемпотентная операция)
 	// (POST /pullRequest/merge)
 	PostPullRequestMerge(ctx echo.Context) error
 	// Переназначить конкретного ревьювера на другого из его команды
 	// (POST /pullRequest/reassign)
 	PostPullRequestReassign(ctx echo.Context) error
+	// Поиск PR по названию
+	// (GET /pullRequest/search)
+	GetPullRequestSearch(ctx echo.Context, params GetPullRequestSearchParams) error
+	// История событий PR
+	// (GET /pullRequest/timeline)
+	GetPullRequestTimeline(ctx echo.Context, params GetPullRequestTimelineParams) error
 	// Получить суммарную статистику сервиса
 	// (GET /stats)
 	GetStats(ctx echo.Context, params GetStatsParams) error
+	// Получить распределение открытых PR по возрасту
+	// (GET /stats/aging)
+	GetStatsAging(ctx echo.Context, params GetStatsAgingParams) error
+	// Получить распределение нагрузки ревью по командам
+	// (GET /stats/fairness)
+	GetStatsFairness(ctx echo.Context, params GetStatsFairnessParams) error
+	// Получить перцентили времени ревью
+	// (GET /stats/latency)
+	GetStatsLatency(ctx echo.Context, params GetStatsLatencyParams) error
+	// Получить статистику одной команды
+	// (GET /stats/team)
+	GetStatsTeam(ctx echo.Context, params GetStatsTeamParams) error
+	// Получить временной ряд метрики
+	// (GET /stats/timeseries)
+	GetStatsTimeseries(ctx echo.Context, params GetStatsTimeseriesParams) error
+	// Получить статистику пользователя
+	// (GET /stats/user)
+	GetStatsUser(ctx echo.Context, params GetStatsUserParams) error
 	// Создать команду с участниками (создаёт/обновляет пользователей)
 	// (POST /team/add)
 	PostTeamAdd(ctx echo.Context) error
+	// Архивировать команду (деактивация участников с переназначением их ревью)
+	// (POST /team/archive)
+	PostTeamArchive(ctx echo.Context) error
 	// Массовая деактивация пользователей команды с переназначением
 	// (POST /team/deactivate)
 	PostTeamDeactivate(ctx echo.Context) error
+	// Удалить команду вместе с участниками
+	// (POST /team/delete)
+	PostTeamDelete(ctx echo.Context) error
 	// Получить команду с участниками
 	// (GET /team/get)
 	GetTeamGet(ctx echo.Context, params GetTeamGetParams) error
+	// Список команд со счётчиками участников и открытых PR
+	// (GET /team/list)
+	GetTeamList(ctx echo.Context, params GetTeamListParams) error
+	// Удалить персональные данные пользователя с сохранением истории PR
+	// (POST /users/erase)
+	PostUsersErase(ctx echo.Context) error
+	// Получить пользователя с текущей нагрузкой
+	// (GET /users/get)
+	GetUsersGet(ctx echo.Context, params GetUsersGetParams) error
+	// Получить PR'ы, автором которых является пользователь, вместе с ревьюверами
+	// (GET /users/getAuthored)
+	GetUsersGetAuthored(ctx echo.Context, params GetUsersGetAuthoredParams) error
 	// Получить PR'ы, где пользователь назначен ревьювером
 	// (GET /users/getReview)
 	GetUsersGetReview(ctx echo.Context, params GetUsersGetReviewParams) error
+	// Список пользователей с фильтрами, сортировкой и пагинацией
+	// (GET /users/list)
+	GetUsersList(ctx echo.Context, params GetUsersListParams) error
 	// Установить флаг активности пользователя
 	// (POST /users/setIsActive)
 	PostUsersSetIsActive(ctx echo.Context) error
@@ -227,6 +1139,33 @@ type ServerInterfaceWrapper struct {
 	Handler ServerInterface
 }
 
+// PostImportTeams converts echo context to params.
+func (w *ServerInterfaceWrapper) PostImportTeams(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(BearerAuthScopes, []string{})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params PostImportTeamsParams
+	// ------------- Optional query parameter "dry_run" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "dry_run", ctx.QueryParams(), &params.DryRun)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter dry_run: %s", err))
+	}
+
+	// ------------- Optional query parameter "format" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "format", ctx.QueryParams(), &params.Format)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter format: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.PostImportTeams(ctx, params)
+	return err
+}
+
 // PostPullRequestCreate converts echo context to params.
 func (w *ServerInterfaceWrapper) PostPullRequestCreate(ctx echo.Context) error {
 	var err error
@@ -236,6 +1175,135 @@ func (w *ServerInterfaceWrapper) PostPullRequestCreate(ctx echo.Context) error {
 	return err
 }
 
+// GetPullRequestGet converts echo context to params.
+func (w *ServerInterfaceWrapper) GetPullRequestGet(ctx echo.Context) error {
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetPullRequestGetParams
+	// ------------- Required query parameter "pull_request_id" -------------
+
+	err = runtime.BindQueryParameter("form", true, true, "pull_request_id", ctx.QueryParams(), &params.PullRequestId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter pull_request_id: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetPullRequestGet(ctx, params)
+	return err
+}
+
+// PostPullRequestGetBatch converts echo context to params.
+func (w *ServerInterfaceWrapper) PostPullRequestGetBatch(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.PostPullRequestGetBatch(ctx)
+	return err
+}
+
+// GetPullRequestList converts echo context to params.
+func (w *ServerInterfaceWrapper) GetPullRequestList(ctx echo.Context) error {
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetPullRequestListParams
+	// ------------- Optional query parameter "status" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "status", ctx.QueryParams(), &params.Status)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter status: %s", err))
+	}
+
+	// ------------- Optional query parameter "author" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "author", ctx.QueryParams(), &params.Author)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter author: %s", err))
+	}
+
+	// ------------- Optional query parameter "reviewer" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "reviewer", ctx.QueryParams(), &params.Reviewer)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter reviewer: %s", err))
+	}
+
+	// ------------- Optional query parameter "team" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "team", ctx.QueryParams(), &params.Team)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter team: %s", err))
+	}
+
+	// ------------- Optional query parameter "created_from" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "created_from", ctx.QueryParams(), &params.CreatedFrom)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter created_from: %s", err))
+	}
+
+	// ------------- Optional query parameter "created_to" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "created_to", ctx.QueryParams(), &params.CreatedTo)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter created_to: %s", err))
+	}
+
+	// ------------- Optional query parameter "merged_from" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "merged_from", ctx.QueryParams(), &params.MergedFrom)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter merged_from: %s", err))
+	}
+
+	// ------------- Optional query parameter "merged_to" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "merged_to", ctx.QueryParams(), &params.MergedTo)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter merged_to: %s", err))
+	}
+
+	// ------------- Optional query parameter "sort" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "sort", ctx.QueryParams(), &params.Sort)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter sort: %s", err))
+	}
+
+	// ------------- Optional query parameter "order" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "order", ctx.QueryParams(), &params.Order)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter order: %s", err))
+	}
+
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", ctx.QueryParams(), &params.Limit)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter limit: %s", err))
+	}
+
+	// ------------- Optional query parameter "cursor" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "cursor", ctx.QueryParams(), &params.Cursor)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter cursor: %s", err))
+	}
+
+	// ------------- Optional query parameter "format" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "format", ctx.QueryParams(), &params.Format)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter format: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetPullRequestList(ctx, params)
+	return err
+}
+
 // PostPullRequestMerge converts echo context to params.
 func (w *ServerInterfaceWrapper) PostPullRequestMerge(ctx echo.Context) error {
 	var err error
@@ -249,11 +1317,56 @@ func (w *ServerInterfaceWrapper) PostPullRequestMerge(ctx echo.Context) error {
 func (w *ServerInterfaceWrapper) PostPullRequestReassign(ctx echo.Context) error {
 	var err error
 
+	ctx.Set(BearerAuthScopes, []string{})
+
 	// Invoke the callback with all the unmarshaled arguments
 	err = w.Handler.PostPullRequestReassign(ctx)
 	return err
 }
 
+// GetPullRequestSearch converts echo context to params.
+func (w *ServerInterfaceWrapper) GetPullRequestSearch(ctx echo.Context) error {
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetPullRequestSearchParams
+	// ------------- Required query parameter "q" -------------
+
+	err = runtime.BindQueryParameter("form", true, true, "q", ctx.QueryParams(), &params.Q)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter q: %s", err))
+	}
+
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", ctx.QueryParams(), &params.Limit)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter limit: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetPullRequestSearch(ctx, params)
+	return err
+}
+
+// GetPullRequestTimeline converts echo context to params.
+func (w *ServerInterfaceWrapper) GetPullRequestTimeline(ctx echo.Context) error {
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetPullRequestTimelineParams
+	// ------------- Required query parameter "pull_request_id" -------------
+
+	err = runtime.BindQueryParameter("form", true, true, "pull_request_id", ctx.QueryParams(), &params.PullRequestId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter pull_request_id: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetPullRequestTimeline(ctx, params)
+	return err
+}
+
 // GetStats converts echo context to params.
 func (w *ServerInterfaceWrapper) GetStats(ctx echo.Context) error {
 	var err error
@@ -267,29 +1380,254 @@ func (w *ServerInterfaceWrapper) GetStats(ctx echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter top: %s", err))
 	}
 
+	// ------------- Optional query parameter "team" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "team", ctx.QueryParams(), &params.Team)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter team: %s", err))
+	}
+
+	// ------------- Optional query parameter "from" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "from", ctx.QueryParams(), &params.From)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter from: %s", err))
+	}
+
+	// ------------- Optional query parameter "to" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "to", ctx.QueryParams(), &params.To)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter to: %s", err))
+	}
+
+	// ------------- Optional query parameter "format" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "format", ctx.QueryParams(), &params.Format)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter format: %s", err))
+	}
+
 	// Invoke the callback with all the unmarshaled arguments
 	err = w.Handler.GetStats(ctx, params)
 	return err
 }
 
+// GetStatsAging converts echo context to params.
+func (w *ServerInterfaceWrapper) GetStatsAging(ctx echo.Context) error {
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetStatsAgingParams
+	// ------------- Optional query parameter "team" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "team", ctx.QueryParams(), &params.Team)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter team: %s", err))
+	}
+
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", ctx.QueryParams(), &params.Limit)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter limit: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetStatsAging(ctx, params)
+	return err
+}
+
+// GetStatsFairness converts echo context to params.
+func (w *ServerInterfaceWrapper) GetStatsFairness(ctx echo.Context) error {
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetStatsFairnessParams
+	// ------------- Optional query parameter "team" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "team", ctx.QueryParams(), &params.Team)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter team: %s", err))
+	}
+
+	// ------------- Optional query parameter "from" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "from", ctx.QueryParams(), &params.From)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter from: %s", err))
+	}
+
+	// ------------- Optional query parameter "to" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "to", ctx.QueryParams(), &params.To)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter to: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetStatsFairness(ctx, params)
+	return err
+}
+
+// GetStatsLatency converts echo context to params.
+func (w *ServerInterfaceWrapper) GetStatsLatency(ctx echo.Context) error {
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetStatsLatencyParams
+	// ------------- Optional query parameter "from" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "from", ctx.QueryParams(), &params.From)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter from: %s", err))
+	}
+
+	// ------------- Optional query parameter "to" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "to", ctx.QueryParams(), &params.To)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter to: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetStatsLatency(ctx, params)
+	return err
+}
+
+// GetStatsTeam converts echo context to params.
+func (w *ServerInterfaceWrapper) GetStatsTeam(ctx echo.Context) error {
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetStatsTeamParams
+	// ------------- Required query parameter "team_name" -------------
+
+	err = runtime.BindQueryParameter("form", true, true, "team_name", ctx.QueryParams(), &params.TeamName)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter team_name: %s", err))
+	}
+
+	// ------------- Optional query parameter "format" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "format", ctx.QueryParams(), &params.Format)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter format: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetStatsTeam(ctx, params)
+	return err
+}
+
+// GetStatsTimeseries converts echo context to params.
+func (w *ServerInterfaceWrapper) GetStatsTimeseries(ctx echo.Context) error {
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetStatsTimeseriesParams
+	// ------------- Required query parameter "metric" -------------
+
+	err = runtime.BindQueryParameter("form", true, true, "metric", ctx.QueryParams(), &params.Metric)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter metric: %s", err))
+	}
+
+	// ------------- Optional query parameter "bucket" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "bucket", ctx.QueryParams(), &params.Bucket)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter bucket: %s", err))
+	}
+
+	// ------------- Optional query parameter "team" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "team", ctx.QueryParams(), &params.Team)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter team: %s", err))
+	}
+
+	// ------------- Optional query parameter "from" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "from", ctx.QueryParams(), &params.From)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter from: %s", err))
+	}
+
+	// ------------- Optional query parameter "to" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "to", ctx.QueryParams(), &params.To)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter to: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetStatsTimeseries(ctx, params)
+	return err
+}
+
+// GetStatsUser converts echo context to params.
+func (w *ServerInterfaceWrapper) GetStatsUser(ctx echo.Context) error {
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetStatsUserParams
+	// ------------- Required query parameter "user_id" -------------
+
+	err = runtime.BindQueryParameter("form", true, true, "user_id", ctx.QueryParams(), &params.UserId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter user_id: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetStatsUser(ctx, params)
+	return err
+}
+
 // PostTeamAdd converts echo context to params.
 func (w *ServerInterfaceWrapper) PostTeamAdd(ctx echo.Context) error {
 	var err error
 
+	ctx.Set(BearerAuthScopes, []string{})
+
 	// Invoke the callback with all the unmarshaled arguments
 	err = w.Handler.PostTeamAdd(ctx)
 	return err
 }
 
+// PostTeamArchive converts echo context to params.
+func (w *ServerInterfaceWrapper) PostTeamArchive(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(BearerAuthScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.PostTeamArchive(ctx)
+	return err
+}
+
 // PostTeamDeactivate converts echo context to params.
 func (w *ServerInterfaceWrapper) PostTeamDeactivate(ctx echo.Context) error {
 	var err error
 
+	ctx.Set(BearerAuthScopes, []string{})
+
 	// Invoke the callback with all the unmarshaled arguments
 	err = w.Handler.PostTeamDeactivate(ctx)
 	return err
 }
 
+// PostTeamDelete converts echo context to params.
+func (w *ServerInterfaceWrapper) PostTeamDelete(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(BearerAuthScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.PostTeamDelete(ctx)
+	return err
+}
+
 // GetTeamGet converts echo context to params.
 func (w *ServerInterfaceWrapper) GetTeamGet(ctx echo.Context) error {
 	var err error
@@ -308,6 +1646,113 @@ func (w *ServerInterfaceWrapper) GetTeamGet(ctx echo.Context) error {
 	return err
 }
 
+// GetTeamList converts echo context to params.
+func (w *ServerInterfaceWrapper) GetTeamList(ctx echo.Context) error {
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetTeamListParams
+	// ------------- Optional query parameter "search" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "search", ctx.QueryParams(), &params.Search)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter search: %s", err))
+	}
+
+	// ------------- Optional query parameter "is_active" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "is_active", ctx.QueryParams(), &params.IsActive)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter is_active: %s", err))
+	}
+
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", ctx.QueryParams(), &params.Limit)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter limit: %s", err))
+	}
+
+	// ------------- Optional query parameter "cursor" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "cursor", ctx.QueryParams(), &params.Cursor)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter cursor: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetTeamList(ctx, params)
+	return err
+}
+
+// PostUsersErase converts echo context to params.
+func (w *ServerInterfaceWrapper) PostUsersErase(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(BearerAuthScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.PostUsersErase(ctx)
+	return err
+}
+
+// GetUsersGet converts echo context to params.
+func (w *ServerInterfaceWrapper) GetUsersGet(ctx echo.Context) error {
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetUsersGetParams
+	// ------------- Required query parameter "user_id" -------------
+
+	err = runtime.BindQueryParameter("form", true, true, "user_id", ctx.QueryParams(), &params.UserId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter user_id: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetUsersGet(ctx, params)
+	return err
+}
+
+// GetUsersGetAuthored converts echo context to params.
+func (w *ServerInterfaceWrapper) GetUsersGetAuthored(ctx echo.Context) error {
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetUsersGetAuthoredParams
+	// ------------- Required query parameter "user_id" -------------
+
+	err = runtime.BindQueryParameter("form", true, true, "user_id", ctx.QueryParams(), &params.UserId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter user_id: %s", err))
+	}
+
+	// ------------- Optional query parameter "status" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "status", ctx.QueryParams(), &params.Status)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter status: %s", err))
+	}
+
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", ctx.QueryParams(), &params.Limit)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter limit: %s", err))
+	}
+
+	// ------------- Optional query parameter "cursor" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "cursor", ctx.QueryParams(), &params.Cursor)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter cursor: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetUsersGetAuthored(ctx, params)
+	return err
+}
+
 // GetUsersGetReview converts echo context to params.
 func (w *ServerInterfaceWrapper) GetUsersGetReview(ctx echo.Context) error {
 	var err error
@@ -321,11 +1766,99 @@ func (w *ServerInterfaceWrapper) GetUsersGetReview(ctx echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter user_id: %s", err))
 	}
 
+	// ------------- Optional query parameter "status" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "status", ctx.QueryParams(), &params.Status)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter status: %s", err))
+	}
+
+	// ------------- Optional query parameter "sort" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "sort", ctx.QueryParams(), &params.Sort)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter sort: %s", err))
+	}
+
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", ctx.QueryParams(), &params.Limit)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter limit: %s", err))
+	}
+
+	// ------------- Optional query parameter "cursor" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "cursor", ctx.QueryParams(), &params.Cursor)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter cursor: %s", err))
+	}
+
 	// Invoke the callback with all the unmarshaled arguments
 	err = w.Handler.GetUsersGetReview(ctx, params)
 	return err
 }
 
+// GetUsersList converts echo context to params.
+func (w *ServerInterfaceWrapper) GetUsersList(ctx echo.Context) error {
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetUsersListParams
+	// ------------- Optional query parameter "team" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "team", ctx.QueryParams(), &params.Team)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter team: %s", err))
+	}
+
+	// ------------- Optional query parameter "is_active" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "is_active", ctx.QueryParams(), &params.IsActive)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter is_active: %s", err))
+	}
+
+	// ------------- Optional query parameter "name_prefix" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "name_prefix", ctx.QueryParams(), &params.NamePrefix)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter name_prefix: %s", err))
+	}
+
+	// ------------- Optional query parameter "sort" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "sort", ctx.QueryParams(), &params.Sort)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter sort: %s", err))
+	}
+
+	// ------------- Optional query parameter "order" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "order", ctx.QueryParams(), &params.Order)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter order: %s", err))
+	}
+
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", ctx.QueryParams(), &params.Limit)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter limit: %s", err))
+	}
+
+	// ------------- Optional query parameter "cursor" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "cursor", ctx.QueryParams(), &params.Cursor)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter cursor: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetUsersList(ctx, params)
+	return err
+}
+
 // PostUsersSetIsActive converts echo context to params.
 func (w *ServerInterfaceWrapper) PostUsersSetIsActive(ctx echo.Context) error {
 	var err error
@@ -363,14 +1896,33 @@ func RegisterHandlersWithBaseURL(router EchoRouter, si ServerInterface, baseURL
 		Handler: si,
 	}
 
+	router.POST(baseURL+"/import/teams", wrapper.PostImportTeams)
 	router.POST(baseURL+"/pullRequest/create", wrapper.PostPullRequestCreate)
+	router.GET(baseURL+"/pullRequest/get", wrapper.GetPullRequestGet)
+	router.POST(baseURL+"/pullRequest/getBatch", wrapper.PostPullRequestGetBatch)
+	router.GET(baseURL+"/pullRequest/list", wrapper.GetPullRequestList)
 	router.POST(baseURL+"/pullRequest/merge", wrapper.PostPullRequestMerge)
 	router.POST(baseURL+"/pullRequest/reassign", wrapper.PostPullRequestReassign)
+	router.GET(baseURL+"/pullRequest/search", wrapper.GetPullRequestSearch)
+	router.GET(baseURL+"/pullRequest/timeline", wrapper.GetPullRequestTimeline)
 	router.GET(baseURL+"/stats", wrapper.GetStats)
+	router.GET(baseURL+"/stats/aging", wrapper.GetStatsAging)
+	router.GET(baseURL+"/stats/fairness", wrapper.GetStatsFairness)
+	router.GET(baseURL+"/stats/latency", wrapper.GetStatsLatency)
+	router.GET(baseURL+"/stats/team", wrapper.GetStatsTeam)
+	router.GET(baseURL+"/stats/timeseries", wrapper.GetStatsTimeseries)
+	router.GET(baseURL+"/stats/user", wrapper.GetStatsUser)
 	router.POST(baseURL+"/team/add", wrapper.PostTeamAdd)
+	router.POST(baseURL+"/team/archive", wrapper.PostTeamArchive)
 	router.POST(baseURL+"/team/deactivate", wrapper.PostTeamDeactivate)
+	router.POST(baseURL+"/team/delete", wrapper.PostTeamDelete)
 	router.GET(baseURL+"/team/get", wrapper.GetTeamGet)
+	router.GET(baseURL+"/team/list", wrapper.GetTeamList)
+	router.POST(baseURL+"/users/erase", wrapper.PostUsersErase)
+	router.GET(baseURL+"/users/get", wrapper.GetUsersGet)
+	router.GET(baseURL+"/users/getAuthored", wrapper.GetUsersGetAuthored)
 	router.GET(baseURL+"/users/getReview", wrapper.GetUsersGetReview)
+	router.GET(baseURL+"/users/list", wrapper.GetUsersList)
 	router.POST(baseURL+"/users/setIsActive", wrapper.PostUsersSetIsActive)
 
 }
@@ -1,24 +1,27 @@
 package web
 
 import (
+	"github.com/guarref/pr-service-assignment/internal/metrics"
 	"github.com/guarref/pr-service-assignment/internal/service"
 	"github.com/guarref/pr-service-assignment/internal/web/omodels"
 	"github.com/labstack/echo/v4"
 )
 
 type Router struct {
-	teamHandler  *TeamHandler
-	userHandler  *UserHandler
-	prHandler    *PullRequestHandler
-	statsHandler *StatsHandler
+	teamHandler   *TeamHandler
+	userHandler   *UserHandler
+	prHandler     *PullRequestHandler
+	statsHandler  *StatsHandler
+	importHandler *ImportHandler
 }
 
-func NewRouter(teamSvc *service.TeamService, userSvc *service.UserService, prSvc *service.PullRequestService, statsSvc *service.StatsService) *Router {
+func NewRouter(teamSvc *service.TeamService, userSvc *service.UserService, prSvc *service.PullRequestService, statsSvc *service.StatsService, importSvc *service.ImportService) *Router {
 	return &Router{
-		teamHandler:  NewTeamHandler(teamSvc),
-		userHandler:  NewUserHandler(userSvc),
-		prHandler:    NewPullRequestHandler(prSvc),
-		statsHandler: NewStatsHandler(statsSvc),
+		teamHandler:   NewTeamHandler(teamSvc),
+		userHandler:   NewUserHandler(userSvc),
+		prHandler:     NewPullRequestHandler(prSvc),
+		statsHandler:  NewStatsHandler(statsSvc),
+		importHandler: NewImportHandler(importSvc),
 	}
 }
 
@@ -30,10 +33,30 @@ func (r *Router) PostPullRequestMerge(ctx echo.Context) error {
 	return r.prHandler.PostPullRequestMerge(ctx)
 }
 
+func (r *Router) GetPullRequestGet(ctx echo.Context, params omodels.GetPullRequestGetParams) error {
+	return r.prHandler.GetPullRequestGet(ctx, params)
+}
+
+func (r *Router) PostPullRequestGetBatch(ctx echo.Context) error {
+	return r.prHandler.PostPullRequestGetBatch(ctx)
+}
+
+func (r *Router) GetPullRequestList(ctx echo.Context, params omodels.GetPullRequestListParams) error {
+	return r.prHandler.GetPullRequestList(ctx, params)
+}
+
 func (r *Router) PostPullRequestReassign(ctx echo.Context) error {
 	return r.prHandler.PostPullRequestReassign(ctx)
 }
 
+func (r *Router) GetPullRequestTimeline(ctx echo.Context, params omodels.GetPullRequestTimelineParams) error {
+	return r.prHandler.GetPullRequestTimeline(ctx, params)
+}
+
+func (r *Router) GetPullRequestSearch(ctx echo.Context, params omodels.GetPullRequestSearchParams) error {
+	return r.prHandler.GetPullRequestSearch(ctx, params)
+}
+
 func (r *Router) PostTeamAdd(ctx echo.Context) error {
 	return r.teamHandler.PostTeamAdd(ctx)
 }
@@ -42,10 +65,30 @@ func (r *Router) GetTeamGet(ctx echo.Context, params omodels.GetTeamGetParams) e
 	return r.teamHandler.GetTeamGet(ctx, params)
 }
 
+func (r *Router) GetTeamList(ctx echo.Context, params omodels.GetTeamListParams) error {
+	return r.teamHandler.GetTeamList(ctx, params)
+}
+
+func (r *Router) GetUsersGetAuthored(ctx echo.Context, params omodels.GetUsersGetAuthoredParams) error {
+	return r.prHandler.GetUsersGetAuthored(ctx, params)
+}
+
 func (r *Router) GetUsersGetReview(ctx echo.Context, params omodels.GetUsersGetReviewParams) error {
 	return r.prHandler.GetUsersGetReview(ctx, params)
 }
 
+func (r *Router) GetUsersGet(ctx echo.Context, params omodels.GetUsersGetParams) error {
+	return r.userHandler.GetUsersGet(ctx, params)
+}
+
+func (r *Router) GetUsersList(ctx echo.Context, params omodels.GetUsersListParams) error {
+	return r.userHandler.GetUsersList(ctx, params)
+}
+
+func (r *Router) PostUsersErase(ctx echo.Context) error {
+	return r.userHandler.PostUsersErase(ctx)
+}
+
 func (r *Router) PostUsersSetIsActive(ctx echo.Context) error {
 	return r.userHandler.PostUsersSetIsActive(ctx)
 }
@@ -54,12 +97,53 @@ func (r *Router) GetStats(ctx echo.Context, params omodels.GetStatsParams) error
 	return r.statsHandler.GetStats(ctx, params)
 }
 
+func (r *Router) GetStatsAging(ctx echo.Context, params omodels.GetStatsAgingParams) error {
+	return r.statsHandler.GetStatsAging(ctx, params)
+}
+
+func (r *Router) GetStatsFairness(ctx echo.Context, params omodels.GetStatsFairnessParams) error {
+	return r.statsHandler.GetStatsFairness(ctx, params)
+}
+
+func (r *Router) GetStatsLatency(ctx echo.Context, params omodels.GetStatsLatencyParams) error {
+	return r.statsHandler.GetStatsLatency(ctx, params)
+}
+
+func (r *Router) GetStatsTeam(ctx echo.Context, params omodels.GetStatsTeamParams) error {
+	return r.statsHandler.GetStatsTeam(ctx, params)
+}
+
+func (r *Router) GetStatsTimeseries(ctx echo.Context, params omodels.GetStatsTimeseriesParams) error {
+	return r.statsHandler.GetStatsTimeseries(ctx, params)
+}
+
+func (r *Router) GetStatsUser(ctx echo.Context, params omodels.GetStatsUserParams) error {
+	return r.statsHandler.GetStatsUser(ctx, params)
+}
+
 func (r *Router) PostTeamDeactivate(ctx echo.Context) error {
 	return r.teamHandler.PostTeamDeactivate(ctx)
 }
 
-func RegisterRoutes(e *echo.Echo, teamSvc *service.TeamService, userSvc *service.UserService, prSvc *service.PullRequestService, statsSvc *service.StatsService) {
+func (r *Router) PostTeamArchive(ctx echo.Context) error {
+	return r.teamHandler.PostTeamArchive(ctx)
+}
+
+func (r *Router) PostTeamDelete(ctx echo.Context) error {
+	return r.teamHandler.PostTeamDelete(ctx)
+}
+
+func (r *Router) PostImportTeams(ctx echo.Context, params omodels.PostImportTeamsParams) error {
+	return r.importHandler.PostImportTeams(ctx, params)
+}
+
+func RegisterRoutes(e *echo.Echo, teamSvc *service.TeamService, userSvc *service.UserService, prSvc *service.PullRequestService, statsSvc *service.StatsService, importSvc *service.ImportService) {
 
-	server := NewRouter(teamSvc, userSvc, prSvc, statsSvc)
+	server := NewRouter(teamSvc, userSvc, prSvc, statsSvc, importSvc)
 	omodels.RegisterHandlers(e, server)
 }
+
+// /metrics отдаётся без авторизации, как и остальные запросы без заголовка Authorization
+func RegisterMetricsRoute(e *echo.Echo, m *metrics.Metrics) {
+	e.GET("/metrics", echo.WrapHandler(m.Handler()))
+}
@@ -0,0 +1,69 @@
+package web
+
+import (
+	"bytes"
+	"io"
+	"mime"
+	"net/http"
+
+	"github.com/guarref/pr-service-assignment/internal/errs"
+	"github.com/guarref/pr-service-assignment/internal/models"
+	"github.com/guarref/pr-service-assignment/internal/service"
+	"github.com/guarref/pr-service-assignment/internal/web/omodels"
+	"github.com/labstack/echo/v4"
+)
+
+const maxImportBodySize = 10 << 20
+
+type ImportHandler struct {
+	service *service.ImportService
+}
+
+func NewImportHandler(s *service.ImportService) *ImportHandler {
+	return &ImportHandler{service: s}
+}
+
+// /import/teams post
+func (h *ImportHandler) PostImportTeams(ctx echo.Context, params omodels.PostImportTeamsParams) error {
+
+	var format models.ImportFormat
+	if params.Format != nil {
+		format = models.ImportFormat(*params.Format)
+	} else {
+		format = importFormatByContentType(ctx.Request().Header.Get(echo.HeaderContentType))
+	}
+	if format == "" {
+		return mapErrorToHTTPResponse(ctx, errs.ErrBadRequest)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(ctx.Request().Body, maxImportBodySize+1))
+	if err != nil || len(body) > maxImportBodySize {
+		return mapErrorToHTTPResponse(ctx, errs.ErrBadRequest)
+	}
+
+	dryRun := params.DryRun != nil && *params.DryRun
+
+	diff, err := h.service.ImportTeams(ctx.Request().Context(), format, bytes.NewReader(body), dryRun)
+	if err != nil {
+		return mapErrorToHTTPResponse(ctx, err)
+	}
+
+	return ctx.JSON(http.StatusOK, toOAPIImportDiff(diff))
+}
+
+func importFormatByContentType(contentType string) models.ImportFormat {
+
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return ""
+	}
+
+	switch mediaType {
+	case "text/csv":
+		return models.ImportFormatCSV
+	case "application/yaml", "application/x-yaml", "text/yaml", "text/x-yaml":
+		return models.ImportFormatYAML
+	}
+
+	return ""
+}
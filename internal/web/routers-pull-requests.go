@@ -1,7 +1,10 @@
 package web
 
 import (
+	"fmt"
 	"net/http"
+	"strings"
+	"time"
 
 	"github.com/guarref/pr-service-assignment/internal/errs"
 	"github.com/guarref/pr-service-assignment/internal/models"
@@ -91,23 +94,278 @@ func (h *PullRequestHandler) PostPullRequestReassign(ctx echo.Context) error {
 	})
 }
 
+// /pullRequest/get get
+func (h *PullRequestHandler) GetPullRequestGet(ctx echo.Context, params omodels.GetPullRequestGetParams) error {
+
+	pr, err := h.service.GetPullRequest(ctx.Request().Context(), params.PullRequestId)
+	if err != nil {
+		return mapErrorToHTTPResponse(ctx, err)
+	}
+
+	return ctx.JSON(http.StatusOK, struct {
+		PR omodels.PullRequestDetails `json:"pr"`
+	}{PR: toOAPIPullRequestDetails(pr)})
+}
+
+// /pullRequest/getBatch post
+func (h *PullRequestHandler) PostPullRequestGetBatch(ctx echo.Context) error {
+
+	var body omodels.PostPullRequestGetBatchJSONRequestBody
+
+	if err := ctx.Bind(&body); err != nil {
+		return mapErrorToHTTPResponse(ctx, errs.ErrBadRequest)
+	}
+
+	prs, notFound, err := h.service.GetPullRequestsBatch(ctx.Request().Context(), body.PullRequestIds)
+	if err != nil {
+		return mapErrorToHTTPResponse(ctx, err)
+	}
+
+	respPRs := make([]omodels.PullRequestDetails, 0, len(prs))
+	for _, pr := range prs {
+		respPRs = append(respPRs, toOAPIPullRequestDetails(pr))
+	}
+
+	return ctx.JSON(http.StatusOK, struct {
+		PullRequests []omodels.PullRequestDetails `json:"pull_requests"`
+		NotFound     []string                     `json:"not_found"`
+	}{
+		PullRequests: respPRs,
+		NotFound:     notFound,
+	})
+}
+
+// /pullRequest/list get
+func (h *PullRequestHandler) GetPullRequestList(ctx echo.Context, params omodels.GetPullRequestListParams) error {
+
+	format, err := exportFormat(ctx, params.Format)
+	if err != nil {
+		return mapErrorToHTTPResponse(ctx, err)
+	}
+
+	filter := models.PullRequestListFilter{
+		CreatedFrom: params.CreatedFrom,
+		CreatedTo:   params.CreatedTo,
+		MergedFrom:  params.MergedFrom,
+		MergedTo:    params.MergedTo,
+	}
+	if params.Status != nil {
+		filter.Status = models.PullRequestStatus(*params.Status)
+	}
+	if params.Author != nil {
+		filter.AuthorID = *params.Author
+	}
+	if params.Reviewer != nil {
+		filter.ReviewerID = *params.Reviewer
+	}
+	if params.Team != nil {
+		filter.TeamName = *params.Team
+	}
+	if params.Sort != nil {
+		filter.Sort = models.PullRequestSort(*params.Sort)
+	}
+	if params.Order != nil {
+		filter.Desc = *params.Order == omodels.Desc
+	}
+
+	var cursor string
+	if params.Cursor != nil {
+		cursor = *params.Cursor
+	}
+
+	if format != omodels.ExportFormatJson {
+		return h.exportPullRequestList(ctx, format, filter, cursor)
+	}
+
+	prs, next, err := h.service.ListPullRequests(ctx.Request().Context(), filter, params.Limit, cursor)
+	if err != nil {
+		return mapErrorToHTTPResponse(ctx, err)
+	}
+
+	resp := omodels.PullRequestList{
+		PullRequests: make([]omodels.PullRequest, 0, len(prs)),
+	}
+	for _, pr := range prs {
+		resp.PullRequests = append(resp.PullRequests, toOAPIPullRequest(pr))
+	}
+	if next != "" {
+		resp.NextCursor = &next
+	}
+
+	return ctx.JSON(http.StatusOK, resp)
+}
+
+var pullRequestExportHeader = []string{"pull_request_id", "pull_request_name", "author_id", "status", "assigned_reviewers", "created_at", "merged_at"}
+
+// выгружает все PR по фильтру, начиная с cursor: страницы по MaxPageLimit читаются и отправляются клиенту по одной
+func (h *PullRequestHandler) exportPullRequestList(ctx echo.Context, format omodels.ExportFormat, filter models.PullRequestListFilter, cursor string) error {
+
+	limit := service.MaxPageLimit
+
+	// первая страница запрашивается до отправки статуса, чтобы ошибки фильтра вернулись обычным ответом
+	prs, next, err := h.service.ListPullRequests(ctx.Request().Context(), filter, &limit, cursor)
+	if err != nil {
+		return mapErrorToHTTPResponse(ctx, err)
+	}
+
+	w, err := newExportWriter(ctx, format, "pull_requests", pullRequestExportHeader)
+	if err != nil {
+		return err
+	}
+
+	for {
+		for _, pr := range prs {
+			var mergedAt string
+			if pr.MergedAt != nil {
+				mergedAt = pr.MergedAt.Format(time.RFC3339)
+			}
+
+			record := []string{
+				csvText(pr.PullRequestID),
+				csvText(pr.PullRequestName),
+				csvText(pr.AuthorID),
+				string(pr.Status),
+				csvText(strings.Join(pr.AssignedReviewers, ";")),
+				pr.CreatedAt.Format(time.RFC3339),
+				mergedAt,
+			}
+			if err := w.Write(record, toOAPIPullRequest(pr)); err != nil {
+				return fmt.Errorf("error writing pull request export: %w", err)
+			}
+		}
+
+		if err := w.Flush(); err != nil {
+			return err
+		}
+		if next == "" {
+			return nil
+		}
+
+		prs, next, err = h.service.ListPullRequests(ctx.Request().Context(), filter, &limit, next)
+		if err != nil {
+			return fmt.Errorf("error exporting pull requests: %w", err)
+		}
+	}
+}
+
+// /pullRequest/search get
+func (h *PullRequestHandler) GetPullRequestSearch(ctx echo.Context, params omodels.GetPullRequestSearchParams) error {
+
+	prs, err := h.service.SearchPullRequests(ctx.Request().Context(), params.Q, params.Limit)
+	if err != nil {
+		return mapErrorToHTTPResponse(ctx, err)
+	}
+
+	respList := make([]omodels.PullRequest, 0, len(prs))
+	for _, pr := range prs {
+		respList = append(respList, toOAPIPullRequest(pr))
+	}
+
+	return ctx.JSON(http.StatusOK, struct {
+		PullRequests []omodels.PullRequest `json:"pull_requests"`
+	}{PullRequests: respList})
+}
+
+// /pullRequest/timeline get
+func (h *PullRequestHandler) GetPullRequestTimeline(ctx echo.Context, params omodels.GetPullRequestTimelineParams) error {
+
+	events, err := h.service.GetPullRequestTimeline(ctx.Request().Context(), params.PullRequestId)
+	if err != nil {
+		return mapErrorToHTTPResponse(ctx, err)
+	}
+
+	respEvents := make([]omodels.PullRequestEvent, 0, len(events))
+	for _, e := range events {
+		respEvents = append(respEvents, toOAPIPullRequestEvent(e))
+	}
+
+	return ctx.JSON(http.StatusOK, struct {
+		PullRequestID string                     `json:"pull_request_id"`
+		Events        []omodels.PullRequestEvent `json:"events"`
+	}{
+		PullRequestID: params.PullRequestId,
+		Events:        respEvents,
+	})
+}
+
+// /users/getAuthored get
+func (h *PullRequestHandler) GetUsersGetAuthored(ctx echo.Context, params omodels.GetUsersGetAuthoredParams) error {
+
+	userID := params.UserId
+
+	filter := models.AuthoredPullRequestsFilter{AuthorID: userID}
+	if params.Status != nil {
+		filter.Status = models.PullRequestStatus(*params.Status)
+	}
+
+	var cursor string
+	if params.Cursor != nil {
+		cursor = *params.Cursor
+	}
+
+	prs, next, err := h.service.GetPullRequestsByAuthor(ctx.Request().Context(), filter, params.Limit, cursor)
+	if err != nil {
+		return mapErrorToHTTPResponse(ctx, err)
+	}
+
+	respList := make([]omodels.PullRequestDetails, 0, len(prs))
+	for _, pr := range prs {
+		respList = append(respList, toOAPIPullRequestDetails(pr))
+	}
+
+	var nextCursor *string
+	if next != "" {
+		nextCursor = &next
+	}
+
+	return ctx.JSON(http.StatusOK, struct {
+		UserID       string                       `json:"user_id"`
+		PullRequests []omodels.PullRequestDetails `json:"pull_requests"`
+		NextCursor   *string                      `json:"next_cursor,omitempty"`
+	}{
+		UserID:       userID,
+		PullRequests: respList,
+		NextCursor:   nextCursor,
+	})
+}
+
 // /users/getReview get
 func (h *PullRequestHandler) GetUsersGetReview(ctx echo.Context, params omodels.GetUsersGetReviewParams) error {
 
 	userID := params.UserId
 
-	prs, err := h.service.GetPullRequestsByReviewer(ctx.Request().Context(), userID)
+	filter := models.ReviewQueueFilter{ReviewerID: userID}
+	if params.Status != nil {
+		filter.Status = models.PullRequestStatus(*params.Status)
+	}
+	if params.Sort != nil {
+		filter.Sort = models.ReviewQueueSort(*params.Sort)
+	}
+
+	var cursor string
+	if params.Cursor != nil {
+		cursor = *params.Cursor
+	}
+
+	prs, next, err := h.service.GetPullRequestsByReviewer(ctx.Request().Context(), filter, params.Limit, cursor)
 	if err != nil {
 		return mapErrorToHTTPResponse(ctx, err)
 	}
 
 	respList := toOAPIPullRequestShortList(prs)
 
+	var nextCursor *string
+	if next != "" {
+		nextCursor = &next
+	}
+
 	return ctx.JSON(http.StatusOK, struct {
 		UserID       string                     `json:"user_id"`
 		PullRequests []omodels.PullRequestShort `json:"pull_requests"`
+		NextCursor   *string                    `json:"next_cursor,omitempty"`
 	}{
 		UserID:       userID,
 		PullRequests: respList,
+		NextCursor:   nextCursor,
 	})
 }
@@ -0,0 +1,503 @@
+package web
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/guarref/pr-service-assignment/internal/errs"
+	"github.com/guarref/pr-service-assignment/internal/models"
+	"github.com/guarref/pr-service-assignment/internal/service"
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	ScimBasePath = "/scim/v2"
+
+	scimContentType = "application/scim+json"
+
+	scimSchemaUser           = "urn:ietf:params:scim:schemas:core:2.0:User"
+	scimSchemaGroup          = "urn:ietf:params:scim:schemas:core:2.0:Group"
+	scimSchemaListResponse   = "urn:ietf:params:scim:api:messages:2.0:ListResponse"
+	scimSchemaError          = "urn:ietf:params:scim:api:messages:2.0:Error"
+	scimSchemaProviderConfig = "urn:ietf:params:scim:schemas:core:2.0:ServiceProviderConfig"
+)
+
+type scimMeta struct {
+	ResourceType string    `json:"resourceType"`
+	Created      time.Time `json:"created"`
+	LastModified time.Time `json:"lastModified"`
+	Location     string    `json:"location"`
+}
+
+type scimName struct {
+	Formatted  string `json:"formatted,omitempty"`
+	GivenName  string `json:"givenName,omitempty"`
+	FamilyName string `json:"familyName,omitempty"`
+}
+
+type scimRef struct {
+	Value   string `json:"value"`
+	Display string `json:"display,omitempty"`
+	Ref     string `json:"$ref,omitempty"`
+}
+
+type scimUser struct {
+	Schemas     []string  `json:"schemas"`
+	ID          string    `json:"id,omitempty"`
+	UserName    string    `json:"userName"`
+	DisplayName string    `json:"displayName,omitempty"`
+	Name        *scimName `json:"name,omitempty"`
+	Active      *bool     `json:"active,omitempty"`
+	Groups      []scimRef `json:"groups,omitempty"`
+	Meta        *scimMeta `json:"meta,omitempty"`
+}
+
+type scimGroup struct {
+	Schemas     []string  `json:"schemas"`
+	ID          string    `json:"id,omitempty"`
+	DisplayName string    `json:"displayName"`
+	Members     []scimRef `json:"members,omitempty"`
+	Meta        *scimMeta `json:"meta,omitempty"`
+}
+
+type scimListResponse struct {
+	Schemas      []string `json:"schemas"`
+	TotalResults int      `json:"totalResults"`
+	StartIndex   int      `json:"startIndex"`
+	ItemsPerPage int      `json:"itemsPerPage"`
+	Resources    []any    `json:"Resources"`
+}
+
+type scimPatchRequest struct {
+	Schemas    []string                    `json:"schemas"`
+	Operations []models.ScimPatchOperation `json:"Operations"`
+}
+
+type scimError struct {
+	Schemas  []string `json:"schemas"`
+	Status   string   `json:"status"`
+	ScimType string   `json:"scimType,omitempty"`
+	Detail   string   `json:"detail"`
+}
+
+type ScimHandler struct {
+	service *service.ScimService
+}
+
+func NewScimHandler(s *service.ScimService) *ScimHandler {
+	return &ScimHandler{service: s}
+}
+
+// SCIM живёт вне OpenAPI-спецификации: свои схемы ответов, ошибок и отдельный токен
+func RegisterScimRoutes(e *echo.Echo, scimSvc *service.ScimService, token string) {
+
+	h := NewScimHandler(scimSvc)
+
+	g := e.Group(ScimBasePath, ScimAuthMiddleware(token))
+	g.GET("/ServiceProviderConfig", h.GetServiceProviderConfig)
+	g.GET("/Users", h.GetUsers)
+	g.POST("/Users", h.PostUser)
+	g.GET("/Users/:id", h.GetUser)
+	g.PUT("/Users/:id", h.PutUser)
+	g.PATCH("/Users/:id", h.PatchUser)
+	g.DELETE("/Users/:id", h.DeleteUser)
+	g.GET("/Groups", h.GetGroups)
+	g.POST("/Groups", h.PostGroup)
+	g.GET("/Groups/:id", h.GetGroup)
+	g.PUT("/Groups/:id", h.PutGroup)
+	g.PATCH("/Groups/:id", h.PatchGroup)
+	g.DELETE("/Groups/:id", h.DeleteGroup)
+}
+
+// /scim/v2/ServiceProviderConfig get
+func (h *ScimHandler) GetServiceProviderConfig(ctx echo.Context) error {
+
+	supported := func(v bool) map[string]bool { return map[string]bool{"supported": v} }
+
+	return scimJSON(ctx, http.StatusOK, map[string]any{
+		"schemas":        []string{scimSchemaProviderConfig},
+		"patch":          supported(true),
+		"bulk":           map[string]any{"supported": false, "maxOperations": 0, "maxPayloadSize": 0},
+		"filter":         map[string]any{"supported": true, "maxResults": service.MaxPageLimit},
+		"changePassword": supported(false),
+		"sort":           supported(false),
+		"etag":           supported(false),
+		"authenticationSchemes": []map[string]any{{
+			"type":        "oauthbearertoken",
+			"name":        "Bearer Token",
+			"description": "static token from SCIM_TOKEN",
+		}},
+	})
+}
+
+// /scim/v2/Users get
+func (h *ScimHandler) GetUsers(ctx echo.Context) error {
+
+	startIndex, count, err := scimPageParams(ctx)
+	if err != nil {
+		return scimErrorResponse(ctx, err)
+	}
+
+	users, total, err := h.service.ListUsers(ctx.Request().Context(), ctx.QueryParam("filter"), startIndex, count)
+	if err != nil {
+		return scimErrorResponse(ctx, err)
+	}
+
+	resources := make([]any, 0, len(users))
+	for _, u := range users {
+		resources = append(resources, toScimUser(ctx, u))
+	}
+
+	return scimJSON(ctx, http.StatusOK, newScimListResponse(resources, total, startIndex))
+}
+
+// /scim/v2/Users post
+func (h *ScimHandler) PostUser(ctx echo.Context) error {
+
+	var body scimUser
+	if err := decodeScimBody(ctx, &body); err != nil {
+		return scimErrorResponse(ctx, err)
+	}
+
+	user, err := h.service.CreateUser(ctx.Request().Context(), fromScimUser(body))
+	if err != nil {
+		return scimErrorResponse(ctx, err)
+	}
+
+	resp := toScimUser(ctx, user)
+	ctx.Response().Header().Set(echo.HeaderLocation, resp.Meta.Location)
+
+	return scimJSON(ctx, http.StatusCreated, resp)
+}
+
+// /scim/v2/Users/:id get
+func (h *ScimHandler) GetUser(ctx echo.Context) error {
+
+	user, err := h.service.GetUser(ctx.Request().Context(), ctx.Param("id"))
+	if err != nil {
+		return scimErrorResponse(ctx, err)
+	}
+
+	return scimJSON(ctx, http.StatusOK, toScimUser(ctx, user))
+}
+
+// /scim/v2/Users/:id put
+func (h *ScimHandler) PutUser(ctx echo.Context) error {
+
+	var body scimUser
+	if err := decodeScimBody(ctx, &body); err != nil {
+		return scimErrorResponse(ctx, err)
+	}
+
+	user, err := h.service.ReplaceUser(ctx.Request().Context(), ctx.Param("id"), fromScimUser(body))
+	if err != nil {
+		return scimErrorResponse(ctx, err)
+	}
+
+	return scimJSON(ctx, http.StatusOK, toScimUser(ctx, user))
+}
+
+// /scim/v2/Users/:id patch
+func (h *ScimHandler) PatchUser(ctx echo.Context) error {
+
+	var body scimPatchRequest
+	if err := decodeScimBody(ctx, &body); err != nil {
+		return scimErrorResponse(ctx, err)
+	}
+
+	user, err := h.service.PatchUser(ctx.Request().Context(), ctx.Param("id"), body.Operations)
+	if err != nil {
+		return scimErrorResponse(ctx, err)
+	}
+
+	return scimJSON(ctx, http.StatusOK, toScimUser(ctx, user))
+}
+
+// /scim/v2/Users/:id delete
+func (h *ScimHandler) DeleteUser(ctx echo.Context) error {
+
+	if err := h.service.DeprovisionUser(ctx.Request().Context(), ctx.Param("id")); err != nil {
+		return scimErrorResponse(ctx, err)
+	}
+
+	return ctx.NoContent(http.StatusNoContent)
+}
+
+// /scim/v2/Groups get
+func (h *ScimHandler) GetGroups(ctx echo.Context) error {
+
+	startIndex, count, err := scimPageParams(ctx)
+	if err != nil {
+		return scimErrorResponse(ctx, err)
+	}
+
+	teams, total, err := h.service.ListGroups(ctx.Request().Context(), ctx.QueryParam("filter"), startIndex, count)
+	if err != nil {
+		return scimErrorResponse(ctx, err)
+	}
+
+	// Azure AD запрашивает группы с excludedAttributes=members
+	withMembers := !strings.Contains(strings.ToLower(ctx.QueryParam("excludedAttributes")), "members")
+
+	resources := make([]any, 0, len(teams))
+	for _, t := range teams {
+		resources = append(resources, toScimGroup(ctx, t, withMembers))
+	}
+
+	return scimJSON(ctx, http.StatusOK, newScimListResponse(resources, total, startIndex))
+}
+
+// /scim/v2/Groups post
+func (h *ScimHandler) PostGroup(ctx echo.Context) error {
+
+	var body scimGroup
+	if err := decodeScimBody(ctx, &body); err != nil {
+		return scimErrorResponse(ctx, err)
+	}
+
+	team, err := h.service.CreateGroup(ctx.Request().Context(), body.DisplayName, scimRefValues(body.Members))
+	if err != nil {
+		return scimErrorResponse(ctx, err)
+	}
+
+	resp := toScimGroup(ctx, team, true)
+	ctx.Response().Header().Set(echo.HeaderLocation, resp.Meta.Location)
+
+	return scimJSON(ctx, http.StatusCreated, resp)
+}
+
+// /scim/v2/Groups/:id get
+func (h *ScimHandler) GetGroup(ctx echo.Context) error {
+
+	team, err := h.service.GetGroup(ctx.Request().Context(), ctx.Param("id"))
+	if err != nil {
+		return scimErrorResponse(ctx, err)
+	}
+
+	withMembers := !strings.Contains(strings.ToLower(ctx.QueryParam("excludedAttributes")), "members")
+
+	return scimJSON(ctx, http.StatusOK, toScimGroup(ctx, team, withMembers))
+}
+
+// /scim/v2/Groups/:id put
+func (h *ScimHandler) PutGroup(ctx echo.Context) error {
+
+	var body scimGroup
+	if err := decodeScimBody(ctx, &body); err != nil {
+		return scimErrorResponse(ctx, err)
+	}
+
+	team, err := h.service.ReplaceGroup(ctx.Request().Context(), ctx.Param("id"), body.DisplayName, scimRefValues(body.Members))
+	if err != nil {
+		return scimErrorResponse(ctx, err)
+	}
+
+	return scimJSON(ctx, http.StatusOK, toScimGroup(ctx, team, true))
+}
+
+// /scim/v2/Groups/:id patch
+func (h *ScimHandler) PatchGroup(ctx echo.Context) error {
+
+	var body scimPatchRequest
+	if err := decodeScimBody(ctx, &body); err != nil {
+		return scimErrorResponse(ctx, err)
+	}
+
+	team, err := h.service.PatchGroup(ctx.Request().Context(), ctx.Param("id"), body.Operations)
+	if err != nil {
+		return scimErrorResponse(ctx, err)
+	}
+
+	return scimJSON(ctx, http.StatusOK, toScimGroup(ctx, team, true))
+}
+
+// /scim/v2/Groups/:id delete
+func (h *ScimHandler) DeleteGroup(ctx echo.Context) error {
+
+	if err := h.service.DeleteGroup(ctx.Request().Context(), ctx.Param("id")); err != nil {
+		return scimErrorResponse(ctx, err)
+	}
+
+	return ctx.NoContent(http.StatusNoContent)
+}
+
+func scimJSON(ctx echo.Context, status int, v any) error {
+
+	ctx.Response().Header().Set(echo.HeaderContentType, scimContentType)
+	ctx.Response().WriteHeader(status)
+
+	return json.NewEncoder(ctx.Response()).Encode(v)
+}
+
+// тело читается вручную: Bind не знает тип application/scim+json
+func decodeScimBody(ctx echo.Context, v any) error {
+
+	if err := json.NewDecoder(ctx.Request().Body).Decode(v); err != nil {
+		return errs.ErrInvalidJSON
+	}
+
+	return nil
+}
+
+func scimErrorResponse(ctx echo.Context, err error) error {
+
+	var respErr *errs.RespError
+	if !errors.As(err, &respErr) {
+		ctx.Set(errorCodeKey, internalErrorCode)
+		return scimJSON(ctx, http.StatusInternalServerError, scimError{
+			Schemas: []string{scimSchemaError},
+			Status:  strconv.Itoa(http.StatusInternalServerError),
+			Detail:  "internal server error",
+		})
+	}
+
+	var scimType string
+	switch respErr {
+	case errs.ErrInvalidFilter:
+		scimType = "invalidFilter"
+	case errs.ErrImmutableAttribute:
+		scimType = "mutability"
+	case errs.ErrInvalidJSON:
+		scimType = "invalidSyntax"
+	case errs.ErrBadRequest:
+		scimType = "invalidValue"
+	case errs.ErrUserExists, errs.ErrTeamExists:
+		scimType = "uniqueness"
+	}
+
+	ctx.Set(errorCodeKey, respErr.Code)
+	return scimJSON(ctx, respErr.StatusCode, scimError{
+		Schemas:  []string{scimSchemaError},
+		Status:   strconv.Itoa(respErr.StatusCode),
+		ScimType: scimType,
+		Detail:   respErr.Message,
+	})
+}
+
+func scimPageParams(ctx echo.Context) (*int, *int, error) {
+
+	parse := func(name string) (*int, error) {
+		raw := ctx.QueryParam(name)
+		if raw == "" {
+			return nil, nil
+		}
+		v, err := strconv.Atoi(raw)
+		if err != nil {
+			return nil, errs.ErrBadRequest
+		}
+		return &v, nil
+	}
+
+	startIndex, err := parse("startIndex")
+	if err != nil {
+		return nil, nil, err
+	}
+	count, err := parse("count")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return startIndex, count, nil
+}
+
+func newScimListResponse(resources []any, total int, startIndex *int) scimListResponse {
+
+	start := 1
+	if startIndex != nil && *startIndex > 1 {
+		start = *startIndex
+	}
+
+	return scimListResponse{
+		Schemas:      []string{scimSchemaListResponse},
+		TotalResults: total,
+		StartIndex:   start,
+		ItemsPerPage: len(resources),
+		Resources:    resources,
+	}
+}
+
+func scimLocation(ctx echo.Context, resource string, id string) string {
+	return ctx.Scheme() + "://" + ctx.Request().Host + ScimBasePath + "/" + resource + "/" + id
+}
+
+func toScimUser(ctx echo.Context, u *models.User) scimUser {
+
+	active := u.IsActive
+
+	groups := make([]scimRef, 0, len(u.Teams))
+	for _, team := range u.Teams {
+		groups = append(groups, scimRef{Value: team, Display: team, Ref: scimLocation(ctx, "Groups", team)})
+	}
+
+	return scimUser{
+		Schemas:     []string{scimSchemaUser},
+		ID:          u.UserID,
+		UserName:    u.UserID,
+		DisplayName: u.UserName,
+		Name:        &scimName{Formatted: u.UserName},
+		Active:      &active,
+		Groups:      groups,
+		Meta: &scimMeta{
+			ResourceType: "User",
+			Created:      u.CreatedAt,
+			LastModified: u.UpdatedAt,
+			Location:     scimLocation(ctx, "Users", u.UserID),
+		},
+	}
+}
+
+// displayName, иначе name.formatted, иначе имя и фамилия; active по умолчанию true
+func fromScimUser(u scimUser) *models.User {
+
+	user := &models.User{UserID: u.UserName, UserName: u.DisplayName, IsActive: true}
+	if u.Active != nil {
+		user.IsActive = *u.Active
+	}
+
+	if user.UserName == "" && u.Name != nil {
+		user.UserName = u.Name.Formatted
+		if user.UserName == "" {
+			user.UserName = strings.TrimSpace(u.Name.GivenName + " " + u.Name.FamilyName)
+		}
+	}
+
+	return user
+}
+
+func toScimGroup(ctx echo.Context, t *models.Team, withMembers bool) scimGroup {
+
+	group := scimGroup{
+		Schemas:     []string{scimSchemaGroup},
+		ID:          t.TeamName,
+		DisplayName: t.TeamName,
+		Meta: &scimMeta{
+			ResourceType: "Group",
+			Created:      t.CreatedAt,
+			LastModified: t.UpdatedAt,
+			Location:     scimLocation(ctx, "Groups", t.TeamName),
+		},
+	}
+
+	if withMembers {
+		group.Members = make([]scimRef, 0, len(t.Members))
+		for _, m := range t.Members {
+			group.Members = append(group.Members, scimRef{Value: m.UserID, Display: m.UserName, Ref: scimLocation(ctx, "Users", m.UserID)})
+		}
+	}
+
+	return group
+}
+
+func scimRefValues(refs []scimRef) []string {
+
+	values := make([]string, 0, len(refs))
+	for _, r := range refs {
+		values = append(values, r.Value)
+	}
+
+	return values
+}
@@ -2,7 +2,9 @@ package web
 
 import (
 	"net/http"
+	"time"
 
+	"github.com/guarref/pr-service-assignment/internal/models"
 	"github.com/guarref/pr-service-assignment/internal/service"
 	"github.com/guarref/pr-service-assignment/internal/web/omodels"
 	"github.com/labstack/echo/v4"
@@ -19,6 +21,11 @@ func NewStatsHandler(s *service.StatsService) *StatsHandler {
 // /stats get
 func (h *StatsHandler) GetStats(ctx echo.Context, params omodels.GetStatsParams) error {
 
+	format, err := exportFormat(ctx, params.Format)
+	if err != nil {
+		return mapErrorToHTTPResponse(ctx, err)
+	}
+
 	var top *int
 
 	if params.Top != nil {
@@ -26,11 +33,35 @@ func (h *StatsHandler) GetStats(ctx echo.Context, params omodels.GetStatsParams)
 		top = &val
 	}
 
-	stats, err := h.service.GetStats(ctx.Request().Context(), top)
+	var teamName string
+	if params.Team != nil {
+		teamName = *params.Team
+	}
+
+	window := models.StatsWindow{From: params.From, To: params.To}
+
+	stats, err := h.service.GetStats(ctx.Request().Context(), top, teamName, window)
 	if err != nil {
 		return mapErrorToHTTPResponse(ctx, err)
 	}
 
+	if format != omodels.ExportFormatJson {
+		rows := []statsExportRow{
+			{Metric: "as_of", Value: stats.AsOf.UTC().Format(time.RFC3339)},
+			{Metric: "total_teams", Value: stats.TotalTeams},
+			{Metric: "total_users", Value: stats.TotalUsers},
+			{Metric: "active_users", Value: stats.ActiveUsers},
+			{Metric: "total_pull_requests", Value: stats.TotalPullRequests},
+			{Metric: "open_pull_requests", Value: stats.OpenPullRequests},
+			{Metric: "merged_pull_requests", Value: stats.MergedPullRequests},
+		}
+		for _, r := range stats.TopReviewers {
+			rows = append(rows, statsExportRow{Metric: "review_count", UserID: r.UserID, Username: r.UserName, Value: r.ReviewCount})
+		}
+
+		return writeStatsExport(ctx, format, "stats", rows)
+	}
+
 	topReviewers := make([]omodels.TopReviewer, 0, len(stats.TopReviewers))
 	for _, r := range stats.TopReviewers {
 		topReviewers = append(topReviewers, omodels.TopReviewer{
@@ -41,12 +72,269 @@ func (h *StatsHandler) GetStats(ctx echo.Context, params omodels.GetStatsParams)
 	}
 
 	resp := omodels.Stats{
-		TotalTeams:        stats.TotalTeams,
-		TotalUsers:        stats.TotalUsers,
-		ActiveUsers:       stats.ActiveUsers,
-		TotalPullRequests: stats.TotalPullRequests,
-		OpenPullRequests:  stats.OpenPullRequests,
-		TopReviewers:      topReviewers,
+		TotalTeams:         stats.TotalTeams,
+		TotalUsers:         stats.TotalUsers,
+		ActiveUsers:        stats.ActiveUsers,
+		TotalPullRequests:  stats.TotalPullRequests,
+		OpenPullRequests:   stats.OpenPullRequests,
+		MergedPullRequests: stats.MergedPullRequests,
+		TopReviewers:       topReviewers,
+		AsOf:               stats.AsOf,
+	}
+
+	return ctx.JSON(http.StatusOK, resp)
+}
+
+// /stats/team get
+func (h *StatsHandler) GetStatsTeam(ctx echo.Context, params omodels.GetStatsTeamParams) error {
+
+	format, err := exportFormat(ctx, params.Format)
+	if err != nil {
+		return mapErrorToHTTPResponse(ctx, err)
+	}
+
+	stats, err := h.service.GetTeamStats(ctx.Request().Context(), params.TeamName)
+	if err != nil {
+		return mapErrorToHTTPResponse(ctx, err)
+	}
+
+	if format != omodels.ExportFormatJson {
+		rows := []statsExportRow{
+			{Metric: "open_pull_requests", Value: stats.OpenPullRequests},
+			{Metric: "merged_pull_requests", Value: stats.MergedPullRequests},
+			{Metric: "total_members", Value: stats.TotalMembers},
+			{Metric: "active_members", Value: stats.ActiveMembers},
+			{Metric: "avg_reviewers_per_pr", Value: stats.AvgReviewersPerPR},
+			{Metric: "required_reviewers", Value: stats.RequiredReviewers},
+			{Metric: "understaffed_pull_requests", Value: stats.UnderstaffedPullRequests},
+		}
+		for _, m := range stats.Members {
+			rows = append(rows,
+				statsExportRow{Metric: "is_active", UserID: m.UserID, Username: m.UserName, Value: m.IsActive},
+				statsExportRow{Metric: "open_reviews", UserID: m.UserID, Username: m.UserName, Value: m.OpenReviews},
+				statsExportRow{Metric: "total_reviews", UserID: m.UserID, Username: m.UserName, Value: m.TotalReviews},
+			)
+		}
+
+		return writeStatsExport(ctx, format, "team-"+stats.TeamName, rows)
+	}
+
+	members := make([]omodels.TeamMemberStats, 0, len(stats.Members))
+	for _, m := range stats.Members {
+		members = append(members, omodels.TeamMemberStats{
+			UserId:       m.UserID,
+			Username:     m.UserName,
+			IsActive:     m.IsActive,
+			OpenReviews:  m.OpenReviews,
+			TotalReviews: m.TotalReviews,
+		})
+	}
+
+	resp := omodels.TeamStats{
+		TeamName:                 stats.TeamName,
+		OpenPullRequests:         stats.OpenPullRequests,
+		MergedPullRequests:       stats.MergedPullRequests,
+		TotalMembers:             stats.TotalMembers,
+		ActiveMembers:            stats.ActiveMembers,
+		AvgReviewersPerPr:        stats.AvgReviewersPerPR,
+		RequiredReviewers:        stats.RequiredReviewers,
+		UnderstaffedPullRequests: stats.UnderstaffedPullRequests,
+		Members:                  members,
+	}
+
+	return ctx.JSON(http.StatusOK, resp)
+}
+
+// /stats/latency get
+func (h *StatsHandler) GetStatsLatency(ctx echo.Context, params omodels.GetStatsLatencyParams) error {
+
+	window := models.StatsWindow{From: params.From, To: params.To}
+
+	stats, err := h.service.GetLatencyStats(ctx.Request().Context(), window)
+	if err != nil {
+		return mapErrorToHTTPResponse(ctx, err)
+	}
+
+	teams := make([]omodels.TeamLatency, 0, len(stats.Teams))
+	for _, t := range stats.Teams {
+		teams = append(teams, omodels.TeamLatency{
+			TeamName:        t.TeamName,
+			TimeToMerge:     toOAPILatencyPercentiles(t.TimeToMerge),
+			AssignedToMerge: toOAPILatencyPercentiles(t.AssignedToMerge),
+		})
+	}
+
+	reviewers := make([]omodels.ReviewerLatency, 0, len(stats.Reviewers))
+	for _, r := range stats.Reviewers {
+		reviewers = append(reviewers, omodels.ReviewerLatency{
+			UserId:          r.UserID,
+			Username:        r.UserName,
+			AssignedToMerge: toOAPILatencyPercentiles(r.AssignedToMerge),
+		})
+	}
+
+	resp := omodels.LatencyStats{
+		TimeToMerge:     toOAPILatencyPercentiles(stats.TimeToMerge),
+		AssignedToMerge: toOAPILatencyPercentiles(stats.AssignedToMerge),
+		Teams:           teams,
+		Reviewers:       reviewers,
+	}
+
+	return ctx.JSON(http.StatusOK, resp)
+}
+
+// /stats/aging get
+func (h *StatsHandler) GetStatsAging(ctx echo.Context, params omodels.GetStatsAgingParams) error {
+
+	var teamName string
+	if params.Team != nil {
+		teamName = *params.Team
+	}
+
+	stats, err := h.service.GetAgingStats(ctx.Request().Context(), teamName, params.Limit)
+	if err != nil {
+		return mapErrorToHTTPResponse(ctx, err)
+	}
+
+	resp := omodels.AgingStats{
+		AsOf:      stats.AsOf,
+		Total:     toOAPIAgingBuckets(stats.Total),
+		Teams:     make([]omodels.TeamAging, 0, len(stats.Teams)),
+		Reviewers: make([]omodels.ReviewerAging, 0, len(stats.Reviewers)),
+		Oldest:    make([]omodels.AgingPullRequest, 0, len(stats.Oldest)),
+	}
+
+	for _, t := range stats.Teams {
+		resp.Teams = append(resp.Teams, omodels.TeamAging{
+			TeamName: t.TeamName,
+			Buckets:  toOAPIAgingBuckets(t.AgingBuckets),
+		})
+	}
+
+	for _, r := range stats.Reviewers {
+		resp.Reviewers = append(resp.Reviewers, omodels.ReviewerAging{
+			UserId:   r.UserID,
+			Username: r.UserName,
+			Buckets:  toOAPIAgingBuckets(r.AgingBuckets),
+		})
+	}
+
+	for _, pr := range stats.Oldest {
+		resp.Oldest = append(resp.Oldest, omodels.AgingPullRequest{
+			PullRequestId:     pr.PullRequestID,
+			PullRequestName:   pr.PullRequestName,
+			AuthorId:          pr.AuthorID,
+			CreatedAt:         pr.CreatedAt,
+			AgeSeconds:        pr.AgeSeconds,
+			AssignedReviewers: pr.AssignedReviewers,
+		})
+	}
+
+	return ctx.JSON(http.StatusOK, resp)
+}
+
+// /stats/fairness get
+func (h *StatsHandler) GetStatsFairness(ctx echo.Context, params omodels.GetStatsFairnessParams) error {
+
+	var teamName string
+	if params.Team != nil {
+		teamName = *params.Team
+	}
+
+	window := models.StatsWindow{From: params.From, To: params.To}
+
+	teams, err := h.service.GetFairnessStats(ctx.Request().Context(), teamName, window)
+	if err != nil {
+		return mapErrorToHTTPResponse(ctx, err)
+	}
+
+	resp := omodels.FairnessStats{Teams: make([]omodels.TeamFairness, 0, len(teams))}
+	for _, t := range teams {
+		resp.Teams = append(resp.Teams, omodels.TeamFairness{
+			TeamName:         t.TeamName,
+			Reviewers:        t.Reviewers,
+			TotalAssignments: t.TotalAssignments,
+			Min:              t.Min,
+			Max:              t.Max,
+			Mean:             t.Mean,
+			Stddev:           t.StdDev,
+			Gini:             t.Gini,
+			Overloaded:       toOAPIReviewerLoads(t.Overloaded),
+			Underloaded:      toOAPIReviewerLoads(t.Underloaded),
+		})
+	}
+
+	return ctx.JSON(http.StatusOK, resp)
+}
+
+// /stats/timeseries get
+func (h *StatsHandler) GetStatsTimeseries(ctx echo.Context, params omodels.GetStatsTimeseriesParams) error {
+
+	var bucket models.TimeseriesBucket
+	if params.Bucket != nil {
+		bucket = models.TimeseriesBucket(*params.Bucket)
+	}
+
+	var teamName string
+	if params.Team != nil {
+		teamName = *params.Team
+	}
+
+	window := models.StatsWindow{From: params.From, To: params.To}
+
+	series, err := h.service.GetTimeseries(ctx.Request().Context(), models.TimeseriesMetric(params.Metric), bucket, teamName, window)
+	if err != nil {
+		return mapErrorToHTTPResponse(ctx, err)
+	}
+
+	points := make([]omodels.TimeseriesPoint, 0, len(series.Points))
+	for _, p := range series.Points {
+		points = append(points, omodels.TimeseriesPoint{
+			BucketStart: p.BucketStart,
+			Value:       p.Value,
+		})
+	}
+
+	resp := omodels.Timeseries{
+		Metric: omodels.TimeseriesMetric(series.Metric),
+		Bucket: omodels.TimeseriesBucket(series.Bucket),
+		From:   series.From,
+		To:     series.To,
+		Points: points,
+	}
+	if series.TeamName != "" {
+		resp.Team = &series.TeamName
+	}
+
+	return ctx.JSON(http.StatusOK, resp)
+}
+
+// /stats/user get
+func (h *StatsHandler) GetStatsUser(ctx echo.Context, params omodels.GetStatsUserParams) error {
+
+	stats, err := h.service.GetUserStats(ctx.Request().Context(), params.UserId)
+	if err != nil {
+		return mapErrorToHTTPResponse(ctx, err)
+	}
+
+	teams := make([]omodels.TeamReviewCount, 0, len(stats.ReviewedTeams))
+	for _, t := range stats.ReviewedTeams {
+		teams = append(teams, omodels.TeamReviewCount{
+			TeamName: t.TeamName,
+			Reviews:  t.Reviews,
+		})
+	}
+
+	resp := omodels.UserStats{
+		UserId:               stats.UserID,
+		Username:             stats.UserName,
+		AuthoredPullRequests: stats.AuthoredPullRequests,
+		OpenReviews:          stats.OpenReviews,
+		TotalReviews:         stats.TotalReviews,
+		ReassignedAway:       stats.ReassignedAway,
+		MergedWhileReviewer:  stats.MergedWhileReviewer,
+		MedianHeldSeconds:    stats.MedianHeldSeconds,
+		ReviewedTeams:        teams,
 	}
 
 	return ctx.JSON(http.StatusOK, resp)
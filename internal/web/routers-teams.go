@@ -28,16 +28,22 @@ func (h *TeamHandler) PostTeamAdd(ctx echo.Context) error {
 	}
 
 	team := models.Team{
-		TeamName: body.TeamName,
-		Members:  make([]models.TeamMember, 0, len(body.Members)),
+		TeamName:   body.TeamName,
+		ParentTeam: body.ParentTeam,
+		Members:    make([]models.TeamMember, 0, len(body.Members)),
 	}
 
 	for _, m := range body.Members {
-		team.Members = append(team.Members, models.TeamMember{
-			UserID:   m.UserId,
-			UserName: m.Username,
-			IsActive: m.IsActive,
-		})
+		member := models.TeamMember{
+			UserID:    m.UserId,
+			UserName:  m.Username,
+			IsActive:  m.IsActive,
+			IsPrimary: m.IsPrimary != nil && *m.IsPrimary,
+		}
+		if m.Role != nil {
+			member.Role = models.TeamRole(*m.Role)
+		}
+		team.Members = append(team.Members, member)
 	}
 
 	if err := h.service.CreateTeam(ctx.Request().Context(), &team); err != nil {
@@ -68,6 +74,47 @@ func (h *TeamHandler) GetTeamGet(ctx echo.Context, params omodels.GetTeamGetPara
 	return ctx.JSON(http.StatusOK, respTeam)
 }
 
+// /team/list get
+func (h *TeamHandler) GetTeamList(ctx echo.Context, params omodels.GetTeamListParams) error {
+
+	filter := models.TeamListFilter{
+		IsActive: params.IsActive,
+	}
+	if params.Search != nil {
+		filter.Search = *params.Search
+	}
+
+	var cursor string
+	if params.Cursor != nil {
+		cursor = *params.Cursor
+	}
+
+	teams, next, err := h.service.ListTeams(ctx.Request().Context(), filter, params.Limit, cursor)
+	if err != nil {
+		return mapErrorToHTTPResponse(ctx, err)
+	}
+
+	resp := omodels.TeamList{
+		Teams: make([]omodels.TeamSummary, 0, len(teams)),
+	}
+	for _, t := range teams {
+		resp.Teams = append(resp.Teams, omodels.TeamSummary{
+			TeamName:          t.TeamName,
+			ParentTeam:        t.ParentTeam,
+			IsActive:          t.IsActive,
+			CreatedAt:         t.CreatedAt,
+			MemberCount:       t.MemberCount,
+			ActiveMemberCount: t.ActiveMemberCount,
+			OpenPullRequests:  t.OpenPullRequests,
+		})
+	}
+	if next != "" {
+		resp.NextCursor = &next
+	}
+
+	return ctx.JSON(http.StatusOK, resp)
+}
+
 // team/deactivate post
 func (h *TeamHandler) PostTeamDeactivate(ctx echo.Context) error {
 
@@ -93,3 +140,50 @@ func (h *TeamHandler) PostTeamDeactivate(ctx echo.Context) error {
 
 	return ctx.JSON(http.StatusOK, resp)
 }
+
+// /team/archive post
+func (h *TeamHandler) PostTeamArchive(ctx echo.Context) error {
+
+	var body omodels.PostTeamArchiveJSONRequestBody
+
+	if err := ctx.Bind(&body); err != nil {
+		return mapErrorToHTTPResponse(ctx, errs.ErrBadRequest)
+	}
+
+	archive, err := h.service.ArchiveTeam(ctx.Request().Context(), body.TeamName)
+	if err != nil {
+		return mapErrorToHTTPResponse(ctx, err)
+	}
+
+	resp := omodels.TeamArchiveResponse{
+		TeamName:         archive.TeamName,
+		DeactivatedUsers: archive.DeactivatedUsers,
+		ArchivedAt:       archive.ArchivedAt,
+	}
+
+	return ctx.JSON(http.StatusOK, resp)
+}
+
+// /team/delete post
+func (h *TeamHandler) PostTeamDelete(ctx echo.Context) error {
+
+	var body omodels.PostTeamDeleteJSONRequestBody
+
+	if err := ctx.Bind(&body); err != nil {
+		return mapErrorToHTTPResponse(ctx, errs.ErrBadRequest)
+	}
+
+	force := body.Force != nil && *body.Force
+
+	export, err := h.service.DeleteTeam(ctx.Request().Context(), body.TeamName, force)
+	if err != nil {
+		return mapErrorToHTTPResponse(ctx, err)
+	}
+
+	resp := omodels.TeamDeleteResponse{
+		TeamName: body.TeamName,
+		Export:   toOAPITeamExport(export),
+	}
+
+	return ctx.JSON(http.StatusOK, resp)
+}
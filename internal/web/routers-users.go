@@ -4,6 +4,7 @@ import (
 	"net/http"
 
 	"github.com/guarref/pr-service-assignment/internal/errs"
+	"github.com/guarref/pr-service-assignment/internal/models"
 	"github.com/guarref/pr-service-assignment/internal/service"
 	"github.com/guarref/pr-service-assignment/internal/web/omodels"
 	"github.com/labstack/echo/v4"
@@ -37,3 +38,82 @@ func (h *UserHandler) PostUsersSetIsActive(ctx echo.Context) error {
 		User omodels.User `json:"user"`
 	}{User: respUser})
 }
+
+// /users/erase post
+func (h *UserHandler) PostUsersErase(ctx echo.Context) error {
+
+	var body omodels.PostUsersEraseJSONRequestBody
+
+	if err := ctx.Bind(&body); err != nil {
+		return mapErrorToHTTPResponse(ctx, errs.ErrBadRequest)
+	}
+
+	erasure, err := h.service.EraseUser(ctx.Request().Context(), body.UserId)
+	if err != nil {
+		return mapErrorToHTTPResponse(ctx, err)
+	}
+
+	return ctx.JSON(http.StatusOK, struct {
+		Erasure omodels.UserErasure `json:"erasure"`
+	}{Erasure: omodels.UserErasure{
+		UserId:            erasure.Pseudonym,
+		RequestedBy:       erasure.RequestedBy,
+		ReassignedReviews: erasure.ReassignedReviews,
+		ErasedAt:          erasure.ErasedAt,
+	}})
+}
+
+// /users/get get
+func (h *UserHandler) GetUsersGet(ctx echo.Context, params omodels.GetUsersGetParams) error {
+
+	user, err := h.service.GetUserByID(ctx.Request().Context(), params.UserId)
+	if err != nil {
+		return mapErrorToHTTPResponse(ctx, err)
+	}
+
+	return ctx.JSON(http.StatusOK, struct {
+		User omodels.UserDetails `json:"user"`
+	}{User: toOAPIUserDetails(user)})
+}
+
+// /users/list get
+func (h *UserHandler) GetUsersList(ctx echo.Context, params omodels.GetUsersListParams) error {
+
+	filter := models.UserListFilter{
+		IsActive: params.IsActive,
+	}
+	if params.Team != nil {
+		filter.TeamName = *params.Team
+	}
+	if params.NamePrefix != nil {
+		filter.NamePrefix = *params.NamePrefix
+	}
+	if params.Sort != nil {
+		filter.Sort = models.UserSort(*params.Sort)
+	}
+	if params.Order != nil {
+		filter.Desc = *params.Order == omodels.Desc
+	}
+
+	var cursor string
+	if params.Cursor != nil {
+		cursor = *params.Cursor
+	}
+
+	users, next, err := h.service.ListUsers(ctx.Request().Context(), filter, params.Limit, cursor)
+	if err != nil {
+		return mapErrorToHTTPResponse(ctx, err)
+	}
+
+	resp := omodels.UserList{
+		Users: make([]omodels.UserDetails, 0, len(users)),
+	}
+	for _, u := range users {
+		resp.Users = append(resp.Users, toOAPIUserDetails(u))
+	}
+	if next != "" {
+		resp.NextCursor = &next
+	}
+
+	return ctx.JSON(http.StatusOK, resp)
+}
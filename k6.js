@@ -8,6 +8,8 @@ export const options = {
 
 const BASE_URL = "http://localhost:8080";
 const TEAM_NAME = "backend-test";
+// /team/add требует токен создателя команды: TOKEN=$(go run cmd/app/main.go token -user u1) k6 run k6.js
+const TOKEN = __ENV.TOKEN || "";
 
 function makePullRequestId(vuId) {
   return (
@@ -34,7 +36,10 @@ function createTeamIfNeeded() {
     `${BASE_URL}/team/add`,
     JSON.stringify(payload),
     {
-      headers: { "Content-Type": "application/json" },
+      headers: {
+        "Content-Type": "application/json",
+        Authorization: `Bearer ${TOKEN}`,
+      },
     },
   );
 
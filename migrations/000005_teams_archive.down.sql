@@ -0,0 +1,3 @@
+ALTER TABLE teams
+    DROP COLUMN IF EXISTS archived_at,
+    DROP COLUMN IF EXISTS is_active;
\ No newline at end of file
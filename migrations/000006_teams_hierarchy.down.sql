@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_teams_parent;
+
+ALTER TABLE teams
+    DROP CONSTRAINT IF EXISTS chk_teams_parent,
+    DROP CONSTRAINT IF EXISTS fk_teams_parent,
+    DROP COLUMN IF EXISTS parent_team;
\ No newline at end of file
@@ -0,0 +1,13 @@
+ALTER TABLE teams
+    ADD COLUMN IF NOT EXISTS parent_team VARCHAR(120) NULL;
+
+ALTER TABLE teams
+    ADD CONSTRAINT fk_teams_parent FOREIGN KEY (parent_team)
+        REFERENCES teams(team_name)
+        ON DELETE SET NULL
+        ON UPDATE CASCADE;
+
+ALTER TABLE teams
+    ADD CONSTRAINT chk_teams_parent CHECK (parent_team IS NULL OR parent_team <> team_name);
+
+CREATE INDEX idx_teams_parent ON teams(parent_team);
\ No newline at end of file
@@ -0,0 +1,21 @@
+DROP INDEX IF EXISTS idx_users_active;
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS team_name VARCHAR(120) NULL;
+
+UPDATE users u
+SET team_name = tm.team_name
+FROM team_memberships tm
+WHERE tm.user_id = u.user_id AND tm.is_primary;
+
+DELETE FROM users WHERE team_name IS NULL;
+
+ALTER TABLE users
+    ALTER COLUMN team_name SET NOT NULL,
+    ADD CONSTRAINT fk_users_team FOREIGN KEY (team_name)
+        REFERENCES teams(team_name)
+        ON DELETE CASCADE
+        ON UPDATE CASCADE;
+
+CREATE INDEX idx_users_team_active ON users(team_name, is_active);
+
+DROP TABLE IF EXISTS team_memberships;
\ No newline at end of file
@@ -0,0 +1,36 @@
+CREATE TABLE IF NOT EXISTS team_memberships (
+    user_id VARCHAR(120) NOT NULL,
+    team_name VARCHAR(120) NOT NULL,
+    is_primary BOOLEAN NOT NULL DEFAULT false,
+    is_active BOOLEAN NOT NULL DEFAULT true,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    PRIMARY KEY (user_id, team_name),
+
+    CONSTRAINT fk_memberships_user FOREIGN KEY (user_id)
+        REFERENCES users(user_id)
+        ON DELETE CASCADE
+        ON UPDATE CASCADE,
+
+    CONSTRAINT fk_memberships_team FOREIGN KEY (team_name)
+        REFERENCES teams(team_name)
+        ON DELETE CASCADE
+        ON UPDATE CASCADE
+);
+
+CREATE UNIQUE INDEX uq_memberships_primary ON team_memberships(user_id) WHERE is_primary;
+CREATE INDEX idx_memberships_team_active ON team_memberships(team_name, is_active);
+
+INSERT INTO team_memberships (user_id, team_name, is_primary, is_active, created_at, updated_at)
+SELECT user_id, team_name, true, true, created_at, updated_at
+FROM users
+ON CONFLICT (user_id, team_name) DO NOTHING;
+
+DROP INDEX IF EXISTS idx_users_team_active;
+
+ALTER TABLE users
+    DROP CONSTRAINT IF EXISTS fk_users_team,
+    DROP COLUMN IF EXISTS team_name;
+
+CREATE INDEX idx_users_active ON users(is_active);
\ No newline at end of file
@@ -0,0 +1,3 @@
+ALTER TABLE team_memberships
+    DROP CONSTRAINT IF EXISTS chk_memberships_role,
+    DROP COLUMN IF EXISTS role;
\ No newline at end of file
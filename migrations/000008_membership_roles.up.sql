@@ -0,0 +1,5 @@
+ALTER TABLE team_memberships
+    ADD COLUMN IF NOT EXISTS role VARCHAR(20) NOT NULL DEFAULT 'member';
+
+ALTER TABLE team_memberships
+    ADD CONSTRAINT chk_memberships_role CHECK (role IN ('member', 'lead', 'maintainer'));
\ No newline at end of file
@@ -0,0 +1,16 @@
+DROP TABLE IF EXISTS user_erasures;
+
+ALTER TABLE users
+    DROP COLUMN IF EXISTS erased_at;
+
+ALTER TABLE pr_reviewers
+    DROP CONSTRAINT IF EXISTS fk_pr_reviewers_user,
+    ADD CONSTRAINT fk_pr_reviewers_user FOREIGN KEY (user_id)
+        REFERENCES users(user_id)
+        ON DELETE CASCADE;
+
+ALTER TABLE pull_requests
+    DROP CONSTRAINT IF EXISTS fk_pr_author,
+    ADD CONSTRAINT fk_pr_author FOREIGN KEY (author_id)
+        REFERENCES users(user_id)
+        ON DELETE CASCADE;
\ No newline at end of file
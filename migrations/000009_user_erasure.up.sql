@@ -0,0 +1,25 @@
+ALTER TABLE pull_requests
+    DROP CONSTRAINT IF EXISTS fk_pr_author,
+    ADD CONSTRAINT fk_pr_author FOREIGN KEY (author_id)
+        REFERENCES users(user_id)
+        ON DELETE CASCADE
+        ON UPDATE CASCADE;
+
+ALTER TABLE pr_reviewers
+    DROP CONSTRAINT IF EXISTS fk_pr_reviewers_user,
+    ADD CONSTRAINT fk_pr_reviewers_user FOREIGN KEY (user_id)
+        REFERENCES users(user_id)
+        ON DELETE CASCADE
+        ON UPDATE CASCADE;
+
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS erased_at TIMESTAMPTZ NULL;
+
+CREATE TABLE IF NOT EXISTS user_erasures (
+    pseudonym VARCHAR(120) PRIMARY KEY,
+    requested_by VARCHAR(120) NOT NULL,
+    reassigned_reviews INT NOT NULL DEFAULT 0,
+    erased_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_user_erasures_requested_by ON user_erasures(requested_by);
\ No newline at end of file
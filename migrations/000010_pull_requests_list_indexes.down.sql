@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_pull_requests_author_id;
+
+DROP INDEX IF EXISTS idx_pull_requests_status_created_at;
\ No newline at end of file
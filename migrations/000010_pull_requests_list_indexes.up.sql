@@ -0,0 +1,3 @@
+CREATE INDEX IF NOT EXISTS idx_pull_requests_status_created_at ON pull_requests(status, created_at, pull_request_id);
+
+CREATE INDEX IF NOT EXISTS idx_pull_requests_author_id ON pull_requests(author_id);
\ No newline at end of file
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_pull_requests_name_trgm;
+
+DROP INDEX IF EXISTS idx_pull_requests_name_fts;
\ No newline at end of file
@@ -0,0 +1,5 @@
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+CREATE INDEX IF NOT EXISTS idx_pull_requests_name_fts ON pull_requests USING GIN (to_tsvector('simple', pull_request_name));
+
+CREATE INDEX IF NOT EXISTS idx_pull_requests_name_trgm ON pull_requests USING GIN (pull_request_name gin_trgm_ops);
\ No newline at end of file
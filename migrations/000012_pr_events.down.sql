@@ -0,0 +1 @@
+DROP TABLE IF EXISTS pr_events;
\ No newline at end of file
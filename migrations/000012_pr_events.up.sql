@@ -0,0 +1,31 @@
+CREATE TABLE IF NOT EXISTS pr_events (
+    event_id BIGSERIAL PRIMARY KEY,
+    pull_request_id VARCHAR(120) NOT NULL,
+    event_type VARCHAR(32) NOT NULL,
+    user_id VARCHAR(120) NULL,
+    reason VARCHAR(32) NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT chk_pr_events_type CHECK (event_type IN ('created', 'reviewer_assigned', 'reviewer_removed', 'merged')),
+    CONSTRAINT chk_pr_events_reason CHECK (reason IN ('creation', 'manual_reassign', 'deactivation', 'erasure', 'team_deletion')),
+
+    CONSTRAINT fk_pr_events_pr FOREIGN KEY (pull_request_id)
+        REFERENCES pull_requests(pull_request_id)
+        ON DELETE CASCADE
+);
+
+CREATE INDEX idx_pr_events_pr ON pr_events(pull_request_id, event_id);
+CREATE INDEX idx_pr_events_user ON pr_events(user_id);
+
+INSERT INTO pr_events (pull_request_id, event_type, user_id, reason, created_at)
+SELECT pull_request_id, 'created', author_id, NULL, created_at FROM pull_requests
+ORDER BY created_at, pull_request_id;
+
+INSERT INTO pr_events (pull_request_id, event_type, user_id, reason, created_at)
+SELECT pull_request_id, 'reviewer_assigned', user_id, NULL, assigned_at FROM pr_reviewers
+ORDER BY assigned_at, pull_request_id, user_id;
+
+INSERT INTO pr_events (pull_request_id, event_type, user_id, reason, created_at)
+SELECT pull_request_id, 'merged', NULL, NULL, merged_at FROM pull_requests
+WHERE merged_at IS NOT NULL
+ORDER BY merged_at, pull_request_id;
\ No newline at end of file
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_pr_reviewers_assigned_at;
+
+DROP INDEX IF EXISTS idx_pull_requests_merged_at;
+
+DROP INDEX IF EXISTS idx_pull_requests_created_at;
\ No newline at end of file
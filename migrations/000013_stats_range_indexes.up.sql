@@ -0,0 +1,5 @@
+CREATE INDEX IF NOT EXISTS idx_pull_requests_created_at ON pull_requests(created_at);
+
+CREATE INDEX IF NOT EXISTS idx_pull_requests_merged_at ON pull_requests(merged_at) WHERE merged_at IS NOT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_pr_reviewers_assigned_at ON pr_reviewers(assigned_at);
\ No newline at end of file
@@ -0,0 +1 @@
+DROP INDEX IF EXISTS idx_pr_events_assigned_at;
\ No newline at end of file
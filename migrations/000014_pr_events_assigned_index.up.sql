@@ -0,0 +1 @@
+CREATE INDEX IF NOT EXISTS idx_pr_events_assigned_at ON pr_events(created_at) WHERE event_type = 'reviewer_assigned';
\ No newline at end of file
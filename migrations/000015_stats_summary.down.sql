@@ -0,0 +1,3 @@
+DROP MATERIALIZED VIEW IF EXISTS stats_reviewer_counts;
+
+DROP MATERIALIZED VIEW IF EXISTS stats_summary;
\ No newline at end of file
@@ -0,0 +1,20 @@
+CREATE MATERIALIZED VIEW IF NOT EXISTS stats_summary AS
+SELECT
+    1 AS summary_id,
+    (SELECT COUNT(*) FROM teams) AS total_teams,
+    (SELECT COUNT(*) FROM users) AS total_users,
+    (SELECT COUNT(*) FROM users WHERE is_active = TRUE) AS active_users,
+    (SELECT COUNT(*) FROM pull_requests) AS total_pull_requests,
+    (SELECT COUNT(*) FROM pull_requests WHERE status = 'OPEN') AS open_pull_requests,
+    (SELECT COUNT(*) FROM pull_requests WHERE merged_at IS NOT NULL) AS merged_pull_requests,
+    now() AS refreshed_at;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_stats_summary_id ON stats_summary(summary_id);
+
+CREATE MATERIALIZED VIEW IF NOT EXISTS stats_reviewer_counts AS
+SELECT user_id, COUNT(DISTINCT pull_request_id) AS review_count
+FROM pr_reviewers
+GROUP BY user_id;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_stats_reviewer_counts_user ON stats_reviewer_counts(user_id);
+CREATE INDEX IF NOT EXISTS idx_stats_reviewer_counts_top ON stats_reviewer_counts(review_count DESC, user_id);
\ No newline at end of file
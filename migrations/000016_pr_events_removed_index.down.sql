@@ -0,0 +1 @@
+DROP INDEX IF EXISTS idx_pr_events_removed_reason;
\ No newline at end of file
@@ -0,0 +1 @@
+CREATE INDEX IF NOT EXISTS idx_pr_events_removed_reason ON pr_events(reason) WHERE event_type = 'reviewer_removed';
\ No newline at end of file
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_user_erasures_subject_hash;
+
+ALTER TABLE user_erasures
+    DROP COLUMN IF EXISTS subject_hash;
\ No newline at end of file
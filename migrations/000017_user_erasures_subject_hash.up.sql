@@ -0,0 +1,4 @@
+ALTER TABLE user_erasures
+    ADD COLUMN IF NOT EXISTS subject_hash VARCHAR(64) NULL;
+
+CREATE INDEX IF NOT EXISTS idx_user_erasures_subject_hash ON user_erasures(subject_hash);
\ No newline at end of file
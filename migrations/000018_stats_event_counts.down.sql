@@ -0,0 +1 @@
+DROP MATERIALIZED VIEW IF EXISTS stats_event_counts;
\ No newline at end of file
@@ -0,0 +1,6 @@
+CREATE MATERIALIZED VIEW IF NOT EXISTS stats_event_counts AS
+SELECT event_type, COALESCE(reason, '') AS reason, COUNT(*) AS event_count
+FROM pr_events
+GROUP BY event_type, COALESCE(reason, '');
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_stats_event_counts_key ON stats_event_counts(event_type, reason);
\ No newline at end of file
@@ -0,0 +1,88 @@
+package tests
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/guarref/pr-service-assignment/internal/auth"
+	"github.com/guarref/pr-service-assignment/internal/web"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuthMiddleware(t *testing.T) {
+	signer := auth.NewSigner("test-secret")
+
+	valid, err := signer.Issue("lead-1", time.Hour)
+	assert.NoError(t, err)
+	expired, err := signer.Issue("lead-1", -time.Minute)
+	assert.NoError(t, err)
+	foreign, err := auth.NewSigner("other-secret").Issue("lead-1", time.Hour)
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name           string
+		header         string
+		expectedStatus int
+		expectedCaller string
+	}{
+		{
+			name:           "valid token",
+			header:         "Bearer " + valid,
+			expectedStatus: http.StatusOK,
+			expectedCaller: "lead-1",
+		},
+		{
+			name:           "no header is anonymous",
+			header:         "",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "expired token",
+			header:         "Bearer " + expired,
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "token signed with another secret",
+			header:         "Bearer " + foreign,
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "not a bearer scheme",
+			header:         "Basic dXNlcjpwYXNz",
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "malformed token",
+			header:         "Bearer garbage",
+			expectedStatus: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.header != "" {
+				req.Header.Set(echo.HeaderAuthorization, tt.header)
+			}
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			var caller string
+			handler := web.AuthMiddleware(signer)(func(c echo.Context) error {
+				caller, _ = auth.CallerFromContext(c.Request().Context())
+				return c.NoContent(http.StatusOK)
+			})
+
+			err := handler(c)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			assert.Equal(t, tt.expectedCaller, caller)
+		})
+	}
+}
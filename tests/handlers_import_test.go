@@ -0,0 +1,270 @@
+package tests
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/guarref/pr-service-assignment/internal/auth"
+	"github.com/guarref/pr-service-assignment/internal/models"
+	"github.com/guarref/pr-service-assignment/internal/repository/mocks"
+	"github.com/guarref/pr-service-assignment/internal/service"
+	"github.com/guarref/pr-service-assignment/internal/web"
+	"github.com/guarref/pr-service-assignment/internal/web/omodels"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestImportHandler_PostImportTeams(t *testing.T) {
+	csvFormat := omodels.PostImportTeamsParamsFormatCsv
+
+	tests := []struct {
+		name             string
+		contentType      string
+		body             string
+		params           omodels.PostImportTeamsParams
+		caller           string
+		setupMocks       func(*mocks.MockImportRepository, *mocks.MockTeamRepository)
+		expectedStatus   int
+		validateResponse func(*testing.T, *httptest.ResponseRecorder)
+	}{
+		{
+			name:        "csv dry run",
+			caller:      "lead-1",
+			contentType: "text/csv",
+			body:        "team,user_id,username,active\nbackend,u1,Alice,true\nbackend,u2,Bob,false\nfrontend,u1,Alice,true\n",
+			params:      omodels.PostImportTeamsParams{DryRun: boolPtr(true)},
+			setupMocks: func(importRepo *mocks.MockImportRepository, teamRepo *mocks.MockTeamRepository) {
+				expected := []models.ImportRow{
+					{Line: 2, TeamName: "backend", UserID: "u1", UserName: "Alice", IsActive: true},
+					{Line: 3, TeamName: "backend", UserID: "u2", UserName: "Bob", IsActive: false},
+					{Line: 4, TeamName: "frontend", UserID: "u1", UserName: "Alice", IsActive: true},
+				}
+				diff := &models.ImportDiff{
+					DryRun:       true,
+					TeamsCreated: []string{"frontend"},
+					UsersCreated: []string{},
+					UsersUpdated: []models.ImportUserUpdate{
+						{UserID: "u2", OldUserName: "Bob", NewUserName: "Bob", OldIsActive: true, NewIsActive: false},
+					},
+					MembershipsAdded: []models.ImportMembership{{TeamName: "frontend", UserID: "u1"}},
+					Unchanged:        1,
+				}
+				importRepo.On("GetAffectedTeams", mock.Anything, expected).Return([]*models.ImportAffectedTeam{{TeamName: "backend", IsActive: true}}, nil)
+				teamRepo.On("GetMemberRole", mock.Anything, "backend", "lead-1").Return(models.TeamRoleLead, nil)
+				importRepo.On("ImportTeams", mock.Anything, expected, true).Return(diff, 0, nil)
+			},
+			expectedStatus: http.StatusOK,
+			validateResponse: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				var response omodels.ImportDiff
+				err := json.Unmarshal(rec.Body.Bytes(), &response)
+				assert.NoError(t, err)
+				assert.True(t, response.DryRun)
+				assert.Equal(t, []string{"frontend"}, response.TeamsCreated)
+				assert.Equal(t, []string{}, response.UsersCreated)
+				assert.Len(t, response.UsersUpdated, 1)
+				assert.False(t, response.UsersUpdated[0].NewIsActive)
+				assert.Equal(t, 1, response.Unchanged)
+			},
+		},
+		{
+			name:        "csv without active column, explicit format",
+			caller:      "lead-1",
+			contentType: "text/plain",
+			body:        "user_id,team,username\nu1,backend,Alice\n",
+			params:      omodels.PostImportTeamsParams{Format: &csvFormat},
+			setupMocks: func(importRepo *mocks.MockImportRepository, teamRepo *mocks.MockTeamRepository) {
+				expected := []models.ImportRow{
+					{Line: 2, TeamName: "backend", UserID: "u1", UserName: "Alice", IsActive: true},
+				}
+				importRepo.On("GetAffectedTeams", mock.Anything, expected).Return([]*models.ImportAffectedTeam{{TeamName: "backend", IsActive: true}}, nil)
+				teamRepo.On("GetMemberRole", mock.Anything, "backend", "lead-1").Return(models.TeamRoleMaintainer, nil)
+				importRepo.On("ImportTeams", mock.Anything, expected, false).Return(&models.ImportDiff{}, 0, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:        "yaml import",
+			caller:      "lead-1",
+			contentType: "application/yaml",
+			body:        "- team: backend\n  user_id: u1\n  username: Alice\n  active: true\n- team: backend\n  user_id: u2\n  username: Bob\n",
+			setupMocks: func(importRepo *mocks.MockImportRepository, teamRepo *mocks.MockTeamRepository) {
+				expected := []models.ImportRow{
+					{Line: 1, TeamName: "backend", UserID: "u1", UserName: "Alice", IsActive: true},
+					{Line: 5, TeamName: "backend", UserID: "u2", UserName: "Bob", IsActive: true},
+				}
+				importRepo.On("GetAffectedTeams", mock.Anything, expected).Return([]*models.ImportAffectedTeam{}, nil)
+				importRepo.On("ImportTeams", mock.Anything, expected, false).Return(&models.ImportDiff{UsersCreated: []string{"u1", "u2"}}, 0, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:        "csv validation reports every invalid line",
+			caller:      "lead-1",
+			contentType: "text/csv; charset=utf-8",
+			body: "team,user_id,username,active\n" +
+				"back end,u1,Alice,true\n" +
+				"backend,u2,,true\n" +
+				"backend,u3,Carl,maybe\n" +
+				"backend,u4,Dan\n" +
+				"backend,u5,Eve,true\n" +
+				"backend,u5,Eve,true\n" +
+				"frontend,u5,Eva,true\n",
+			setupMocks:     func(importRepo *mocks.MockImportRepository, teamRepo *mocks.MockTeamRepository) {},
+			expectedStatus: http.StatusBadRequest,
+			validateResponse: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				var response omodels.ErrorResponse
+				err := json.Unmarshal(rec.Body.Bytes(), &response)
+				assert.NoError(t, err)
+				assert.Equal(t, omodels.BADREQUEST, response.Error.Code)
+				if assert.NotNil(t, response.Error.Issues) {
+					var lines []int
+					for _, issue := range *response.Error.Issues {
+						lines = append(lines, issue.Line)
+					}
+					assert.Equal(t, []int{2, 3, 4, 5, 7, 8}, lines)
+					assert.Contains(t, (*response.Error.Issues)[0].Message, "invalid team name")
+					assert.Contains(t, (*response.Error.Issues)[4].Message, "duplicate row")
+					assert.Contains(t, (*response.Error.Issues)[5].Message, "conflicts")
+				}
+			},
+		},
+		{
+			name:           "csv with unknown and missing columns",
+			caller:         "lead-1",
+			contentType:    "text/csv",
+			body:           "team,user_id,email\nbackend,u1,a@example.com\n",
+			setupMocks:     func(importRepo *mocks.MockImportRepository, teamRepo *mocks.MockTeamRepository) {},
+			expectedStatus: http.StatusBadRequest,
+			validateResponse: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				var response omodels.ErrorResponse
+				err := json.Unmarshal(rec.Body.Bytes(), &response)
+				assert.NoError(t, err)
+				if assert.NotNil(t, response.Error.Issues) {
+					assert.Len(t, *response.Error.Issues, 2)
+					assert.Equal(t, 1, (*response.Error.Issues)[0].Line)
+				}
+			},
+		},
+		{
+			name:           "yaml with unknown field",
+			caller:         "lead-1",
+			contentType:    "application/x-yaml",
+			body:           "- team: backend\n  user_id: u1\n  username: Alice\n  email: a@example.com\n",
+			setupMocks:     func(importRepo *mocks.MockImportRepository, teamRepo *mocks.MockTeamRepository) {},
+			expectedStatus: http.StatusBadRequest,
+			validateResponse: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				var response omodels.ErrorResponse
+				err := json.Unmarshal(rec.Body.Bytes(), &response)
+				assert.NoError(t, err)
+				if assert.NotNil(t, response.Error.Issues) {
+					assert.Equal(t, 4, (*response.Error.Issues)[0].Line)
+				}
+			},
+		},
+		{
+			name:           "yaml that is not a list",
+			caller:         "lead-1",
+			contentType:    "application/yaml",
+			body:           "team: backend\n",
+			setupMocks:     func(importRepo *mocks.MockImportRepository, teamRepo *mocks.MockTeamRepository) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "empty file",
+			caller:         "lead-1",
+			contentType:    "text/csv",
+			body:           "",
+			setupMocks:     func(importRepo *mocks.MockImportRepository, teamRepo *mocks.MockTeamRepository) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "unsupported content type",
+			caller:         "lead-1",
+			contentType:    "application/json",
+			body:           `[{"team":"backend"}]`,
+			setupMocks:     func(importRepo *mocks.MockImportRepository, teamRepo *mocks.MockTeamRepository) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "no token",
+			contentType:    "text/csv",
+			body:           "team,user_id,username\nbackend,u1,Alice\n",
+			setupMocks:     func(importRepo *mocks.MockImportRepository, teamRepo *mocks.MockTeamRepository) {},
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:        "caller is not lead of every affected team",
+			caller:      "lead-1",
+			contentType: "text/csv",
+			body:        "team,user_id,username,active\nbackend,u1,Alice,false\n",
+			setupMocks: func(importRepo *mocks.MockImportRepository, teamRepo *mocks.MockTeamRepository) {
+				importRepo.On("GetAffectedTeams", mock.Anything, mock.Anything).Return([]*models.ImportAffectedTeam{
+					{TeamName: "backend", IsActive: true},
+					{TeamName: "platform", IsActive: true},
+				}, nil)
+				teamRepo.On("GetMemberRole", mock.Anything, "backend", "lead-1").Return(models.TeamRoleLead, nil)
+				teamRepo.On("GetMemberRole", mock.Anything, "platform", "lead-1").Return(models.TeamRoleMember, nil)
+			},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:        "rows targeting an archived team",
+			caller:      "lead-1",
+			contentType: "text/csv",
+			body:        "team,user_id,username\nbackend,u1,Alice\nlegacy,u2,Bob\nlegacy,u3,Carl\n",
+			setupMocks: func(importRepo *mocks.MockImportRepository, teamRepo *mocks.MockTeamRepository) {
+				importRepo.On("GetAffectedTeams", mock.Anything, mock.Anything).Return([]*models.ImportAffectedTeam{
+					{TeamName: "backend", IsActive: true},
+					{TeamName: "legacy", IsActive: false},
+				}, nil)
+			},
+			expectedStatus: http.StatusBadRequest,
+			validateResponse: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				var response omodels.ErrorResponse
+				err := json.Unmarshal(rec.Body.Bytes(), &response)
+				assert.NoError(t, err)
+				if assert.NotNil(t, response.Error.Issues) {
+					assert.Len(t, *response.Error.Issues, 2)
+					assert.Equal(t, 3, (*response.Error.Issues)[0].Line)
+					assert.Contains(t, (*response.Error.Issues)[0].Message, "archived")
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			importRepo := new(mocks.MockImportRepository)
+			teamRepo := new(mocks.MockTeamRepository)
+			importService := service.NewImportService(importRepo, teamRepo, nil)
+			handler := web.NewImportHandler(importService)
+
+			tt.setupMocks(importRepo, teamRepo)
+
+			req := httptest.NewRequest(http.MethodPost, "/import/teams", strings.NewReader(tt.body))
+			req.Header.Set(echo.HeaderContentType, tt.contentType)
+			if tt.caller != "" {
+				req = req.WithContext(auth.WithCaller(req.Context(), tt.caller))
+			}
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			err := handler.PostImportTeams(c, tt.params)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+
+			if tt.validateResponse != nil {
+				tt.validateResponse(t, rec)
+			}
+
+			importRepo.AssertExpectations(t)
+			teamRepo.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,115 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/guarref/pr-service-assignment/internal/auth"
+	"github.com/guarref/pr-service-assignment/internal/errs"
+	"github.com/guarref/pr-service-assignment/internal/metrics"
+	"github.com/guarref/pr-service-assignment/internal/models"
+	"github.com/guarref/pr-service-assignment/internal/repository/mocks"
+	"github.com/guarref/pr-service-assignment/internal/service"
+	"github.com/guarref/pr-service-assignment/internal/web"
+	"github.com/guarref/pr-service-assignment/internal/web/omodels"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newMetricsServer(statsRepo *mocks.MockStatsRepository) *echo.Echo {
+
+	m := metrics.New(statsRepo, nil)
+	handler := web.NewStatsHandler(service.NewStatsService(statsRepo))
+
+	e := echo.New()
+	e.Use(web.MetricsMiddleware(m))
+	e.GET("/stats/user", func(c echo.Context) error {
+		return handler.GetStatsUser(c, omodels.GetStatsUserParams{UserId: c.QueryParam("user_id")})
+	})
+	web.RegisterMetricsRoute(e, m)
+
+	return e
+}
+
+func TestMetrics(t *testing.T) {
+	t.Run("requests, errors and domain counters", func(t *testing.T) {
+		statsRepo := new(mocks.MockStatsRepository)
+		statsRepo.On("GetUserStats", mock.Anything, "u1").Return(&models.UserStats{UserID: "u1", ReviewedTeams: []*models.TeamReviewCount{}}, nil)
+		statsRepo.On("GetUserStats", mock.Anything, "ghost").Return(nil, errs.ErrUserNotFound)
+		statsRepo.On("GetDomainCounters", mock.Anything).Return(&models.DomainCounters{
+			OpenPullRequests: 4,
+			Assignments:      17,
+			Reassignments: map[models.PullRequestEventReason]int{
+				models.EventReasonManualReassign: 3,
+				models.EventReasonDeactivation:   2,
+			},
+		}, nil)
+
+		e := newMetricsServer(statsRepo)
+
+		for _, target := range []string{"/stats/user?user_id=u1", "/stats/user?user_id=u1", "/stats/user?user_id=ghost", "/missing"} {
+			e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, target, nil))
+		}
+
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		body := rec.Body.String()
+		assert.Contains(t, body, `pr_service_http_requests_total{code="200",method="GET",route="/stats/user"} 2`)
+		assert.Contains(t, body, `pr_service_http_requests_total{code="404",method="GET",route="/stats/user"} 1`)
+		assert.Contains(t, body, `pr_service_http_requests_total{code="404",method="GET",route="unmatched"} 1`)
+		assert.Contains(t, body, `pr_service_http_request_duration_seconds_count{method="GET",route="/stats/user"} 3`)
+		assert.Contains(t, body, `pr_service_errors_total{code="NOT_FOUND"} 1`)
+		assert.Contains(t, body, "pr_service_open_pull_requests 4")
+		assert.Contains(t, body, "pr_service_review_assignments_total 17")
+		assert.Contains(t, body, `pr_service_reassignments_total{reason="manual_reassign"} 3`)
+		assert.Contains(t, body, `pr_service_reassignments_total{reason="erasure"} 0`)
+		statsRepo.AssertExpectations(t)
+	})
+
+	t.Run("reviewers dropped without replacement are counted after commit", func(t *testing.T) {
+		statsRepo := new(mocks.MockStatsRepository)
+		statsRepo.On("GetDomainCounters", mock.Anything).Return(&models.DomainCounters{}, nil)
+		teamRepo := new(mocks.MockTeamRepository)
+		teamRepo.On("GetMemberRole", mock.Anything, mock.Anything, "lead-1").Return(models.TeamRoleLead, nil)
+		teamRepo.On("ArchiveTeam", mock.Anything, "team-1").Return(&models.TeamArchive{TeamName: "team-1"}, 2, nil)
+		teamRepo.On("ArchiveTeam", mock.Anything, "team-2").Return(nil, 0, errors.New("commit failed"))
+
+		m := metrics.New(statsRepo, nil)
+		teamService := service.NewTeamService(teamRepo, new(mocks.MockUserRepository), m)
+
+		ctx := auth.WithCaller(context.Background(), "lead-1")
+		_, err := teamService.ArchiveTeam(ctx, "team-1")
+		assert.NoError(t, err)
+		_, err = teamService.ArchiveTeam(ctx, "team-2")
+		assert.Error(t, err)
+
+		e := echo.New()
+		web.RegisterMetricsRoute(e, m)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+		assert.Contains(t, rec.Body.String(), `pr_service_errors_total{code="NO_CANDIDATE"} 2`)
+		teamRepo.AssertExpectations(t)
+	})
+
+	t.Run("domain counters unavailable", func(t *testing.T) {
+		statsRepo := new(mocks.MockStatsRepository)
+		statsRepo.On("GetDomainCounters", mock.Anything).Return(nil, errors.New("db down"))
+
+		e := newMetricsServer(statsRepo)
+		e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/missing", nil))
+
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Body.String(), "pr_service_http_requests_total")
+		assert.NotContains(t, rec.Body.String(), "pr_service_open_pull_requests")
+		statsRepo.AssertExpectations(t)
+	})
+}
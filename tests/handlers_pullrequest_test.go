@@ -2,12 +2,17 @@ package tests
 
 import (
 	"bytes"
+	"encoding/base64"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"slices"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/guarref/pr-service-assignment/internal/auth"
 	"github.com/guarref/pr-service-assignment/internal/errs"
 	"github.com/guarref/pr-service-assignment/internal/models"
 	"github.com/guarref/pr-service-assignment/internal/repository/mocks"
@@ -73,6 +78,39 @@ func TestPullRequestHandler_PostPullRequestCreate(t *testing.T) {
 				assert.Len(t, response.PR.AssignedReviewers, 2)
 			},
 		},
+		{
+			name: "least loaded reviewers are chosen",
+			requestBody: omodels.PostPullRequestCreateJSONRequestBody{
+				PullRequestId:   "pr-123",
+				PullRequestName: "Test PR",
+				AuthorId:        "user-1",
+			},
+			setupMocks: func(prRepo *mocks.MockPullRequestRepository, userRepo *mocks.MockUserRepository) {
+				author := &models.User{UserID: "user-1", UserName: "testuser", TeamName: "team-1", IsActive: true}
+				// нагрузка считается по всем командам пользователя, а не только по team-1
+				activeUsers := []*models.User{
+					{UserID: "user-2", TeamName: "team-1", IsActive: true, OpenReviewCount: 4},
+					{UserID: "user-3", TeamName: "team-1", IsActive: true, OpenReviewCount: 0},
+					{UserID: "user-4", TeamName: "team-1", IsActive: true, OpenReviewCount: 2},
+					{UserID: "user-5", TeamName: "team-1", IsActive: true, OpenReviewCount: 1},
+				}
+				createdPR := &models.PullRequest{
+					PullRequestID:     "pr-123",
+					PullRequestName:   "Test PR",
+					AuthorID:          "user-1",
+					Status:            models.PullRequestOpen,
+					AssignedReviewers: []string{"user-3", "user-5"},
+					CreatedAt:         time.Now(),
+				}
+
+				userRepo.On("GetUserByID", mock.Anything, "user-1").Return(author, nil)
+				userRepo.On("GetActiveUsersByTeam", mock.Anything, "team-1", "user-1").Return(activeUsers, nil)
+				prRepo.On("CreatePullRequest", mock.Anything, mock.MatchedBy(func(pr *models.PullRequest) bool {
+					return slices.Equal(pr.AssignedReviewers, []string{"user-3", "user-5"})
+				})).Return(createdPR, nil)
+			},
+			expectedStatus: http.StatusCreated,
+		},
 		{
 			name: "invalid request body",
 			requestBody: map[string]interface{}{
@@ -151,7 +189,7 @@ func TestPullRequestHandler_PostPullRequestCreate(t *testing.T) {
 			e := echo.New()
 			prRepo := new(mocks.MockPullRequestRepository)
 			userRepo := new(mocks.MockUserRepository)
-			prService := service.NewPullRequestService(prRepo, userRepo)
+			prService := service.NewPullRequestService(prRepo, userRepo, new(mocks.MockTeamRepository))
 			handler := web.NewPullRequestHandler(prService)
 
 			tt.setupMocks(prRepo, userRepo)
@@ -246,7 +284,7 @@ func TestPullRequestHandler_PostPullRequestMerge(t *testing.T) {
 			e := echo.New()
 			prRepo := new(mocks.MockPullRequestRepository)
 			userRepo := new(mocks.MockUserRepository)
-			prService := service.NewPullRequestService(prRepo, userRepo)
+			prService := service.NewPullRequestService(prRepo, userRepo, new(mocks.MockTeamRepository))
 			handler := web.NewPullRequestHandler(prService)
 
 			tt.setupMocks(prRepo)
@@ -275,20 +313,27 @@ func TestPullRequestHandler_PostPullRequestMerge(t *testing.T) {
 }
 
 func TestPullRequestHandler_PostPullRequestReassign(t *testing.T) {
+	openPR := []*models.PullRequestDetails{
+		{PullRequest: models.PullRequest{PullRequestID: "pr-123", AuthorID: "user-1", Status: models.PullRequestOpen}},
+	}
+	author := &models.User{UserID: "user-1", TeamName: "backend", IsActive: true}
+
 	tests := []struct {
 		name             string
+		caller           string
 		requestBody      interface{}
-		setupMocks       func(*mocks.MockPullRequestRepository)
+		setupMocks       func(*mocks.MockPullRequestRepository, *mocks.MockUserRepository, *mocks.MockTeamRepository)
 		expectedStatus   int
 		validateResponse func(*testing.T, *httptest.ResponseRecorder)
 	}{
 		{
-			name: "successful PR reassignment",
+			name:   "successful PR reassignment by team lead",
+			caller: "lead-1",
 			requestBody: omodels.PostPullRequestReassignJSONRequestBody{
 				PullRequestId: "pr-123",
 				OldUserId:     "user-2",
 			},
-			setupMocks: func(prRepo *mocks.MockPullRequestRepository) {
+			setupMocks: func(prRepo *mocks.MockPullRequestRepository, userRepo *mocks.MockUserRepository, teamRepo *mocks.MockTeamRepository) {
 				reassignedPR := &models.PullRequest{
 					PullRequestID:     "pr-123",
 					PullRequestName:   "Test PR",
@@ -297,6 +342,9 @@ func TestPullRequestHandler_PostPullRequestReassign(t *testing.T) {
 					AssignedReviewers: []string{"user-3", "user-4"},
 					CreatedAt:         time.Now(),
 				}
+				prRepo.On("GetPullRequestsByIDs", mock.Anything, []string{"pr-123"}).Return(openPR, nil)
+				userRepo.On("GetUserByID", mock.Anything, "user-1").Return(author, nil)
+				teamRepo.On("GetMemberRole", mock.Anything, "backend", "lead-1").Return(models.TeamRoleLead, nil)
 				prRepo.On("ReassignToPullRequest", mock.Anything, "pr-123", "user-2").Return(reassignedPR, "user-4", nil)
 			},
 			expectedStatus: http.StatusOK,
@@ -316,19 +364,45 @@ func TestPullRequestHandler_PostPullRequestReassign(t *testing.T) {
 			requestBody: map[string]interface{}{
 				"invalid": "data",
 			},
-			setupMocks: func(prRepo *mocks.MockPullRequestRepository) {
+			setupMocks: func(prRepo *mocks.MockPullRequestRepository, userRepo *mocks.MockUserRepository, teamRepo *mocks.MockTeamRepository) {
 				// No mocks needed
 			},
 			expectedStatus: http.StatusBadRequest,
 		},
 		{
-			name: "PR not found",
+			name: "without token",
+			requestBody: omodels.PostPullRequestReassignJSONRequestBody{
+				PullRequestId: "pr-123",
+				OldUserId:     "user-2",
+			},
+			setupMocks: func(prRepo *mocks.MockPullRequestRepository, userRepo *mocks.MockUserRepository, teamRepo *mocks.MockTeamRepository) {
+				// Service will return ErrUnauthorized before any lookup
+			},
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:   "caller is not lead of author team",
+			caller: "user-3",
+			requestBody: omodels.PostPullRequestReassignJSONRequestBody{
+				PullRequestId: "pr-123",
+				OldUserId:     "user-2",
+			},
+			setupMocks: func(prRepo *mocks.MockPullRequestRepository, userRepo *mocks.MockUserRepository, teamRepo *mocks.MockTeamRepository) {
+				prRepo.On("GetPullRequestsByIDs", mock.Anything, []string{"pr-123"}).Return(openPR, nil)
+				userRepo.On("GetUserByID", mock.Anything, "user-1").Return(author, nil)
+				teamRepo.On("GetMemberRole", mock.Anything, "backend", "user-3").Return(models.TeamRoleMember, nil)
+			},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:   "PR not found",
+			caller: "lead-1",
 			requestBody: omodels.PostPullRequestReassignJSONRequestBody{
 				PullRequestId: "pr-999",
 				OldUserId:     "user-2",
 			},
-			setupMocks: func(prRepo *mocks.MockPullRequestRepository) {
-				prRepo.On("ReassignToPullRequest", mock.Anything, "pr-999", "user-2").Return(nil, "", errs.ErrPullRequestNotFound)
+			setupMocks: func(prRepo *mocks.MockPullRequestRepository, userRepo *mocks.MockUserRepository, teamRepo *mocks.MockTeamRepository) {
+				prRepo.On("GetPullRequestsByIDs", mock.Anything, []string{"pr-999"}).Return([]*models.PullRequestDetails{}, nil)
 			},
 			expectedStatus: http.StatusNotFound,
 		},
@@ -340,15 +414,19 @@ func TestPullRequestHandler_PostPullRequestReassign(t *testing.T) {
 			e := echo.New()
 			prRepo := new(mocks.MockPullRequestRepository)
 			userRepo := new(mocks.MockUserRepository)
-			prService := service.NewPullRequestService(prRepo, userRepo)
+			teamRepo := new(mocks.MockTeamRepository)
+			prService := service.NewPullRequestService(prRepo, userRepo, teamRepo)
 			handler := web.NewPullRequestHandler(prService)
 
-			tt.setupMocks(prRepo)
+			tt.setupMocks(prRepo, userRepo, teamRepo)
 
 			// Create request
 			bodyBytes, _ := json.Marshal(tt.requestBody)
 			req := httptest.NewRequest(http.MethodPost, "/pullRequest/reassign", bytes.NewReader(bodyBytes))
 			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			if tt.caller != "" {
+				req = req.WithContext(auth.WithCaller(req.Context(), tt.caller))
+			}
 			rec := httptest.NewRecorder()
 			c := e.NewContext(req, rec)
 
@@ -364,14 +442,22 @@ func TestPullRequestHandler_PostPullRequestReassign(t *testing.T) {
 			}
 
 			prRepo.AssertExpectations(t)
+			userRepo.AssertExpectations(t)
+			teamRepo.AssertExpectations(t)
 		})
 	}
 }
 
 func TestPullRequestHandler_GetUsersGetReview(t *testing.T) {
+	statusOpen := omodels.GetUsersGetReviewParamsStatusOPEN
+	sortPriority := omodels.GetUsersGetReviewParamsSortPriority
+	sortBad := omodels.GetUsersGetReviewParamsSort("name")
+	assignedAt := time.Date(2025, 10, 24, 12, 34, 56, 0, time.UTC)
+
 	tests := []struct {
 		name             string
 		userID           string
+		params           omodels.GetUsersGetReviewParams
 		setupMocks       func(*mocks.MockPullRequestRepository)
 		expectedStatus   int
 		validateResponse func(*testing.T, *httptest.ResponseRecorder)
@@ -394,7 +480,10 @@ func TestPullRequestHandler_GetUsersGetReview(t *testing.T) {
 						Status:          models.PullRequestOpen,
 					},
 				}
-				prRepo.On("GetPullRequestByReviewerID", mock.Anything, "user-2").Return(prs, nil)
+				prRepo.On("GetPullRequestByReviewerID", mock.Anything, mock.MatchedBy(func(f models.ReviewQueueFilter) bool {
+					return f.ReviewerID == "user-2" && f.Status == "" && f.Sort == models.ReviewQueueSortNewest &&
+						f.Limit == service.DefaultPageLimit+1 && f.After == nil
+				})).Return(prs, nil)
 			},
 			expectedStatus: http.StatusOK,
 			validateResponse: func(t *testing.T, rec *httptest.ResponseRecorder) {
@@ -412,7 +501,7 @@ func TestPullRequestHandler_GetUsersGetReview(t *testing.T) {
 			name:   "empty list",
 			userID: "user-2",
 			setupMocks: func(prRepo *mocks.MockPullRequestRepository) {
-				prRepo.On("GetPullRequestByReviewerID", mock.Anything, "user-2").Return([]*models.PullRequestShort{}, nil)
+				prRepo.On("GetPullRequestByReviewerID", mock.Anything, mock.Anything).Return([]*models.PullRequestShort{}, nil)
 			},
 			expectedStatus: http.StatusOK,
 			validateResponse: func(t *testing.T, rec *httptest.ResponseRecorder) {
@@ -425,6 +514,47 @@ func TestPullRequestHandler_GetUsersGetReview(t *testing.T) {
 				assert.Empty(t, response.PullRequests)
 			},
 		},
+		{
+			name:   "open queue by priority with next page",
+			userID: "user-2",
+			params: omodels.GetUsersGetReviewParams{Status: &statusOpen, Sort: &sortPriority, Limit: intPtr(1)},
+			setupMocks: func(prRepo *mocks.MockPullRequestRepository) {
+				prs := []*models.PullRequestShort{
+					{PullRequestID: "pr-1", Status: models.PullRequestOpen, CreatedAt: assignedAt, AssignedAt: assignedAt, AgeSeconds: 3600},
+					{PullRequestID: "pr-2", Status: models.PullRequestOpen, CreatedAt: assignedAt, AssignedAt: assignedAt.Add(time.Hour)},
+				}
+				prRepo.On("GetPullRequestByReviewerID", mock.Anything, mock.MatchedBy(func(f models.ReviewQueueFilter) bool {
+					return f.Status == models.PullRequestOpen && f.Sort == models.ReviewQueueSortPriority && f.Limit == 2
+				})).Return(prs, nil)
+			},
+			expectedStatus: http.StatusOK,
+			validateResponse: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				var response struct {
+					PullRequests []omodels.PullRequestShort `json:"pull_requests"`
+					NextCursor   *string                    `json:"next_cursor"`
+				}
+				err := json.Unmarshal(rec.Body.Bytes(), &response)
+				assert.NoError(t, err)
+				assert.Len(t, response.PullRequests, 1)
+				assert.True(t, response.PullRequests[0].AssignedAt.Equal(assignedAt))
+				assert.Equal(t, int64(3600), response.PullRequests[0].AgeSeconds)
+				assert.NotNil(t, response.NextCursor)
+			},
+		},
+		{
+			name:           "unknown sort",
+			userID:         "user-2",
+			params:         omodels.GetUsersGetReviewParams{Sort: &sortBad},
+			setupMocks:     func(prRepo *mocks.MockPullRequestRepository) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "malformed cursor",
+			userID:         "user-2",
+			params:         omodels.GetUsersGetReviewParams{Cursor: strPtr("not-a-cursor!")},
+			setupMocks:     func(prRepo *mocks.MockPullRequestRepository) {},
+			expectedStatus: http.StatusBadRequest,
+		},
 		{
 			name:   "missing user ID",
 			userID: "",
@@ -441,7 +571,7 @@ func TestPullRequestHandler_GetUsersGetReview(t *testing.T) {
 			e := echo.New()
 			prRepo := new(mocks.MockPullRequestRepository)
 			userRepo := new(mocks.MockUserRepository)
-			prService := service.NewPullRequestService(prRepo, userRepo)
+			prService := service.NewPullRequestService(prRepo, userRepo, new(mocks.MockTeamRepository))
 			handler := web.NewPullRequestHandler(prService)
 
 			tt.setupMocks(prRepo)
@@ -454,9 +584,8 @@ func TestPullRequestHandler_GetUsersGetReview(t *testing.T) {
 			c.SetParamNames("userId")
 			c.SetParamValues(tt.userID)
 
-			params := omodels.GetUsersGetReviewParams{
-				UserId: tt.userID,
-			}
+			params := tt.params
+			params.UserId = tt.userID
 
 			// Execute
 			err := handler.GetUsersGetReview(c, params)
@@ -473,3 +602,788 @@ func TestPullRequestHandler_GetUsersGetReview(t *testing.T) {
 		})
 	}
 }
+
+func TestPullRequestHandler_GetPullRequestList(t *testing.T) {
+	statusMerged := omodels.GetPullRequestListParamsStatusMERGED
+	sortMerged := omodels.GetPullRequestListParamsSortMergedAt
+	sortBad := omodels.GetPullRequestListParamsSort("author_id")
+	orderDesc := omodels.Desc
+	createdFrom := time.Date(2025, 10, 1, 0, 0, 0, 0, time.UTC)
+	createdTo := time.Date(2025, 11, 1, 0, 0, 0, 0, time.UTC)
+	mergedAt := time.Date(2025, 10, 20, 9, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name             string
+		params           omodels.GetPullRequestListParams
+		setupMocks       func(*mocks.MockPullRequestRepository)
+		expectedStatus   int
+		validateResponse func(*testing.T, *httptest.ResponseRecorder)
+	}{
+		{
+			name: "filters are passed to repository",
+			params: omodels.GetPullRequestListParams{
+				Status:      &statusMerged,
+				Author:      strPtr("u1"),
+				Reviewer:    strPtr("u2"),
+				Team:        strPtr("backend"),
+				CreatedFrom: &createdFrom,
+				CreatedTo:   &createdTo,
+				Sort:        &sortMerged,
+				Order:       &orderDesc,
+				Limit:       intPtr(1),
+			},
+			setupMocks: func(prRepo *mocks.MockPullRequestRepository) {
+				prs := []*models.PullRequest{
+					{PullRequestID: "pr-2", PullRequestName: "Fix", AuthorID: "u1", Status: models.PullRequestMerged, AssignedReviewers: []string{"u2"}, CreatedAt: createdFrom, MergedAt: &mergedAt},
+					{PullRequestID: "pr-1", PullRequestName: "Add", AuthorID: "u1", Status: models.PullRequestMerged, AssignedReviewers: []string{"u2", "u3"}, CreatedAt: createdFrom, MergedAt: &mergedAt},
+				}
+				prRepo.On("ListPullRequests", mock.Anything, mock.MatchedBy(func(f models.PullRequestListFilter) bool {
+					return f.Status == models.PullRequestMerged && f.AuthorID == "u1" && f.ReviewerID == "u2" &&
+						f.TeamName == "backend" && f.CreatedFrom.Equal(createdFrom) && f.CreatedTo.Equal(createdTo) &&
+						f.MergedFrom == nil && f.Sort == models.PullRequestSortMergedAt && f.Desc &&
+						f.Limit == 2 && f.After == nil
+				})).Return(prs, nil)
+			},
+			expectedStatus: http.StatusOK,
+			validateResponse: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				var response omodels.PullRequestList
+				err := json.Unmarshal(rec.Body.Bytes(), &response)
+				assert.NoError(t, err)
+				assert.Len(t, response.PullRequests, 1)
+				assert.Equal(t, "pr-2", response.PullRequests[0].PullRequestId)
+				assert.Equal(t, []string{"u2"}, response.PullRequests[0].AssignedReviewers)
+				assert.NotNil(t, response.NextCursor)
+			},
+		},
+		{
+			name:   "default sort by created_at",
+			params: omodels.GetPullRequestListParams{},
+			setupMocks: func(prRepo *mocks.MockPullRequestRepository) {
+				prRepo.On("ListPullRequests", mock.Anything, mock.MatchedBy(func(f models.PullRequestListFilter) bool {
+					return f.Sort == models.PullRequestSortCreatedAt && !f.Desc && f.Limit == service.DefaultPageLimit+1
+				})).Return([]*models.PullRequest{}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			validateResponse: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				var response omodels.PullRequestList
+				err := json.Unmarshal(rec.Body.Bytes(), &response)
+				assert.NoError(t, err)
+				assert.Equal(t, []omodels.PullRequest{}, response.PullRequests)
+				assert.Nil(t, response.NextCursor)
+			},
+		},
+		{
+			name:           "unknown sort",
+			params:         omodels.GetPullRequestListParams{Sort: &sortBad},
+			setupMocks:     func(prRepo *mocks.MockPullRequestRepository) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "empty created range",
+			params:         omodels.GetPullRequestListParams{CreatedFrom: &createdTo, CreatedTo: &createdFrom},
+			setupMocks:     func(prRepo *mocks.MockPullRequestRepository) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "invalid team name",
+			params:         omodels.GetPullRequestListParams{Team: strPtr("back end")},
+			setupMocks:     func(prRepo *mocks.MockPullRequestRepository) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "malformed cursor",
+			params:         omodels.GetPullRequestListParams{Cursor: strPtr("not-a-cursor!")},
+			setupMocks:     func(prRepo *mocks.MockPullRequestRepository) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			prRepo := new(mocks.MockPullRequestRepository)
+			userRepo := new(mocks.MockUserRepository)
+			prService := service.NewPullRequestService(prRepo, userRepo, new(mocks.MockTeamRepository))
+			handler := web.NewPullRequestHandler(prService)
+
+			tt.setupMocks(prRepo)
+
+			req := httptest.NewRequest(http.MethodGet, "/pullRequest/list", nil)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			err := handler.GetPullRequestList(c, tt.params)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+
+			if tt.validateResponse != nil {
+				tt.validateResponse(t, rec)
+			}
+
+			prRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestPullRequestHandler_GetPullRequestList_CursorRoundTrip(t *testing.T) {
+	e := echo.New()
+	prRepo := new(mocks.MockPullRequestRepository)
+	handler := web.NewPullRequestHandler(service.NewPullRequestService(prRepo, new(mocks.MockUserRepository), new(mocks.MockTeamRepository)))
+
+	sortMerged := omodels.GetPullRequestListParamsSortMergedAt
+	createdAt := time.Date(2025, 10, 24, 12, 34, 56, 0, time.UTC)
+
+	// открытый PR без merged_at — курсор указывает на «бесконечность»
+	first := []*models.PullRequest{
+		{PullRequestID: "pr-1", Status: models.PullRequestOpen, CreatedAt: createdAt},
+		{PullRequestID: "pr-2", Status: models.PullRequestOpen, CreatedAt: createdAt},
+	}
+	prRepo.On("ListPullRequests", mock.Anything, mock.MatchedBy(func(f models.PullRequestListFilter) bool {
+		return f.After == nil
+	})).Return(first, nil).Once()
+	prRepo.On("ListPullRequests", mock.Anything, mock.MatchedBy(func(f models.PullRequestListFilter) bool {
+		return f.After != nil && f.After.Key == "infinity" && f.After.ID == "pr-1"
+	})).Return([]*models.PullRequest{first[1]}, nil).Once()
+
+	rec := httptest.NewRecorder()
+	c := e.NewContext(httptest.NewRequest(http.MethodGet, "/pullRequest/list", nil), rec)
+	assert.NoError(t, handler.GetPullRequestList(c, omodels.GetPullRequestListParams{Sort: &sortMerged, Limit: intPtr(1)}))
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var page omodels.PullRequestList
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &page))
+	assert.NotNil(t, page.NextCursor)
+
+	// курсор другой сортировки отклоняется
+	rec = httptest.NewRecorder()
+	c = e.NewContext(httptest.NewRequest(http.MethodGet, "/pullRequest/list", nil), rec)
+	assert.NoError(t, handler.GetPullRequestList(c, omodels.GetPullRequestListParams{Limit: intPtr(1), Cursor: page.NextCursor}))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec = httptest.NewRecorder()
+	c = e.NewContext(httptest.NewRequest(http.MethodGet, "/pullRequest/list", nil), rec)
+	assert.NoError(t, handler.GetPullRequestList(c, omodels.GetPullRequestListParams{Sort: &sortMerged, Limit: intPtr(1), Cursor: page.NextCursor}))
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	page = omodels.PullRequestList{}
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &page))
+	assert.Len(t, page.PullRequests, 1)
+	assert.Nil(t, page.NextCursor)
+
+	prRepo.AssertExpectations(t)
+}
+
+func TestPullRequestHandler_GetPullRequestGet(t *testing.T) {
+	createdAt := time.Date(2025, 10, 24, 12, 34, 56, 0, time.UTC)
+	mergedAt := createdAt.Add(2 * time.Hour)
+
+	tests := []struct {
+		name             string
+		prID             string
+		setupMocks       func(*mocks.MockPullRequestRepository)
+		expectedStatus   int
+		validateResponse func(*testing.T, *httptest.ResponseRecorder)
+	}{
+		{
+			name: "merged pr with completed reviews",
+			prID: "pr-1",
+			setupMocks: func(prRepo *mocks.MockPullRequestRepository) {
+				pr := &models.PullRequestDetails{
+					PullRequest: models.PullRequest{PullRequestID: "pr-1", PullRequestName: "Add", AuthorID: "u1", Status: models.PullRequestMerged, AssignedReviewers: []string{"u2"}, CreatedAt: createdAt, MergedAt: &mergedAt},
+					Reviewers: []models.PullRequestReviewer{
+						{PullRequestID: "pr-1", UserID: "u2", UserName: "Bob", TeamName: "backend", IsActive: true, AssignedAt: createdAt},
+					},
+				}
+				prRepo.On("GetPullRequestsByIDs", mock.Anything, []string{"pr-1"}).Return([]*models.PullRequestDetails{pr}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			validateResponse: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				var response struct {
+					PR omodels.PullRequestDetails `json:"pr"`
+				}
+				err := json.Unmarshal(rec.Body.Bytes(), &response)
+				assert.NoError(t, err)
+				assert.Equal(t, "pr-1", response.PR.PullRequestId)
+				assert.Len(t, response.PR.Reviewers, 1)
+				assert.Equal(t, "Bob", response.PR.Reviewers[0].Username)
+				assert.Equal(t, "backend", response.PR.Reviewers[0].TeamName)
+				assert.True(t, response.PR.Reviewers[0].AssignedAt.Equal(createdAt))
+				assert.Equal(t, omodels.COMPLETED, response.PR.Reviewers[0].ReviewState)
+			},
+		},
+		{
+			name: "pr not found",
+			prID: "pr-404",
+			setupMocks: func(prRepo *mocks.MockPullRequestRepository) {
+				prRepo.On("GetPullRequestsByIDs", mock.Anything, []string{"pr-404"}).Return([]*models.PullRequestDetails{}, nil)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "empty id",
+			prID:           "",
+			setupMocks:     func(prRepo *mocks.MockPullRequestRepository) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			prRepo := new(mocks.MockPullRequestRepository)
+			handler := web.NewPullRequestHandler(service.NewPullRequestService(prRepo, new(mocks.MockUserRepository), new(mocks.MockTeamRepository)))
+
+			tt.setupMocks(prRepo)
+
+			rec := httptest.NewRecorder()
+			c := e.NewContext(httptest.NewRequest(http.MethodGet, "/pullRequest/get", nil), rec)
+
+			err := handler.GetPullRequestGet(c, omodels.GetPullRequestGetParams{PullRequestId: tt.prID})
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+
+			if tt.validateResponse != nil {
+				tt.validateResponse(t, rec)
+			}
+
+			prRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestPullRequestHandler_PostPullRequestGetBatch(t *testing.T) {
+	tooMany := make([]string, service.MaxBatchPullRequests+1)
+	for i := range tooMany {
+		tooMany[i] = fmt.Sprintf("pr-%d", i)
+	}
+
+	tests := []struct {
+		name             string
+		requestBody      omodels.PostPullRequestGetBatchJSONRequestBody
+		setupMocks       func(*mocks.MockPullRequestRepository)
+		expectedStatus   int
+		validateResponse func(*testing.T, *httptest.ResponseRecorder)
+	}{
+		{
+			name:        "request order kept, duplicates dropped, missing reported",
+			requestBody: omodels.PostPullRequestGetBatchJSONRequestBody{PullRequestIds: []string{"pr-2", "pr-404", "pr-1", "pr-2"}},
+			setupMocks: func(prRepo *mocks.MockPullRequestRepository) {
+				found := []*models.PullRequestDetails{
+					{PullRequest: models.PullRequest{PullRequestID: "pr-1", Status: models.PullRequestOpen, AssignedReviewers: []string{"u2"}}, Reviewers: []models.PullRequestReviewer{{UserID: "u2"}}},
+					{PullRequest: models.PullRequest{PullRequestID: "pr-2", Status: models.PullRequestOpen, AssignedReviewers: []string{}}, Reviewers: []models.PullRequestReviewer{}},
+				}
+				prRepo.On("GetPullRequestsByIDs", mock.Anything, []string{"pr-2", "pr-404", "pr-1"}).Return(found, nil)
+			},
+			expectedStatus: http.StatusOK,
+			validateResponse: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				var response struct {
+					PullRequests []omodels.PullRequestDetails `json:"pull_requests"`
+					NotFound     []string                     `json:"not_found"`
+				}
+				err := json.Unmarshal(rec.Body.Bytes(), &response)
+				assert.NoError(t, err)
+				assert.Len(t, response.PullRequests, 2)
+				assert.Equal(t, "pr-2", response.PullRequests[0].PullRequestId)
+				assert.Equal(t, "pr-1", response.PullRequests[1].PullRequestId)
+				assert.Equal(t, omodels.PENDING, response.PullRequests[1].Reviewers[0].ReviewState)
+				assert.Equal(t, []string{"pr-404"}, response.NotFound)
+			},
+		},
+		{
+			name:           "empty list",
+			requestBody:    omodels.PostPullRequestGetBatchJSONRequestBody{PullRequestIds: []string{}},
+			setupMocks:     func(prRepo *mocks.MockPullRequestRepository) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "too many ids",
+			requestBody:    omodels.PostPullRequestGetBatchJSONRequestBody{PullRequestIds: tooMany},
+			setupMocks:     func(prRepo *mocks.MockPullRequestRepository) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			prRepo := new(mocks.MockPullRequestRepository)
+			handler := web.NewPullRequestHandler(service.NewPullRequestService(prRepo, new(mocks.MockUserRepository), new(mocks.MockTeamRepository)))
+
+			tt.setupMocks(prRepo)
+
+			body, _ := json.Marshal(tt.requestBody)
+			req := httptest.NewRequest(http.MethodPost, "/pullRequest/getBatch", bytes.NewReader(body))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			err := handler.PostPullRequestGetBatch(c)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+
+			if tt.validateResponse != nil {
+				tt.validateResponse(t, rec)
+			}
+
+			prRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestPullRequestHandler_GetUsersGetReview_CursorRoundTrip(t *testing.T) {
+	e := echo.New()
+	prRepo := new(mocks.MockPullRequestRepository)
+	handler := web.NewPullRequestHandler(service.NewPullRequestService(prRepo, new(mocks.MockUserRepository), new(mocks.MockTeamRepository)))
+
+	sortPriority := omodels.GetUsersGetReviewParamsSortPriority
+	assignedAt := time.Date(2025, 10, 24, 12, 34, 56, 0, time.UTC)
+
+	first := []*models.PullRequestShort{
+		{PullRequestID: "pr-1", Status: models.PullRequestMerged, AssignedAt: assignedAt},
+		{PullRequestID: "pr-2", Status: models.PullRequestMerged, AssignedAt: assignedAt},
+	}
+	prRepo.On("GetPullRequestByReviewerID", mock.Anything, mock.MatchedBy(func(f models.ReviewQueueFilter) bool {
+		return f.After == nil
+	})).Return(first, nil).Once()
+	prRepo.On("GetPullRequestByReviewerID", mock.Anything, mock.MatchedBy(func(f models.ReviewQueueFilter) bool {
+		// смерженные PR идут после открытых: ранг 1
+		return f.After != nil && f.After.StatusRank == 1 && f.After.Time.Equal(assignedAt) && f.After.ID == "pr-1"
+	})).Return([]*models.PullRequestShort{first[1]}, nil).Once()
+
+	var page struct {
+		PullRequests []omodels.PullRequestShort `json:"pull_requests"`
+		NextCursor   *string                    `json:"next_cursor"`
+	}
+
+	rec := httptest.NewRecorder()
+	c := e.NewContext(httptest.NewRequest(http.MethodGet, "/users/getReview", nil), rec)
+	assert.NoError(t, handler.GetUsersGetReview(c, omodels.GetUsersGetReviewParams{UserId: "user-2", Sort: &sortPriority, Limit: intPtr(1)}))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &page))
+	assert.NotNil(t, page.NextCursor)
+
+	// курсор другой сортировки отклоняется
+	rec = httptest.NewRecorder()
+	c = e.NewContext(httptest.NewRequest(http.MethodGet, "/users/getReview", nil), rec)
+	assert.NoError(t, handler.GetUsersGetReview(c, omodels.GetUsersGetReviewParams{UserId: "user-2", Limit: intPtr(1), Cursor: page.NextCursor}))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec = httptest.NewRecorder()
+	c = e.NewContext(httptest.NewRequest(http.MethodGet, "/users/getReview", nil), rec)
+	assert.NoError(t, handler.GetUsersGetReview(c, omodels.GetUsersGetReviewParams{UserId: "user-2", Sort: &sortPriority, Limit: intPtr(1), Cursor: page.NextCursor}))
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	page.NextCursor = nil
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &page))
+	assert.Len(t, page.PullRequests, 1)
+	assert.Nil(t, page.NextCursor)
+
+	prRepo.AssertExpectations(t)
+}
+
+func TestPullRequestHandler_GetUsersGetReview_InvalidCursorKey(t *testing.T) {
+	sortPriority := omodels.GetUsersGetReviewParamsSortPriority
+	sortOldest := omodels.GetUsersGetReviewParamsSortOldest
+
+	// курсор с корректной подписью сортировки, но испорченным ключом
+	forge := func(sort, key string) *string {
+		raw, _ := json.Marshal(map[string]string{"s": sort, "k": key, "id": "pr-1"})
+		cursor := base64.RawURLEncoding.EncodeToString(raw)
+		return &cursor
+	}
+
+	tests := []struct {
+		name   string
+		params omodels.GetUsersGetReviewParams
+	}{
+		{name: "priority without separator", params: omodels.GetUsersGetReviewParams{Sort: &sortPriority, Cursor: forge("review:priority", "2025-10-24T12:34:56Z")}},
+		{name: "priority with unknown rank", params: omodels.GetUsersGetReviewParams{Sort: &sortPriority, Cursor: forge("review:priority", "x|2025-10-24T12:34:56Z")}},
+		{name: "priority with bad timestamp", params: omodels.GetUsersGetReviewParams{Sort: &sortPriority, Cursor: forge("review:priority", "0|yesterday")}},
+		{name: "oldest with bad timestamp", params: omodels.GetUsersGetReviewParams{Sort: &sortOldest, Cursor: forge("review:oldest", "0|2025-10-24T12:34:56Z")}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			prRepo := new(mocks.MockPullRequestRepository)
+			handler := web.NewPullRequestHandler(service.NewPullRequestService(prRepo, new(mocks.MockUserRepository), new(mocks.MockTeamRepository)))
+
+			tt.params.UserId = "user-2"
+			rec := httptest.NewRecorder()
+			c := e.NewContext(httptest.NewRequest(http.MethodGet, "/users/getReview", nil), rec)
+
+			assert.NoError(t, handler.GetUsersGetReview(c, tt.params))
+			assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+			var response omodels.ErrorResponse
+			assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+			assert.Equal(t, omodels.BADREQUEST, response.Error.Code)
+			assert.Equal(t, errs.ErrInvalidCursor.Message, response.Error.Message)
+			prRepo.AssertNotCalled(t, "GetPullRequestByReviewerID", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestPullRequestHandler_GetUsersGetAuthored(t *testing.T) {
+	statusOpen := omodels.GetUsersGetAuthoredParamsStatusOPEN
+	statusBad := omodels.GetUsersGetAuthoredParamsStatus("CLOSED")
+	createdAt := time.Date(2025, 10, 24, 12, 34, 56, 0, time.UTC)
+
+	tests := []struct {
+		name             string
+		params           omodels.GetUsersGetAuthoredParams
+		setupMocks       func(*mocks.MockPullRequestRepository)
+		expectedStatus   int
+		validateResponse func(*testing.T, *httptest.ResponseRecorder)
+	}{
+		{
+			name:   "open PRs with reviewers and next page",
+			params: omodels.GetUsersGetAuthoredParams{UserId: "u1", Status: &statusOpen, Limit: intPtr(1)},
+			setupMocks: func(prRepo *mocks.MockPullRequestRepository) {
+				prs := []*models.PullRequestDetails{
+					{
+						PullRequest: models.PullRequest{PullRequestID: "pr-2", AuthorID: "u1", Status: models.PullRequestOpen, AssignedReviewers: []string{"u2"}, CreatedAt: createdAt},
+						Reviewers:   []models.PullRequestReviewer{{UserID: "u2", UserName: "Bob", AssignedAt: createdAt, HeldSeconds: 7200}},
+					},
+					{PullRequest: models.PullRequest{PullRequestID: "pr-1", AuthorID: "u1", Status: models.PullRequestOpen, CreatedAt: createdAt}},
+				}
+				prRepo.On("GetPullRequestsByAuthorID", mock.Anything, mock.MatchedBy(func(f models.AuthoredPullRequestsFilter) bool {
+					return f.AuthorID == "u1" && f.Status == models.PullRequestOpen && f.Limit == 2 && f.After == nil
+				})).Return(prs, nil)
+			},
+			expectedStatus: http.StatusOK,
+			validateResponse: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				var response struct {
+					UserID       string                       `json:"user_id"`
+					PullRequests []omodels.PullRequestDetails `json:"pull_requests"`
+					NextCursor   *string                      `json:"next_cursor"`
+				}
+				err := json.Unmarshal(rec.Body.Bytes(), &response)
+				assert.NoError(t, err)
+				assert.Equal(t, "u1", response.UserID)
+				assert.Len(t, response.PullRequests, 1)
+				assert.Equal(t, "pr-2", response.PullRequests[0].PullRequestId)
+				assert.Equal(t, int64(7200), response.PullRequests[0].Reviewers[0].HeldSeconds)
+				assert.Equal(t, omodels.PENDING, response.PullRequests[0].Reviewers[0].ReviewState)
+				assert.NotNil(t, response.NextCursor)
+			},
+		},
+		{
+			name:   "no PRs",
+			params: omodels.GetUsersGetAuthoredParams{UserId: "u1"},
+			setupMocks: func(prRepo *mocks.MockPullRequestRepository) {
+				prRepo.On("GetPullRequestsByAuthorID", mock.Anything, mock.MatchedBy(func(f models.AuthoredPullRequestsFilter) bool {
+					return f.Status == "" && f.Limit == service.DefaultPageLimit+1
+				})).Return([]*models.PullRequestDetails{}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			validateResponse: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.JSONEq(t, `{"user_id":"u1","pull_requests":[]}`, rec.Body.String())
+			},
+		},
+		{
+			name:           "unknown status",
+			params:         omodels.GetUsersGetAuthoredParams{UserId: "u1", Status: &statusBad},
+			setupMocks:     func(prRepo *mocks.MockPullRequestRepository) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "cursor from another endpoint",
+			params:         omodels.GetUsersGetAuthoredParams{UserId: "u1", Cursor: strPtr("eyJzIjoicmV2aWV3Om5ld2VzdCIsImsiOiJ4IiwiaWQiOiJwci0xIn0")},
+			setupMocks:     func(prRepo *mocks.MockPullRequestRepository) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "missing user ID",
+			params:         omodels.GetUsersGetAuthoredParams{},
+			setupMocks:     func(prRepo *mocks.MockPullRequestRepository) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			prRepo := new(mocks.MockPullRequestRepository)
+			handler := web.NewPullRequestHandler(service.NewPullRequestService(prRepo, new(mocks.MockUserRepository), new(mocks.MockTeamRepository)))
+
+			tt.setupMocks(prRepo)
+
+			rec := httptest.NewRecorder()
+			c := e.NewContext(httptest.NewRequest(http.MethodGet, "/users/getAuthored", nil), rec)
+
+			err := handler.GetUsersGetAuthored(c, tt.params)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+
+			if tt.validateResponse != nil {
+				tt.validateResponse(t, rec)
+			}
+
+			prRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestPullRequestHandler_GetPullRequestSearch(t *testing.T) {
+	tests := []struct {
+		name             string
+		params           omodels.GetPullRequestSearchParams
+		setupMocks       func(*mocks.MockPullRequestRepository)
+		expectedStatus   int
+		validateResponse func(*testing.T, *httptest.ResponseRecorder)
+	}{
+		{
+			name:   "query is trimmed and ranked results kept in order",
+			params: omodels.GetPullRequestSearchParams{Q: "  PAY-1234 ", Limit: intPtr(10)},
+			setupMocks: func(prRepo *mocks.MockPullRequestRepository) {
+				prs := []*models.PullRequest{
+					{PullRequestID: "pr-2", PullRequestName: "PAY-1234 fix refunds", AssignedReviewers: []string{"u2"}},
+					{PullRequestID: "pr-1", PullRequestName: "Follow-up for PAY-1234", AssignedReviewers: []string{}},
+				}
+				prRepo.On("SearchPullRequests", mock.Anything, "PAY-1234", 10).Return(prs, nil)
+			},
+			expectedStatus: http.StatusOK,
+			validateResponse: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				var response struct {
+					PullRequests []omodels.PullRequest `json:"pull_requests"`
+				}
+				err := json.Unmarshal(rec.Body.Bytes(), &response)
+				assert.NoError(t, err)
+				assert.Len(t, response.PullRequests, 2)
+				assert.Equal(t, "pr-2", response.PullRequests[0].PullRequestId)
+			},
+		},
+		{
+			name:   "default limit",
+			params: omodels.GetPullRequestSearchParams{Q: "search"},
+			setupMocks: func(prRepo *mocks.MockPullRequestRepository) {
+				prRepo.On("SearchPullRequests", mock.Anything, "search", service.DefaultPageLimit).Return([]*models.PullRequest{}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "blank query",
+			params:         omodels.GetPullRequestSearchParams{Q: "   "},
+			setupMocks:     func(prRepo *mocks.MockPullRequestRepository) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "query too long",
+			params:         omodels.GetPullRequestSearchParams{Q: strings.Repeat("a", service.MaxSearchQueryLength+1)},
+			setupMocks:     func(prRepo *mocks.MockPullRequestRepository) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			prRepo := new(mocks.MockPullRequestRepository)
+			handler := web.NewPullRequestHandler(service.NewPullRequestService(prRepo, new(mocks.MockUserRepository), new(mocks.MockTeamRepository)))
+
+			tt.setupMocks(prRepo)
+
+			rec := httptest.NewRecorder()
+			c := e.NewContext(httptest.NewRequest(http.MethodGet, "/pullRequest/search", nil), rec)
+
+			err := handler.GetPullRequestSearch(c, tt.params)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+
+			if tt.validateResponse != nil {
+				tt.validateResponse(t, rec)
+			}
+
+			prRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestPullRequestHandler_GetPullRequestTimeline(t *testing.T) {
+	createdAt := time.Date(2025, 10, 24, 12, 34, 56, 0, time.UTC)
+	deactivation := models.EventReasonDeactivation
+
+	tests := []struct {
+		name             string
+		prID             string
+		setupMocks       func(*mocks.MockPullRequestRepository)
+		expectedStatus   int
+		validateResponse func(*testing.T, *httptest.ResponseRecorder)
+	}{
+		{
+			name: "events in recorded order",
+			prID: "pr-1",
+			setupMocks: func(prRepo *mocks.MockPullRequestRepository) {
+				events := []*models.PullRequestEvent{
+					{EventID: 1, PullRequestID: "pr-1", Type: models.PullRequestEventCreated, UserID: strPtr("u1"), CreatedAt: createdAt},
+					{EventID: 4, PullRequestID: "pr-1", Type: models.PullRequestEventReviewerRemoved, UserID: strPtr("u2"), Reason: &deactivation, CreatedAt: createdAt.Add(time.Hour)},
+					{EventID: 7, PullRequestID: "pr-1", Type: models.PullRequestEventMerged, CreatedAt: createdAt.Add(2 * time.Hour)},
+				}
+				prRepo.On("GetPullRequestTimeline", mock.Anything, "pr-1").Return(events, nil)
+			},
+			expectedStatus: http.StatusOK,
+			validateResponse: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				var response struct {
+					PullRequestID string                     `json:"pull_request_id"`
+					Events        []omodels.PullRequestEvent `json:"events"`
+				}
+				err := json.Unmarshal(rec.Body.Bytes(), &response)
+				assert.NoError(t, err)
+				assert.Equal(t, "pr-1", response.PullRequestID)
+				assert.Len(t, response.Events, 3)
+				assert.Equal(t, omodels.Created, response.Events[0].Type)
+				assert.Nil(t, response.Events[0].Reason)
+				assert.Equal(t, omodels.ReviewerRemoved, response.Events[1].Type)
+				assert.Equal(t, omodels.Deactivation, *response.Events[1].Reason)
+				assert.Equal(t, "u2", *response.Events[1].UserId)
+				assert.Equal(t, omodels.Merged, response.Events[2].Type)
+				assert.Nil(t, response.Events[2].UserId)
+			},
+		},
+		{
+			name: "pr not found",
+			prID: "pr-404",
+			setupMocks: func(prRepo *mocks.MockPullRequestRepository) {
+				prRepo.On("GetPullRequestTimeline", mock.Anything, "pr-404").Return(nil, errs.ErrPullRequestNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "empty id",
+			prID:           "",
+			setupMocks:     func(prRepo *mocks.MockPullRequestRepository) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			prRepo := new(mocks.MockPullRequestRepository)
+			handler := web.NewPullRequestHandler(service.NewPullRequestService(prRepo, new(mocks.MockUserRepository), new(mocks.MockTeamRepository)))
+
+			tt.setupMocks(prRepo)
+
+			rec := httptest.NewRecorder()
+			c := e.NewContext(httptest.NewRequest(http.MethodGet, "/pullRequest/timeline", nil), rec)
+
+			err := handler.GetPullRequestTimeline(c, omodels.GetPullRequestTimelineParams{PullRequestId: tt.prID})
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+
+			if tt.validateResponse != nil {
+				tt.validateResponse(t, rec)
+			}
+
+			prRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestPullRequestHandler_GetPullRequestList_Export(t *testing.T) {
+	createdAt := time.Date(2025, 10, 24, 12, 34, 56, 0, time.UTC)
+	mergedAt := time.Date(2025, 10, 25, 9, 0, 0, 0, time.UTC)
+
+	t.Run("csv streams all pages", func(t *testing.T) {
+		e := echo.New()
+		prRepo := new(mocks.MockPullRequestRepository)
+		handler := web.NewPullRequestHandler(service.NewPullRequestService(prRepo, new(mocks.MockUserRepository), new(mocks.MockTeamRepository)))
+
+		first := make([]*models.PullRequest, 0, service.MaxPageLimit+1)
+		for i := 0; i <= service.MaxPageLimit; i++ {
+			first = append(first, &models.PullRequest{
+				PullRequestID: fmt.Sprintf("pr-%03d", i), PullRequestName: "Add search", AuthorID: "u1",
+				Status: models.PullRequestOpen, AssignedReviewers: []string{"u2", "u3"}, CreatedAt: createdAt,
+			})
+		}
+		first[0].PullRequestName = "=HYPERLINK(\"http://evil\")"
+
+		last := []*models.PullRequest{
+			{PullRequestID: "pr-999", PullRequestName: "Fix", AuthorID: "u1", Status: models.PullRequestMerged, CreatedAt: createdAt, MergedAt: &mergedAt},
+		}
+
+		prRepo.On("ListPullRequests", mock.Anything, mock.MatchedBy(func(f models.PullRequestListFilter) bool {
+			return f.After == nil && f.Limit == service.MaxPageLimit+1
+		})).Return(first, nil).Once()
+		prRepo.On("ListPullRequests", mock.Anything, mock.MatchedBy(func(f models.PullRequestListFilter) bool {
+			return f.After != nil && f.After.ID == fmt.Sprintf("pr-%03d", service.MaxPageLimit-1)
+		})).Return(last, nil).Once()
+
+		req := httptest.NewRequest(http.MethodGet, "/pullRequest/list?format=csv", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		csvFormat := omodels.ExportFormatCsv
+		err := handler.GetPullRequestList(c, omodels.GetPullRequestListParams{Format: &csvFormat})
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.True(t, strings.HasPrefix(rec.Header().Get(echo.HeaderContentType), "text/csv"))
+
+		lines := strings.Split(strings.TrimSpace(rec.Body.String()), "\n")
+		assert.Len(t, lines, service.MaxPageLimit+2)
+		assert.Equal(t, "pull_request_id,pull_request_name,author_id,status,assigned_reviewers,created_at,merged_at", lines[0])
+		assert.Equal(t, `pr-000,"'=HYPERLINK(""http://evil"")",u1,OPEN,u2;u3,2025-10-24T12:34:56Z,`, lines[1])
+		assert.Equal(t, "pr-999,Fix,u1,MERGED,,2025-10-24T12:34:56Z,2025-10-25T09:00:00Z", lines[len(lines)-1])
+
+		prRepo.AssertExpectations(t)
+	})
+
+	t.Run("ndjson by accept header", func(t *testing.T) {
+		e := echo.New()
+		prRepo := new(mocks.MockPullRequestRepository)
+		handler := web.NewPullRequestHandler(service.NewPullRequestService(prRepo, new(mocks.MockUserRepository), new(mocks.MockTeamRepository)))
+
+		prs := []*models.PullRequest{
+			{PullRequestID: "pr-1", PullRequestName: "Add", AuthorID: "u1", Status: models.PullRequestOpen, AssignedReviewers: []string{"u2"}, CreatedAt: createdAt},
+			{PullRequestID: "pr-2", PullRequestName: "Fix", AuthorID: "u1", Status: models.PullRequestMerged, CreatedAt: createdAt, MergedAt: &mergedAt},
+		}
+		prRepo.On("ListPullRequests", mock.Anything, mock.Anything).Return(prs, nil).Once()
+
+		req := httptest.NewRequest(http.MethodGet, "/pullRequest/list", nil)
+		req.Header.Set(echo.HeaderAccept, "application/x-ndjson, application/json;q=0.5")
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		err := handler.GetPullRequestList(c, omodels.GetPullRequestListParams{})
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "application/x-ndjson", rec.Header().Get(echo.HeaderContentType))
+
+		lines := strings.Split(strings.TrimSpace(rec.Body.String()), "\n")
+		assert.Len(t, lines, 2)
+
+		var pr omodels.PullRequest
+		assert.NoError(t, json.Unmarshal([]byte(lines[1]), &pr))
+		assert.Equal(t, "pr-2", pr.PullRequestId)
+		assert.Equal(t, omodels.PullRequestStatusMERGED, pr.Status)
+
+		prRepo.AssertExpectations(t)
+	})
+
+	t.Run("invalid filter is reported before streaming", func(t *testing.T) {
+		e := echo.New()
+		prRepo := new(mocks.MockPullRequestRepository)
+		handler := web.NewPullRequestHandler(service.NewPullRequestService(prRepo, new(mocks.MockUserRepository), new(mocks.MockTeamRepository)))
+
+		req := httptest.NewRequest(http.MethodGet, "/pullRequest/list?format=csv", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		csvFormat := omodels.ExportFormatCsv
+		sortBad := omodels.GetPullRequestListParamsSort("author_id")
+		err := handler.GetPullRequestList(c, omodels.GetPullRequestListParams{Format: &csvFormat, Sort: &sortBad})
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Contains(t, rec.Header().Get(echo.HeaderContentType), echo.MIMEApplicationJSON)
+	})
+}
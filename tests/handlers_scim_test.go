@@ -0,0 +1,538 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/guarref/pr-service-assignment/internal/auth"
+	"github.com/guarref/pr-service-assignment/internal/errs"
+	"github.com/guarref/pr-service-assignment/internal/models"
+	"github.com/guarref/pr-service-assignment/internal/repository/mocks"
+	"github.com/guarref/pr-service-assignment/internal/service"
+	"github.com/guarref/pr-service-assignment/internal/web"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const scimTestToken = "scim-test-token"
+
+// локальный SCIM-клиент: ходит в поднятый httptest-сервер так же, как IdP
+type scimClient struct {
+	t      *testing.T
+	server *httptest.Server
+	token  string
+}
+
+type scimResponse struct {
+	status int
+	header http.Header
+	body   map[string]any
+}
+
+func newScimFixture(t *testing.T) (*scimClient, *mocks.MockScimRepository, *mocks.MockTeamRepository) {
+	scimRepo := new(mocks.MockScimRepository)
+	teamRepo := new(mocks.MockTeamRepository)
+
+	e := echo.New()
+	e.Use(web.AuthMiddleware(auth.NewSigner("test-secret")))
+	web.RegisterScimRoutes(e, service.NewScimService(scimRepo, teamRepo, nil), scimTestToken)
+
+	server := httptest.NewServer(e)
+	t.Cleanup(server.Close)
+
+	return &scimClient{t: t, server: server, token: scimTestToken}, scimRepo, teamRepo
+}
+
+func (c *scimClient) do(method, path string, body any) scimResponse {
+	var reader io.Reader
+	if body != nil {
+		raw, err := json.Marshal(body)
+		assert.NoError(c.t, err)
+		reader = bytes.NewReader(raw)
+	}
+
+	req, err := http.NewRequest(method, c.server.URL+web.ScimBasePath+path, reader)
+	assert.NoError(c.t, err)
+	req.Header.Set(echo.HeaderContentType, "application/scim+json")
+	if c.token != "" {
+		req.Header.Set(echo.HeaderAuthorization, "Bearer "+c.token)
+	}
+
+	res, err := http.DefaultClient.Do(req)
+	assert.NoError(c.t, err)
+	defer res.Body.Close()
+
+	resp := scimResponse{status: res.StatusCode, header: res.Header}
+	raw, err := io.ReadAll(res.Body)
+	assert.NoError(c.t, err)
+	if len(raw) > 0 {
+		assert.NoError(c.t, json.Unmarshal(raw, &resp.body))
+	}
+
+	return resp
+}
+
+func scimPatch(ops ...map[string]any) map[string]any {
+	return map[string]any{
+		"schemas":    []string{"urn:ietf:params:scim:api:messages:2.0:PatchOp"},
+		"Operations": ops,
+	}
+}
+
+func scimTestUser(id, name string, active bool) *models.User {
+	created := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	return &models.User{UserID: id, UserName: name, IsActive: active, Teams: []string{"backend"}, CreatedAt: created, UpdatedAt: created}
+}
+
+func scimTestGroup(name string, memberIDs ...string) *models.Team {
+	members := make([]models.TeamMember, 0, len(memberIDs))
+	for _, id := range memberIDs {
+		members = append(members, models.TeamMember{UserID: id, UserName: id, IsActive: true})
+	}
+	return &models.Team{TeamName: name, IsActive: true, Members: members}
+}
+
+func TestScimHandler_Auth(t *testing.T) {
+	client, scimRepo, _ := newScimFixture(t)
+
+	scimRepo.On("GetUser", mock.Anything, "u1").Return(scimTestUser("u1", "Alice", true), nil)
+
+	client.token = ""
+	resp := client.do(http.MethodGet, "/Users/u1", nil)
+	assert.Equal(t, http.StatusUnauthorized, resp.status)
+	assert.Equal(t, []any{"urn:ietf:params:scim:api:messages:2.0:Error"}, resp.body["schemas"])
+	assert.Equal(t, "401", resp.body["status"])
+
+	client.token = "wrong"
+	resp = client.do(http.MethodGet, "/Users/u1", nil)
+	assert.Equal(t, http.StatusUnauthorized, resp.status)
+
+	// статический токен SCIM не должен разбираться общим AuthMiddleware
+	client.token = scimTestToken
+	resp = client.do(http.MethodGet, "/Users/u1", nil)
+	assert.Equal(t, http.StatusOK, resp.status)
+	assert.Contains(t, resp.header.Get(echo.HeaderContentType), "application/scim+json")
+
+	resp = client.do(http.MethodGet, "/ServiceProviderConfig", nil)
+	assert.Equal(t, http.StatusOK, resp.status)
+	assert.Equal(t, map[string]any{"supported": true}, resp.body["patch"])
+}
+
+func TestScimHandler_Users(t *testing.T) {
+	tests := []struct {
+		name             string
+		method           string
+		path             string
+		body             any
+		setupMocks       func(*mocks.MockScimRepository)
+		expectedStatus   int
+		validateResponse func(*testing.T, scimResponse)
+	}{
+		{
+			name:   "list filtered by userName",
+			method: http.MethodGet,
+			path:   `/Users?filter=userName%20eq%20%22u1%22`,
+			setupMocks: func(scimRepo *mocks.MockScimRepository) {
+				filter := models.ScimUserFilter{UserID: "u1", Limit: service.DefaultPageLimit}
+				scimRepo.On("ListUsers", mock.Anything, filter).Return([]*models.User{scimTestUser("u1", "Alice", true)}, 1, nil)
+			},
+			expectedStatus: http.StatusOK,
+			validateResponse: func(t *testing.T, resp scimResponse) {
+				assert.Equal(t, float64(1), resp.body["totalResults"])
+				assert.Equal(t, float64(1), resp.body["startIndex"])
+				resources := resp.body["Resources"].([]any)
+				assert.Len(t, resources, 1)
+				user := resources[0].(map[string]any)
+				assert.Equal(t, "u1", user["id"])
+				assert.Equal(t, "u1", user["userName"])
+				assert.Equal(t, "Alice", user["displayName"])
+				assert.Equal(t, true, user["active"])
+				assert.Equal(t, "backend", user["groups"].([]any)[0].(map[string]any)["value"])
+			},
+		},
+		{
+			name:   "list with paging and active filter",
+			method: http.MethodGet,
+			path:   `/Users?filter=active%20eq%20false&startIndex=3&count=2`,
+			setupMocks: func(scimRepo *mocks.MockScimRepository) {
+				inactive := false
+				filter := models.ScimUserFilter{IsActive: &inactive, Offset: 2, Limit: 2}
+				scimRepo.On("ListUsers", mock.Anything, filter).Return([]*models.User{scimTestUser("u3", "Carol", false)}, 3, nil)
+			},
+			expectedStatus: http.StatusOK,
+			validateResponse: func(t *testing.T, resp scimResponse) {
+				assert.Equal(t, float64(3), resp.body["totalResults"])
+				assert.Equal(t, float64(3), resp.body["startIndex"])
+				assert.Equal(t, float64(1), resp.body["itemsPerPage"])
+			},
+		},
+		{
+			name:           "contradicting filter returns empty list",
+			method:         http.MethodGet,
+			path:           `/Users?filter=userName%20eq%20%22u1%22%20and%20id%20eq%20%22u2%22`,
+			setupMocks:     func(scimRepo *mocks.MockScimRepository) {},
+			expectedStatus: http.StatusOK,
+			validateResponse: func(t *testing.T, resp scimResponse) {
+				assert.Equal(t, float64(0), resp.body["totalResults"])
+				assert.Equal(t, []any{}, resp.body["Resources"])
+			},
+		},
+		{
+			name:           "unsupported filter operator",
+			method:         http.MethodGet,
+			path:           `/Users?filter=userName%20co%20%22u%22`,
+			setupMocks:     func(scimRepo *mocks.MockScimRepository) {},
+			expectedStatus: http.StatusBadRequest,
+			validateResponse: func(t *testing.T, resp scimResponse) {
+				assert.Equal(t, "invalidFilter", resp.body["scimType"])
+			},
+		},
+		{
+			name:   "create user",
+			method: http.MethodPost,
+			path:   "/Users",
+			body: map[string]any{
+				"schemas":  []string{"urn:ietf:params:scim:schemas:core:2.0:User"},
+				"userName": "u5",
+				"name":     map[string]any{"givenName": "Eve", "familyName": "Stone"},
+				"emails":   []any{map[string]any{"value": "eve@example.com", "primary": true}},
+			},
+			setupMocks: func(scimRepo *mocks.MockScimRepository) {
+				scimRepo.On("CreateUser", mock.Anything, &models.User{UserID: "u5", UserName: "Eve Stone", IsActive: true}).Return(nil)
+				scimRepo.On("GetUser", mock.Anything, "u5").Return(scimTestUser("u5", "Eve Stone", true), nil)
+			},
+			expectedStatus: http.StatusCreated,
+			validateResponse: func(t *testing.T, resp scimResponse) {
+				assert.Equal(t, "u5", resp.body["id"])
+				assert.Contains(t, resp.header.Get(echo.HeaderLocation), "/scim/v2/Users/u5")
+			},
+		},
+		{
+			name:   "create existing user",
+			method: http.MethodPost,
+			path:   "/Users",
+			body:   map[string]any{"userName": "u1", "displayName": "Alice"},
+			setupMocks: func(scimRepo *mocks.MockScimRepository) {
+				scimRepo.On("CreateUser", mock.Anything, mock.Anything).Return(errs.ErrUserExists)
+			},
+			expectedStatus: http.StatusConflict,
+			validateResponse: func(t *testing.T, resp scimResponse) {
+				assert.Equal(t, "uniqueness", resp.body["scimType"])
+			},
+		},
+		{
+			name:   "get missing user",
+			method: http.MethodGet,
+			path:   "/Users/ghost",
+			setupMocks: func(scimRepo *mocks.MockScimRepository) {
+				scimRepo.On("GetUser", mock.Anything, "ghost").Return(nil, errs.ErrUserNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+			validateResponse: func(t *testing.T, resp scimResponse) {
+				assert.Equal(t, "404", resp.body["status"])
+			},
+		},
+		{
+			name:   "patch active with path deprovisions",
+			method: http.MethodPatch,
+			path:   "/Users/u1",
+			body:   scimPatch(map[string]any{"op": "replace", "path": "active", "value": false}),
+			setupMocks: func(scimRepo *mocks.MockScimRepository) {
+				scimRepo.On("GetUser", mock.Anything, "u1").Return(scimTestUser("u1", "Alice", true), nil).Once()
+				scimRepo.On("UpdateUser", mock.Anything, "u1", "Alice", false).Return(0, nil)
+				scimRepo.On("GetUser", mock.Anything, "u1").Return(scimTestUser("u1", "Alice", false), nil).Once()
+			},
+			expectedStatus: http.StatusOK,
+			validateResponse: func(t *testing.T, resp scimResponse) {
+				assert.Equal(t, false, resp.body["active"])
+			},
+		},
+		{
+			name:   "patch without path, string boolean",
+			method: http.MethodPatch,
+			path:   "/Users/u1",
+			body: scimPatch(map[string]any{
+				"op":    "Replace",
+				"value": map[string]any{"active": "False", "displayName": "Alice B", "emails[type eq \"work\"].value": "a@example.com"},
+			}),
+			setupMocks: func(scimRepo *mocks.MockScimRepository) {
+				scimRepo.On("GetUser", mock.Anything, "u1").Return(scimTestUser("u1", "Alice", true), nil)
+				scimRepo.On("UpdateUser", mock.Anything, "u1", "Alice B", false).Return(0, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:   "patch userName is immutable",
+			method: http.MethodPatch,
+			path:   "/Users/u1",
+			body:   scimPatch(map[string]any{"op": "replace", "path": "userName", "value": "u100"}),
+			setupMocks: func(scimRepo *mocks.MockScimRepository) {
+				scimRepo.On("GetUser", mock.Anything, "u1").Return(scimTestUser("u1", "Alice", true), nil)
+			},
+			expectedStatus: http.StatusBadRequest,
+			validateResponse: func(t *testing.T, resp scimResponse) {
+				assert.Equal(t, "mutability", resp.body["scimType"])
+			},
+		},
+		{
+			name:   "patch remove is not supported",
+			method: http.MethodPatch,
+			path:   "/Users/u1",
+			body:   scimPatch(map[string]any{"op": "remove", "path": "displayName"}),
+			setupMocks: func(scimRepo *mocks.MockScimRepository) {
+				scimRepo.On("GetUser", mock.Anything, "u1").Return(scimTestUser("u1", "Alice", true), nil)
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "put replaces user",
+			method: http.MethodPut,
+			path:   "/Users/u1",
+			body:   map[string]any{"userName": "u1", "displayName": "Alice Cooper", "active": true},
+			setupMocks: func(scimRepo *mocks.MockScimRepository) {
+				scimRepo.On("UpdateUser", mock.Anything, "u1", "Alice Cooper", true).Return(0, nil)
+				scimRepo.On("GetUser", mock.Anything, "u1").Return(scimTestUser("u1", "Alice Cooper", true), nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "put with another userName",
+			method:         http.MethodPut,
+			path:           "/Users/u1",
+			body:           map[string]any{"userName": "u2"},
+			setupMocks:     func(scimRepo *mocks.MockScimRepository) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "delete deprovisions user",
+			method: http.MethodDelete,
+			path:   "/Users/u1",
+			setupMocks: func(scimRepo *mocks.MockScimRepository) {
+				scimRepo.On("GetUser", mock.Anything, "u1").Return(scimTestUser("u1", "Alice", true), nil)
+				scimRepo.On("UpdateUser", mock.Anything, "u1", "Alice", false).Return(0, nil)
+			},
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name:           "invalid json",
+			method:         http.MethodPost,
+			path:           "/Users",
+			body:           "not an object",
+			setupMocks:     func(scimRepo *mocks.MockScimRepository) {},
+			expectedStatus: http.StatusBadRequest,
+			validateResponse: func(t *testing.T, resp scimResponse) {
+				assert.Equal(t, "invalidSyntax", resp.body["scimType"])
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, scimRepo, _ := newScimFixture(t)
+
+			tt.setupMocks(scimRepo)
+
+			resp := client.do(tt.method, tt.path, tt.body)
+
+			assert.Equal(t, tt.expectedStatus, resp.status)
+
+			if tt.validateResponse != nil {
+				tt.validateResponse(t, resp)
+			}
+
+			scimRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestScimHandler_Groups(t *testing.T) {
+	tests := []struct {
+		name             string
+		method           string
+		path             string
+		body             any
+		setupMocks       func(*mocks.MockScimRepository, *mocks.MockTeamRepository)
+		expectedStatus   int
+		validateResponse func(*testing.T, scimResponse)
+	}{
+		{
+			name:   "list filtered by displayName without members",
+			method: http.MethodGet,
+			path:   `/Groups?filter=displayName%20eq%20%22backend%22&excludedAttributes=members`,
+			setupMocks: func(scimRepo *mocks.MockScimRepository, teamRepo *mocks.MockTeamRepository) {
+				filter := models.ScimGroupFilter{TeamName: "backend", Limit: service.DefaultPageLimit}
+				scimRepo.On("ListGroups", mock.Anything, filter).Return([]*models.Team{scimTestGroup("backend", "u1")}, 1, nil)
+			},
+			expectedStatus: http.StatusOK,
+			validateResponse: func(t *testing.T, resp scimResponse) {
+				group := resp.body["Resources"].([]any)[0].(map[string]any)
+				assert.Equal(t, "backend", group["id"])
+				assert.Equal(t, "backend", group["displayName"])
+				assert.NotContains(t, group, "members")
+			},
+		},
+		{
+			name:   "list groups of member",
+			method: http.MethodGet,
+			path:   `/Groups?filter=members.value%20eq%20%22u1%22`,
+			setupMocks: func(scimRepo *mocks.MockScimRepository, teamRepo *mocks.MockTeamRepository) {
+				filter := models.ScimGroupFilter{MemberID: "u1", Limit: service.DefaultPageLimit}
+				scimRepo.On("ListGroups", mock.Anything, filter).Return([]*models.Team{scimTestGroup("backend", "u1")}, 1, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:   "create group with members",
+			method: http.MethodPost,
+			path:   "/Groups",
+			body: map[string]any{
+				"displayName": "platform",
+				"members":     []any{map[string]any{"value": "u1"}, map[string]any{"value": "u2"}, map[string]any{"value": "u1"}},
+			},
+			setupMocks: func(scimRepo *mocks.MockScimRepository, teamRepo *mocks.MockTeamRepository) {
+				scimRepo.On("CreateGroup", mock.Anything, "platform", []string{"u1", "u2"}).Return(nil)
+				scimRepo.On("GetGroup", mock.Anything, "platform").Return(scimTestGroup("platform", "u1", "u2"), nil)
+			},
+			expectedStatus: http.StatusCreated,
+			validateResponse: func(t *testing.T, resp scimResponse) {
+				assert.Len(t, resp.body["members"], 2)
+				assert.Contains(t, resp.header.Get(echo.HeaderLocation), "/scim/v2/Groups/platform")
+			},
+		},
+		{
+			name:           "create group with invalid name",
+			method:         http.MethodPost,
+			path:           "/Groups",
+			body:           map[string]any{"displayName": "Platform Team"},
+			setupMocks:     func(scimRepo *mocks.MockScimRepository, teamRepo *mocks.MockTeamRepository) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "patch add and remove members",
+			method: http.MethodPatch,
+			path:   "/Groups/backend",
+			body: scimPatch(
+				map[string]any{"op": "add", "path": "members", "value": []any{map[string]any{"value": "u3"}, map[string]any{"value": "u4"}}},
+				map[string]any{"op": "remove", "path": `members[value eq "u2"]`},
+				map[string]any{"op": "Remove", "path": "members", "value": []any{map[string]any{"value": "u4"}}},
+			),
+			setupMocks: func(scimRepo *mocks.MockScimRepository, teamRepo *mocks.MockTeamRepository) {
+				update := models.ScimMembersUpdate{Add: []string{"u3"}, Remove: []string{"u2", "u4"}}
+				scimRepo.On("UpdateGroupMembers", mock.Anything, "backend", update).Return(0, nil)
+				scimRepo.On("GetGroup", mock.Anything, "backend").Return(scimTestGroup("backend", "u1", "u3"), nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:   "patch replace members",
+			method: http.MethodPatch,
+			path:   "/Groups/backend",
+			body: scimPatch(map[string]any{
+				"op":    "replace",
+				"value": map[string]any{"displayName": "backend", "members": []any{map[string]any{"value": "u7"}}},
+			}),
+			setupMocks: func(scimRepo *mocks.MockScimRepository, teamRepo *mocks.MockTeamRepository) {
+				update := models.ScimMembersUpdate{Add: []string{"u7"}, Replace: true}
+				scimRepo.On("UpdateGroupMembers", mock.Anything, "backend", update).Return(0, nil)
+				scimRepo.On("GetGroup", mock.Anything, "backend").Return(scimTestGroup("backend", "u7"), nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "patch rename is immutable",
+			method:         http.MethodPatch,
+			path:           "/Groups/backend",
+			body:           scimPatch(map[string]any{"op": "replace", "path": "displayName", "value": "core"}),
+			setupMocks:     func(scimRepo *mocks.MockScimRepository, teamRepo *mocks.MockTeamRepository) {},
+			expectedStatus: http.StatusBadRequest,
+			validateResponse: func(t *testing.T, resp scimResponse) {
+				assert.Equal(t, "mutability", resp.body["scimType"])
+			},
+		},
+		{
+			name:   "patch unknown member",
+			method: http.MethodPatch,
+			path:   "/Groups/backend",
+			body:   scimPatch(map[string]any{"op": "add", "path": "members", "value": []any{map[string]any{"value": "ghost"}}}),
+			setupMocks: func(scimRepo *mocks.MockScimRepository, teamRepo *mocks.MockTeamRepository) {
+				scimRepo.On("UpdateGroupMembers", mock.Anything, "backend", mock.Anything).Return(0, errs.ErrUserNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:   "patch archived group",
+			method: http.MethodPatch,
+			path:   "/Groups/backend",
+			body:   scimPatch(map[string]any{"op": "add", "path": "members", "value": []any{map[string]any{"value": "u1"}}}),
+			setupMocks: func(scimRepo *mocks.MockScimRepository, teamRepo *mocks.MockTeamRepository) {
+				scimRepo.On("UpdateGroupMembers", mock.Anything, "backend", mock.Anything).Return(0, errs.ErrTeamArchived)
+			},
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name:   "put archived group",
+			method: http.MethodPut,
+			path:   "/Groups/backend",
+			body:   map[string]any{"displayName": "backend", "members": []any{}},
+			setupMocks: func(scimRepo *mocks.MockScimRepository, teamRepo *mocks.MockTeamRepository) {
+				scimRepo.On("UpdateGroupMembers", mock.Anything, "backend", mock.Anything).Return(0, errs.ErrTeamArchived)
+			},
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name:   "put replaces members",
+			method: http.MethodPut,
+			path:   "/Groups/backend",
+			body:   map[string]any{"displayName": "backend", "members": []any{}},
+			setupMocks: func(scimRepo *mocks.MockScimRepository, teamRepo *mocks.MockTeamRepository) {
+				update := models.ScimMembersUpdate{Add: []string{}, Replace: true}
+				scimRepo.On("UpdateGroupMembers", mock.Anything, "backend", update).Return(0, nil)
+				scimRepo.On("GetGroup", mock.Anything, "backend").Return(scimTestGroup("backend"), nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:   "delete archives team",
+			method: http.MethodDelete,
+			path:   "/Groups/backend",
+			setupMocks: func(scimRepo *mocks.MockScimRepository, teamRepo *mocks.MockTeamRepository) {
+				teamRepo.On("ArchiveTeam", mock.Anything, "backend").Return(&models.TeamArchive{TeamName: "backend"}, 0, nil)
+			},
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name:   "delete missing group",
+			method: http.MethodDelete,
+			path:   "/Groups/ghost",
+			setupMocks: func(scimRepo *mocks.MockScimRepository, teamRepo *mocks.MockTeamRepository) {
+				teamRepo.On("ArchiveTeam", mock.Anything, "ghost").Return(nil, 0, errs.ErrTeamNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, scimRepo, teamRepo := newScimFixture(t)
+
+			tt.setupMocks(scimRepo, teamRepo)
+
+			resp := client.do(tt.method, tt.path, tt.body)
+
+			assert.Equal(t, tt.expectedStatus, resp.status)
+
+			if tt.validateResponse != nil {
+				tt.validateResponse(t, resp)
+			}
+
+			scimRepo.AssertExpectations(t)
+			teamRepo.AssertExpectations(t)
+		})
+	}
+}
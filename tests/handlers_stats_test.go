@@ -1,11 +1,15 @@
 package tests
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/guarref/pr-service-assignment/internal/errs"
 	"github.com/guarref/pr-service-assignment/internal/models"
 	"github.com/guarref/pr-service-assignment/internal/repository/mocks"
 	"github.com/guarref/pr-service-assignment/internal/service"
@@ -17,9 +21,15 @@ import (
 )
 
 func TestStatsHandler_GetStats(t *testing.T) {
+	sprintStart := time.Date(2025, 10, 6, 0, 0, 0, 0, time.UTC)
+	sprintEnd := sprintStart.AddDate(0, 0, 14)
+
 	tests := []struct {
 		name           string
 		top            *int
+		team           string
+		from           *time.Time
+		to             *time.Time
 		setupMocks     func(*mocks.MockStatsRepository)
 		expectedStatus int
 		validateResponse func(*testing.T, *httptest.ResponseRecorder)
@@ -40,7 +50,7 @@ func TestStatsHandler_GetStats(t *testing.T) {
 						{UserID: "user-3", UserName: "reviewer3", ReviewCount: 10},
 					},
 				}
-				statsRepo.On("GetStats", mock.Anything, 5).Return(stats, nil)
+				statsRepo.On("GetStats", mock.Anything, 5, "", models.StatsWindow{}).Return(stats, nil)
 			},
 			expectedStatus: http.StatusOK,
 			validateResponse: func(t *testing.T, rec *httptest.ResponseRecorder) {
@@ -67,7 +77,7 @@ func TestStatsHandler_GetStats(t *testing.T) {
 					OpenPullRequests:  20,
 					TopReviewers:      []*models.TopReviewer{},
 				}
-				statsRepo.On("GetStats", mock.Anything, 0).Return(stats, nil)
+				statsRepo.On("GetStats", mock.Anything, 0, "", models.StatsWindow{}).Return(stats, nil)
 			},
 			expectedStatus: http.StatusOK,
 			validateResponse: func(t *testing.T, rec *httptest.ResponseRecorder) {
@@ -91,7 +101,7 @@ func TestStatsHandler_GetStats(t *testing.T) {
 						{UserID: "user-1", UserName: "reviewer1", ReviewCount: 15},
 					},
 				}
-				statsRepo.On("GetStats", mock.Anything, 10).Return(stats, nil) // Should be capped at 10
+				statsRepo.On("GetStats", mock.Anything, 10, "", models.StatsWindow{}).Return(stats, nil) // Should be capped at 10
 			},
 			expectedStatus: http.StatusOK,
 			validateResponse: func(t *testing.T, rec *httptest.ResponseRecorder) {
@@ -101,6 +111,47 @@ func TestStatsHandler_GetStats(t *testing.T) {
 				assert.Equal(t, 10, stats.TotalTeams)
 			},
 		},
+		{
+			name: "stats for team subtree",
+			top:  intPtr(3),
+			team: "platform",
+			setupMocks: func(statsRepo *mocks.MockStatsRepository) {
+				stats := &models.Stats{
+					TotalTeams:        3,
+					TotalUsers:        12,
+					ActiveUsers:       10,
+					TotalPullRequests: 40,
+					OpenPullRequests:  5,
+					TopReviewers:      []*models.TopReviewer{},
+				}
+				statsRepo.On("GetStats", mock.Anything, 3, "platform", models.StatsWindow{}).Return(stats, nil)
+			},
+			expectedStatus: http.StatusOK,
+			validateResponse: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				var stats omodels.Stats
+				err := json.Unmarshal(rec.Body.Bytes(), &stats)
+				assert.NoError(t, err)
+				assert.Equal(t, 3, stats.TotalTeams)
+				assert.Equal(t, 12, stats.TotalUsers)
+			},
+		},
+		{
+			name: "team not found",
+			top:  intPtr(3),
+			team: "missing",
+			setupMocks: func(statsRepo *mocks.MockStatsRepository) {
+				statsRepo.On("GetStats", mock.Anything, 3, "missing", models.StatsWindow{}).Return(nil, errs.ErrTeamNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name: "invalid team name",
+			team: "team@1",
+			setupMocks: func(statsRepo *mocks.MockStatsRepository) {
+				// Service will return ErrBadRequest
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
 		{
 			name: "invalid top parameter (zero)",
 			top:  intPtr(0),
@@ -117,6 +168,42 @@ func TestStatsHandler_GetStats(t *testing.T) {
 			},
 			expectedStatus: http.StatusBadRequest,
 		},
+		{
+			name: "stats for time window",
+			top:  intPtr(3),
+			from: &sprintStart,
+			to:   &sprintEnd,
+			setupMocks: func(statsRepo *mocks.MockStatsRepository) {
+				stats := &models.Stats{
+					TotalTeams:         3,
+					TotalPullRequests:  8,
+					OpenPullRequests:   2,
+					MergedPullRequests: 6,
+					TopReviewers:       []*models.TopReviewer{{UserID: "user-1", UserName: "reviewer1", ReviewCount: 4}},
+				}
+				window := models.StatsWindow{From: &sprintStart, To: &sprintEnd}
+				statsRepo.On("GetStats", mock.Anything, 3, "", window).Return(stats, nil)
+			},
+			expectedStatus: http.StatusOK,
+			validateResponse: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				var stats omodels.Stats
+				err := json.Unmarshal(rec.Body.Bytes(), &stats)
+				assert.NoError(t, err)
+				assert.Equal(t, 8, stats.TotalPullRequests)
+				assert.Equal(t, 6, stats.MergedPullRequests)
+				assert.Equal(t, 4, stats.TopReviewers[0].ReviewCount)
+			},
+		},
+		{
+			name: "empty time window",
+			top:  intPtr(3),
+			from: &sprintEnd,
+			to:   &sprintStart,
+			setupMocks: func(statsRepo *mocks.MockStatsRepository) {
+				// Service will return ErrBadRequest
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
 		{
 			name: "empty stats",
 			top:  intPtr(5),
@@ -129,7 +216,7 @@ func TestStatsHandler_GetStats(t *testing.T) {
 					OpenPullRequests:  0,
 					TopReviewers:      []*models.TopReviewer{},
 				}
-				statsRepo.On("GetStats", mock.Anything, 5).Return(stats, nil)
+				statsRepo.On("GetStats", mock.Anything, 5, "", models.StatsWindow{}).Return(stats, nil)
 			},
 			expectedStatus: http.StatusOK,
 			validateResponse: func(t *testing.T, rec *httptest.ResponseRecorder) {
@@ -162,6 +249,11 @@ func TestStatsHandler_GetStats(t *testing.T) {
 			if tt.top != nil {
 				params.Top = tt.top
 			}
+			if tt.team != "" {
+				params.Team = &tt.team
+			}
+			params.From = tt.from
+			params.To = tt.to
 
 			// Execute
 			err := handler.GetStats(c, params)
@@ -179,6 +271,839 @@ func TestStatsHandler_GetStats(t *testing.T) {
 	}
 }
 
+func TestStatsHandler_GetStatsTeam(t *testing.T) {
+	tests := []struct {
+		name             string
+		teamName         string
+		setupMocks       func(*mocks.MockStatsRepository)
+		expectedStatus   int
+		validateResponse func(*testing.T, *httptest.ResponseRecorder)
+	}{
+		{
+			name:     "successful get team stats",
+			teamName: "backend",
+			setupMocks: func(statsRepo *mocks.MockStatsRepository) {
+				stats := &models.TeamStats{
+					TeamName:                 "backend",
+					OpenPullRequests:         4,
+					MergedPullRequests:       12,
+					TotalMembers:             3,
+					ActiveMembers:            2,
+					AvgReviewersPerPR:        1.75,
+					RequiredReviewers:        2,
+					UnderstaffedPullRequests: 1,
+					Members: []*models.TeamMemberStats{
+						{UserID: "user-1", UserName: "alice", IsActive: true, OpenReviews: 2, TotalReviews: 7},
+						{UserID: "user-2", UserName: "bob", IsActive: true, OpenReviews: 1, TotalReviews: 5},
+						{UserID: "user-3", UserName: "carol", IsActive: false, OpenReviews: 0, TotalReviews: 3},
+					},
+				}
+				statsRepo.On("GetTeamStats", mock.Anything, "backend", service.RequiredReviewers).Return(stats, nil)
+			},
+			expectedStatus: http.StatusOK,
+			validateResponse: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				var stats omodels.TeamStats
+				err := json.Unmarshal(rec.Body.Bytes(), &stats)
+				assert.NoError(t, err)
+				assert.Equal(t, "backend", stats.TeamName)
+				assert.Equal(t, 4, stats.OpenPullRequests)
+				assert.Equal(t, 2, stats.ActiveMembers)
+				assert.InDelta(t, 1.75, stats.AvgReviewersPerPr, 0.001)
+				assert.Equal(t, 1, stats.UnderstaffedPullRequests)
+				assert.Len(t, stats.Members, 3)
+				assert.Equal(t, 2, stats.Members[0].OpenReviews)
+				assert.False(t, stats.Members[2].IsActive)
+			},
+		},
+		{
+			name:     "team not found",
+			teamName: "missing",
+			setupMocks: func(statsRepo *mocks.MockStatsRepository) {
+				statsRepo.On("GetTeamStats", mock.Anything, "missing", service.RequiredReviewers).Return(nil, errs.ErrTeamNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:     "invalid team name",
+			teamName: "team@1",
+			setupMocks: func(statsRepo *mocks.MockStatsRepository) {
+				// Service will return ErrBadRequest
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			statsRepo := new(mocks.MockStatsRepository)
+			handler := web.NewStatsHandler(service.NewStatsService(statsRepo))
+
+			tt.setupMocks(statsRepo)
+
+			req := httptest.NewRequest(http.MethodGet, "/stats/team?team_name="+tt.teamName, nil)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			err := handler.GetStatsTeam(c, omodels.GetStatsTeamParams{TeamName: tt.teamName})
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+
+			if tt.validateResponse != nil {
+				tt.validateResponse(t, rec)
+			}
+
+			statsRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestStatsHandler_GetStatsLatency(t *testing.T) {
+	sprintStart := time.Date(2025, 10, 6, 0, 0, 0, 0, time.UTC)
+	sprintEnd := sprintStart.AddDate(0, 0, 14)
+
+	tests := []struct {
+		name             string
+		from             *time.Time
+		to               *time.Time
+		setupMocks       func(*mocks.MockStatsRepository)
+		expectedStatus   int
+		validateResponse func(*testing.T, *httptest.ResponseRecorder)
+	}{
+		{
+			name: "latency for time window",
+			from: &sprintStart,
+			to:   &sprintEnd,
+			setupMocks: func(statsRepo *mocks.MockStatsRepository) {
+				stats := &models.LatencyStats{
+					TimeToMerge:     models.LatencyPercentiles{Count: 10, P50: 3600, P90: 7200, P99: 86400},
+					AssignedToMerge: models.LatencyPercentiles{Count: 18, P50: 1800, P90: 5400, P99: 80000},
+					Teams: []*models.TeamLatency{
+						{
+							TeamName:        "backend",
+							TimeToMerge:     models.LatencyPercentiles{Count: 10, P50: 3600, P90: 7200, P99: 86400},
+							AssignedToMerge: models.LatencyPercentiles{Count: 18, P50: 1800, P90: 5400, P99: 80000},
+						},
+					},
+					Reviewers: []*models.ReviewerLatency{
+						{UserID: "user-1", UserName: "alice", AssignedToMerge: models.LatencyPercentiles{Count: 9, P50: 1200, P90: 3000, P99: 4000}},
+					},
+				}
+				window := models.StatsWindow{From: &sprintStart, To: &sprintEnd}
+				statsRepo.On("GetLatencyStats", mock.Anything, window).Return(stats, nil)
+			},
+			expectedStatus: http.StatusOK,
+			validateResponse: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				var stats omodels.LatencyStats
+				err := json.Unmarshal(rec.Body.Bytes(), &stats)
+				assert.NoError(t, err)
+				assert.Equal(t, 10, stats.TimeToMerge.Count)
+				assert.Equal(t, float64(3600), stats.TimeToMerge.P50)
+				assert.Equal(t, float64(80000), stats.AssignedToMerge.P99)
+				assert.Len(t, stats.Teams, 1)
+				assert.Equal(t, "backend", stats.Teams[0].TeamName)
+				assert.Len(t, stats.Reviewers, 1)
+				assert.Equal(t, 9, stats.Reviewers[0].AssignedToMerge.Count)
+			},
+		},
+		{
+			name: "no merged pull requests",
+			setupMocks: func(statsRepo *mocks.MockStatsRepository) {
+				stats := &models.LatencyStats{
+					Teams:     []*models.TeamLatency{},
+					Reviewers: []*models.ReviewerLatency{},
+				}
+				statsRepo.On("GetLatencyStats", mock.Anything, models.StatsWindow{}).Return(stats, nil)
+			},
+			expectedStatus: http.StatusOK,
+			validateResponse: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				var stats omodels.LatencyStats
+				err := json.Unmarshal(rec.Body.Bytes(), &stats)
+				assert.NoError(t, err)
+				assert.Equal(t, 0, stats.TimeToMerge.Count)
+				assert.Empty(t, stats.Teams)
+				assert.Empty(t, stats.Reviewers)
+			},
+		},
+		{
+			name: "empty time window",
+			from: &sprintEnd,
+			to:   &sprintStart,
+			setupMocks: func(statsRepo *mocks.MockStatsRepository) {
+				// Service will return ErrBadRequest
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			statsRepo := new(mocks.MockStatsRepository)
+			handler := web.NewStatsHandler(service.NewStatsService(statsRepo))
+
+			tt.setupMocks(statsRepo)
+
+			req := httptest.NewRequest(http.MethodGet, "/stats/latency", nil)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			err := handler.GetStatsLatency(c, omodels.GetStatsLatencyParams{From: tt.from, To: tt.to})
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+
+			if tt.validateResponse != nil {
+				tt.validateResponse(t, rec)
+			}
+
+			statsRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestStatsHandler_GetStatsFairness(t *testing.T) {
+	tests := []struct {
+		name             string
+		team             string
+		setupMocks       func(*mocks.MockStatsRepository)
+		expectedStatus   int
+		validateResponse func(*testing.T, *httptest.ResponseRecorder)
+	}{
+		{
+			name: "distribution per team",
+			setupMocks: func(statsRepo *mocks.MockStatsRepository) {
+				loads := []*models.ReviewerLoad{
+					{TeamName: "backend", UserID: "user-1", UserName: "alice", Assignments: 7},
+					{TeamName: "backend", UserID: "user-2", UserName: "bob", Assignments: 1},
+					{TeamName: "backend", UserID: "user-3", UserName: "carol", Assignments: 4},
+					{TeamName: "frontend", UserID: "user-4", UserName: "dave", Assignments: 0},
+					{TeamName: "frontend", UserID: "user-5", UserName: "erin", Assignments: 0},
+				}
+				statsRepo.On("GetReviewerLoads", mock.Anything, "", models.StatsWindow{}).Return(loads, nil)
+			},
+			expectedStatus: http.StatusOK,
+			validateResponse: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				var stats omodels.FairnessStats
+				err := json.Unmarshal(rec.Body.Bytes(), &stats)
+				assert.NoError(t, err)
+				assert.Len(t, stats.Teams, 2)
+
+				backend := stats.Teams[0]
+				assert.Equal(t, "backend", backend.TeamName)
+				assert.Equal(t, 3, backend.Reviewers)
+				assert.Equal(t, 12, backend.TotalAssignments)
+				assert.Equal(t, 1, backend.Min)
+				assert.Equal(t, 7, backend.Max)
+				assert.InDelta(t, 4.0, backend.Mean, 0.001)
+				assert.InDelta(t, 2.449, backend.Stddev, 0.001)
+				assert.InDelta(t, 0.333, backend.Gini, 0.001)
+				assert.Len(t, backend.Overloaded, 1)
+				assert.Equal(t, "user-1", backend.Overloaded[0].UserId)
+				assert.Len(t, backend.Underloaded, 1)
+				assert.Equal(t, "user-2", backend.Underloaded[0].UserId)
+
+				frontend := stats.Teams[1]
+				assert.Equal(t, 0, frontend.TotalAssignments)
+				assert.Equal(t, float64(0), frontend.Gini)
+				assert.Empty(t, frontend.Overloaded)
+				assert.Empty(t, frontend.Underloaded)
+			},
+		},
+		{
+			name: "team not found",
+			team: "missing",
+			setupMocks: func(statsRepo *mocks.MockStatsRepository) {
+				statsRepo.On("GetReviewerLoads", mock.Anything, "missing", models.StatsWindow{}).Return(nil, errs.ErrTeamNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name: "invalid team name",
+			team: "team@1",
+			setupMocks: func(statsRepo *mocks.MockStatsRepository) {
+				// Service will return ErrBadRequest
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			statsRepo := new(mocks.MockStatsRepository)
+			handler := web.NewStatsHandler(service.NewStatsService(statsRepo))
+
+			tt.setupMocks(statsRepo)
+
+			req := httptest.NewRequest(http.MethodGet, "/stats/fairness", nil)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			var params omodels.GetStatsFairnessParams
+			if tt.team != "" {
+				params.Team = &tt.team
+			}
+
+			err := handler.GetStatsFairness(c, params)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+
+			if tt.validateResponse != nil {
+				tt.validateResponse(t, rec)
+			}
+
+			statsRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestStatsHandler_GetStatsTimeseries(t *testing.T) {
+	sprintStart := time.Date(2025, 10, 6, 0, 0, 0, 0, time.UTC)
+	sprintEnd := sprintStart.AddDate(0, 0, 14)
+	longAgo := sprintStart.AddDate(-5, 0, 0)
+
+	week := omodels.Week
+	day := omodels.Day
+
+	tests := []struct {
+		name             string
+		metric           omodels.TimeseriesMetric
+		bucket           *omodels.TimeseriesBucket
+		team             string
+		from             *time.Time
+		to               *time.Time
+		setupMocks       func(*mocks.MockStatsRepository)
+		expectedStatus   int
+		validateResponse func(*testing.T, *httptest.ResponseRecorder)
+	}{
+		{
+			name:   "weekly merged pull requests",
+			metric: omodels.PrsMerged,
+			bucket: &week,
+			team:   "platform",
+			from:   &sprintStart,
+			to:     &sprintEnd,
+			setupMocks: func(statsRepo *mocks.MockStatsRepository) {
+				filter := models.TimeseriesFilter{
+					Metric:   models.TimeseriesPullRequestsMerged,
+					Bucket:   models.TimeseriesWeek,
+					TeamName: "platform",
+					From:     sprintStart,
+					To:       sprintEnd,
+				}
+				points := []*models.TimeseriesPoint{
+					{BucketStart: sprintStart, Value: 5},
+					{BucketStart: sprintStart.AddDate(0, 0, 7), Value: 0},
+				}
+				statsRepo.On("GetTimeseries", mock.Anything, filter).Return(points, nil)
+			},
+			expectedStatus: http.StatusOK,
+			validateResponse: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				var series omodels.Timeseries
+				err := json.Unmarshal(rec.Body.Bytes(), &series)
+				assert.NoError(t, err)
+				assert.Equal(t, omodels.PrsMerged, series.Metric)
+				assert.Equal(t, omodels.Week, series.Bucket)
+				assert.Equal(t, "platform", *series.Team)
+				assert.Len(t, series.Points, 2)
+				assert.Equal(t, 5, series.Points[0].Value)
+				assert.Equal(t, 0, series.Points[1].Value)
+				assert.True(t, sprintStart.Equal(series.Points[0].BucketStart))
+			},
+		},
+		{
+			name:   "default daily range",
+			metric: omodels.PrsCreated,
+			setupMocks: func(statsRepo *mocks.MockStatsRepository) {
+				statsRepo.On("GetTimeseries", mock.Anything, mock.MatchedBy(func(f models.TimeseriesFilter) bool {
+					days := f.To.Sub(f.From).Hours() / 24
+					return f.Metric == models.TimeseriesPullRequestsCreated && f.Bucket == models.TimeseriesDay &&
+						f.TeamName == "" && days > 29 && days <= 30
+				})).Return([]*models.TimeseriesPoint{}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			validateResponse: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				var series omodels.Timeseries
+				err := json.Unmarshal(rec.Body.Bytes(), &series)
+				assert.NoError(t, err)
+				assert.Equal(t, omodels.Day, series.Bucket)
+				assert.Nil(t, series.Team)
+			},
+		},
+		{
+			name:   "team not found",
+			metric: omodels.Assignments,
+			team:   "missing",
+			from:   &sprintStart,
+			to:     &sprintEnd,
+			setupMocks: func(statsRepo *mocks.MockStatsRepository) {
+				statsRepo.On("GetTimeseries", mock.Anything, mock.Anything).Return(nil, errs.ErrTeamNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:   "unknown metric",
+			metric: omodels.TimeseriesMetric("reviews_completed"),
+			setupMocks: func(statsRepo *mocks.MockStatsRepository) {
+				// Service will return ErrBadRequest
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "too many buckets",
+			metric: omodels.PrsCreated,
+			bucket: &day,
+			from:   &longAgo,
+			to:     &sprintEnd,
+			setupMocks: func(statsRepo *mocks.MockStatsRepository) {
+				// Service will return ErrBadRequest
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			statsRepo := new(mocks.MockStatsRepository)
+			handler := web.NewStatsHandler(service.NewStatsService(statsRepo))
+
+			tt.setupMocks(statsRepo)
+
+			req := httptest.NewRequest(http.MethodGet, "/stats/timeseries", nil)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			params := omodels.GetStatsTimeseriesParams{Metric: tt.metric, Bucket: tt.bucket, From: tt.from, To: tt.to}
+			if tt.team != "" {
+				params.Team = &tt.team
+			}
+
+			err := handler.GetStatsTimeseries(c, params)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+
+			if tt.validateResponse != nil {
+				tt.validateResponse(t, rec)
+			}
+
+			statsRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestStatsHandler_GetStatsUser(t *testing.T) {
+	medianHeld := 14400.0
+
+	tests := []struct {
+		name             string
+		userID           string
+		setupMocks       func(*mocks.MockStatsRepository)
+		expectedStatus   int
+		validateResponse func(*testing.T, *httptest.ResponseRecorder)
+	}{
+		{
+			name:   "successful get user stats",
+			userID: "user-1",
+			setupMocks: func(statsRepo *mocks.MockStatsRepository) {
+				stats := &models.UserStats{
+					UserID:               "user-1",
+					UserName:             "alice",
+					AuthoredPullRequests: 8,
+					OpenReviews:          2,
+					TotalReviews:         15,
+					ReassignedAway:       1,
+					MergedWhileReviewer:  11,
+					MedianHeldSeconds:    &medianHeld,
+					ReviewedTeams: []*models.TeamReviewCount{
+						{TeamName: "backend", Reviews: 13},
+						{TeamName: "payments", Reviews: 2},
+					},
+				}
+				statsRepo.On("GetUserStats", mock.Anything, "user-1").Return(stats, nil)
+			},
+			expectedStatus: http.StatusOK,
+			validateResponse: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				var stats omodels.UserStats
+				err := json.Unmarshal(rec.Body.Bytes(), &stats)
+				assert.NoError(t, err)
+				assert.Equal(t, "alice", stats.Username)
+				assert.Equal(t, 8, stats.AuthoredPullRequests)
+				assert.Equal(t, 15, stats.TotalReviews)
+				assert.Equal(t, 1, stats.ReassignedAway)
+				assert.Equal(t, 11, stats.MergedWhileReviewer)
+				assert.Equal(t, medianHeld, *stats.MedianHeldSeconds)
+				assert.Len(t, stats.ReviewedTeams, 2)
+				assert.Equal(t, "backend", stats.ReviewedTeams[0].TeamName)
+			},
+		},
+		{
+			name:   "user without reviews",
+			userID: "user-2",
+			setupMocks: func(statsRepo *mocks.MockStatsRepository) {
+				stats := &models.UserStats{
+					UserID:        "user-2",
+					UserName:      "bob",
+					ReviewedTeams: []*models.TeamReviewCount{},
+				}
+				statsRepo.On("GetUserStats", mock.Anything, "user-2").Return(stats, nil)
+			},
+			expectedStatus: http.StatusOK,
+			validateResponse: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.Contains(t, rec.Body.String(), `"median_held_seconds":null`)
+			},
+		},
+		{
+			name:   "user not found",
+			userID: "missing",
+			setupMocks: func(statsRepo *mocks.MockStatsRepository) {
+				statsRepo.On("GetUserStats", mock.Anything, "missing").Return(nil, errs.ErrUserNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:   "empty user id",
+			userID: "",
+			setupMocks: func(statsRepo *mocks.MockStatsRepository) {
+				// Service will return ErrBadRequest
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			statsRepo := new(mocks.MockStatsRepository)
+			handler := web.NewStatsHandler(service.NewStatsService(statsRepo))
+
+			tt.setupMocks(statsRepo)
+
+			req := httptest.NewRequest(http.MethodGet, "/stats/user?user_id="+tt.userID, nil)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			err := handler.GetStatsUser(c, omodels.GetStatsUserParams{UserId: tt.userID})
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+
+			if tt.validateResponse != nil {
+				tt.validateResponse(t, rec)
+			}
+
+			statsRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestStatsHandler_GetStatsAging(t *testing.T) {
+	asOf := time.Date(2025, 10, 20, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name             string
+		team             *string
+		limit            *int
+		setupMocks       func(*mocks.MockStatsRepository)
+		expectedStatus   int
+		validateResponse func(*testing.T, *httptest.ResponseRecorder)
+	}{
+		{
+			name: "successful get aging with default limit",
+			setupMocks: func(statsRepo *mocks.MockStatsRepository) {
+				stats := &models.AgingStats{
+					AsOf:  asOf,
+					Total: models.AgingBuckets{Under1Day: 4, Days1To3: 3, Days3To7: 1, Over7Days: 2, Total: 10},
+					Teams: []*models.TeamAging{
+						{TeamName: "backend", AgingBuckets: models.AgingBuckets{Under1Day: 2, Days1To3: 1, Days3To7: 1, Over7Days: 2, Total: 6}},
+					},
+					Reviewers: []*models.ReviewerAging{
+						{UserID: "u2", UserName: "Bob", AgingBuckets: models.AgingBuckets{Days3To7: 1, Over7Days: 2, Total: 3}},
+					},
+					Oldest: []*models.AgingPullRequest{
+						{
+							PullRequestID:     "pr-1001",
+							PullRequestName:   "Add search",
+							AuthorID:          "u1",
+							CreatedAt:         asOf.AddDate(0, 0, -11),
+							AgeSeconds:        11 * 24 * 3600,
+							AssignedReviewers: []string{"u2", "u3"},
+						},
+					},
+				}
+				statsRepo.On("GetAgingStats", mock.Anything, "", service.DefaultAgingOldest).Return(stats, nil)
+			},
+			expectedStatus: http.StatusOK,
+			validateResponse: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				var stats omodels.AgingStats
+				err := json.Unmarshal(rec.Body.Bytes(), &stats)
+				assert.NoError(t, err)
+				assert.Equal(t, asOf, stats.AsOf)
+				assert.Equal(t, omodels.AgingBuckets{Under1d: 4, Days13: 3, Days37: 1, Over7d: 2, Total: 10}, stats.Total)
+				assert.Len(t, stats.Teams, 1)
+				assert.Equal(t, 2, stats.Teams[0].Buckets.Over7d)
+				assert.Len(t, stats.Reviewers, 1)
+				assert.Equal(t, "Bob", stats.Reviewers[0].Username)
+				assert.Len(t, stats.Oldest, 1)
+				assert.Equal(t, []string{"u2", "u3"}, stats.Oldest[0].AssignedReviewers)
+				assert.Equal(t, int64(11*24*3600), stats.Oldest[0].AgeSeconds)
+			},
+		},
+		{
+			name:  "team filter and limit above maximum",
+			team:  strPtr("backend"),
+			limit: intPtr(1000),
+			setupMocks: func(statsRepo *mocks.MockStatsRepository) {
+				stats := &models.AgingStats{
+					AsOf:      asOf,
+					Teams:     []*models.TeamAging{},
+					Reviewers: []*models.ReviewerAging{},
+					Oldest:    []*models.AgingPullRequest{},
+				}
+				statsRepo.On("GetAgingStats", mock.Anything, "backend", service.MaxAgingOldest).Return(stats, nil)
+			},
+			expectedStatus: http.StatusOK,
+			validateResponse: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.Contains(t, rec.Body.String(), `"oldest":[]`)
+				assert.Contains(t, rec.Body.String(), `"teams":[]`)
+			},
+		},
+		{
+			name: "team not found",
+			team: strPtr("missing"),
+			setupMocks: func(statsRepo *mocks.MockStatsRepository) {
+				statsRepo.On("GetAgingStats", mock.Anything, "missing", service.DefaultAgingOldest).Return(nil, errs.ErrTeamNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:  "non-positive limit",
+			limit: intPtr(0),
+			setupMocks: func(statsRepo *mocks.MockStatsRepository) {
+				// Service will return ErrBadRequest
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			statsRepo := new(mocks.MockStatsRepository)
+			handler := web.NewStatsHandler(service.NewStatsService(statsRepo))
+
+			tt.setupMocks(statsRepo)
+
+			req := httptest.NewRequest(http.MethodGet, "/stats/aging", nil)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			err := handler.GetStatsAging(c, omodels.GetStatsAgingParams{Team: tt.team, Limit: tt.limit})
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+
+			if tt.validateResponse != nil {
+				tt.validateResponse(t, rec)
+			}
+
+			statsRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestStatsHandler_Export(t *testing.T) {
+	t.Run("stats as csv", func(t *testing.T) {
+		e := echo.New()
+		statsRepo := new(mocks.MockStatsRepository)
+		handler := web.NewStatsHandler(service.NewStatsService(statsRepo))
+
+		stats := &models.Stats{
+			TotalTeams:         3,
+			TotalUsers:         10,
+			ActiveUsers:        8,
+			TotalPullRequests:  12,
+			OpenPullRequests:   4,
+			MergedPullRequests: 8,
+			TopReviewers:       []*models.TopReviewer{{UserID: "user-1", UserName: "alice", ReviewCount: 6}},
+			AsOf:               time.Date(2025, 10, 24, 12, 0, 0, 0, time.UTC),
+		}
+		statsRepo.On("GetStats", mock.Anything, 0, "", models.StatsWindow{}).Return(stats, nil)
+
+		req := httptest.NewRequest(http.MethodGet, "/stats", nil)
+		req.Header.Set(echo.HeaderAccept, "text/csv")
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		err := handler.GetStats(c, omodels.GetStatsParams{})
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "text/csv; charset=utf-8", rec.Header().Get(echo.HeaderContentType))
+		assert.Equal(t, "metric,user_id,username,value\n"+
+			"as_of,,,2025-10-24T12:00:00Z\n"+
+			"total_teams,,,3\n"+
+			"total_users,,,10\n"+
+			"active_users,,,8\n"+
+			"total_pull_requests,,,12\n"+
+			"open_pull_requests,,,4\n"+
+			"merged_pull_requests,,,8\n"+
+			"review_count,user-1,alice,6\n", rec.Body.String())
+
+		statsRepo.AssertExpectations(t)
+	})
+
+	t.Run("team stats as ndjson", func(t *testing.T) {
+		e := echo.New()
+		statsRepo := new(mocks.MockStatsRepository)
+		handler := web.NewStatsHandler(service.NewStatsService(statsRepo))
+
+		stats := &models.TeamStats{
+			TeamName:          "backend",
+			AvgReviewersPerPR: 1.5,
+			RequiredReviewers: 2,
+			Members: []*models.TeamMemberStats{
+				{UserID: "user-1", UserName: "alice", IsActive: true, OpenReviews: 2, TotalReviews: 7},
+			},
+		}
+		statsRepo.On("GetTeamStats", mock.Anything, "backend", service.RequiredReviewers).Return(stats, nil)
+
+		req := httptest.NewRequest(http.MethodGet, "/stats/team?team_name=backend&format=ndjson", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		ndjson := omodels.ExportFormatNdjson
+		err := handler.GetStatsTeam(c, omodels.GetStatsTeamParams{TeamName: "backend", Format: &ndjson})
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "application/x-ndjson", rec.Header().Get(echo.HeaderContentType))
+
+		lines := strings.Split(strings.TrimSpace(rec.Body.String()), "\n")
+		assert.Len(t, lines, 10)
+		assert.JSONEq(t, `{"metric":"avg_reviewers_per_pr","value":1.5}`, lines[4])
+		assert.JSONEq(t, `{"metric":"open_reviews","user_id":"user-1","username":"alice","value":2}`, lines[8])
+
+		statsRepo.AssertExpectations(t)
+	})
+
+	t.Run("accept with q-values", func(t *testing.T) {
+		for accept, contentType := range map[string]string{
+			"text/csv;q=0.1, application/x-ndjson":                "application/x-ndjson",
+			"application/json;q=0.5, text/csv;q=0.8":              "text/csv; charset=utf-8",
+			"text/csv;q=0, application/x-ndjson;q=0.2, */*;q=0.1": "application/x-ndjson",
+			"application/x-ndjson;q=0":                            echo.MIMEApplicationJSON,
+		} {
+			e := echo.New()
+			statsRepo := new(mocks.MockStatsRepository)
+			handler := web.NewStatsHandler(service.NewStatsService(statsRepo))
+			statsRepo.On("GetStats", mock.Anything, 0, "", models.StatsWindow{}).
+				Return(&models.Stats{TopReviewers: []*models.TopReviewer{}}, nil)
+
+			req := httptest.NewRequest(http.MethodGet, "/stats", nil)
+			req.Header.Set(echo.HeaderAccept, accept)
+			rec := httptest.NewRecorder()
+
+			err := handler.GetStats(e.NewContext(req, rec), omodels.GetStatsParams{})
+
+			assert.NoError(t, err)
+			assert.Equal(t, http.StatusOK, rec.Code, accept)
+			assert.Equal(t, contentType, rec.Header().Get(echo.HeaderContentType), accept)
+		}
+	})
+
+	t.Run("unknown format", func(t *testing.T) {
+		e := echo.New()
+		statsRepo := new(mocks.MockStatsRepository)
+		handler := web.NewStatsHandler(service.NewStatsService(statsRepo))
+
+		req := httptest.NewRequest(http.MethodGet, "/stats?format=xlsx", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		xlsx := omodels.ExportFormat("xlsx")
+		err := handler.GetStats(c, omodels.GetStatsParams{Format: &xlsx})
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+}
+
+func TestStatsHandler_GetStats_Cache(t *testing.T) {
+	e := echo.New()
+	statsRepo := new(mocks.MockStatsRepository)
+	handler := web.NewStatsHandler(service.NewStatsService(statsRepo))
+
+	refreshedAt := time.Date(2025, 10, 24, 12, 0, 0, 0, time.UTC)
+	summary := &models.Stats{TotalTeams: 3, TopReviewers: []*models.TopReviewer{}, AsOf: refreshedAt}
+	teamStats := &models.Stats{TotalTeams: 1, TopReviewers: []*models.TopReviewer{}, AsOf: refreshedAt.Add(time.Minute)}
+
+	statsRepo.On("GetStats", mock.Anything, 3, "", models.StatsWindow{}).Return(summary, nil).Once()
+	statsRepo.On("GetStats", mock.Anything, 3, "platform", models.StatsWindow{}).Return(teamStats, nil).Once()
+
+	get := func(params omodels.GetStatsParams) omodels.Stats {
+		req := httptest.NewRequest(http.MethodGet, "/stats", nil)
+		rec := httptest.NewRecorder()
+
+		err := handler.GetStats(e.NewContext(req, rec), params)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		var stats omodels.Stats
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &stats))
+		return stats
+	}
+
+	team := "platform"
+
+	first := get(omodels.GetStatsParams{Top: intPtr(3)})
+	second := get(omodels.GetStatsParams{Top: intPtr(3)})
+	teamResp := get(omodels.GetStatsParams{Top: intPtr(3), Team: &team})
+
+	assert.Equal(t, 3, first.TotalTeams)
+	assert.True(t, refreshedAt.Equal(first.AsOf))
+	assert.Equal(t, first, second)
+	assert.Equal(t, 1, teamResp.TotalTeams)
+
+	// повторные запросы в пределах StatsCacheTTL в репозиторий не уходят
+	statsRepo.AssertExpectations(t)
+}
+
+func TestStatsService_RunSummaryRefresh(t *testing.T) {
+	statsRepo := new(mocks.MockStatsRepository)
+	refreshed := make(chan struct{}, 1)
+	statsRepo.On("RefreshStatsSummary", mock.Anything).
+		Run(func(mock.Arguments) { refreshed <- struct{}{} }).
+		Return(nil).Once()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		service.NewStatsService(statsRepo).RunSummaryRefresh(ctx, time.Hour)
+		close(done)
+	}()
+
+	// первый пересчёт не ждёт тика, иначе после запуска статистика час оставалась бы пустой
+	select {
+	case <-refreshed:
+	case <-time.After(time.Second):
+		t.Fatal("summary was not refreshed on start")
+	}
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("refresh loop did not stop after cancel")
+	}
+
+	statsRepo.AssertExpectations(t)
+}
+
 // Helper function to create int pointer
 func intPtr(i int) *int {
 	return &i
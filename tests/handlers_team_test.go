@@ -8,6 +8,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/guarref/pr-service-assignment/internal/auth"
 	"github.com/guarref/pr-service-assignment/internal/errs"
 	"github.com/guarref/pr-service-assignment/internal/models"
 	"github.com/guarref/pr-service-assignment/internal/repository/mocks"
@@ -21,14 +22,17 @@ import (
 
 func TestTeamHandler_PostTeamAdd(t *testing.T) {
 	tests := []struct {
-		name           string
-		requestBody    interface{}
-		setupMocks     func(*mocks.MockTeamRepository)
-		expectedStatus int
+		name             string
+		caller           string
+		requestBody      interface{}
+		setupMocks       func(*mocks.MockTeamRepository)
+		setupUsers       func(*mocks.MockUserRepository)
+		expectedStatus   int
 		validateResponse func(*testing.T, *httptest.ResponseRecorder)
 	}{
 		{
-			name: "successful team creation",
+			name:   "successful team creation",
+			caller: "user-1",
 			requestBody: omodels.PostTeamAddJSONRequestBody{
 				TeamName: "team-1",
 				Members: []omodels.TeamMember{
@@ -53,7 +57,123 @@ func TestTeamHandler_PostTeamAdd(t *testing.T) {
 			},
 		},
 		{
-			name: "invalid request body",
+			name:   "team creation with roles",
+			caller: "user-1",
+			requestBody: omodels.PostTeamAddJSONRequestBody{
+				TeamName: "team-1",
+				Members: []omodels.TeamMember{
+					{UserId: "user-1", Username: "user1", IsActive: true, Role: rolePtr(omodels.Lead)},
+					{UserId: "user-2", Username: "user2", IsActive: true},
+				},
+			},
+			setupMocks: func(teamRepo *mocks.MockTeamRepository) {
+				teamRepo.On("CreateTeam", mock.Anything, mock.MatchedBy(func(team *models.Team) bool {
+					return team.Members[0].Role == models.TeamRoleLead && team.Members[1].Role == models.TeamRoleMember
+				})).Return(nil)
+			},
+			expectedStatus: http.StatusCreated,
+			validateResponse: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				var response struct {
+					Team omodels.Team `json:"team"`
+				}
+				err := json.Unmarshal(rec.Body.Bytes(), &response)
+				assert.NoError(t, err)
+				assert.Equal(t, omodels.Lead, *response.Team.Members[0].Role)
+				assert.Equal(t, omodels.Member, *response.Team.Members[1].Role)
+			},
+		},
+		{
+			name:   "unknown role",
+			caller: "user-1",
+			requestBody: omodels.PostTeamAddJSONRequestBody{
+				TeamName: "team-1",
+				Members: []omodels.TeamMember{
+					{UserId: "user-1", Username: "user1", IsActive: true, Role: rolePtr("owner")},
+				},
+			},
+			setupMocks:     func(teamRepo *mocks.MockTeamRepository) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "team creation with parent team",
+			caller: "user-1",
+			requestBody: omodels.PostTeamAddJSONRequestBody{
+				TeamName:   "squad-1",
+				ParentTeam: strPtr("platform"),
+				Members: []omodels.TeamMember{
+					{UserId: "user-1", Username: "user1", IsActive: true},
+				},
+			},
+			setupMocks: func(teamRepo *mocks.MockTeamRepository) {
+				teamRepo.On("CreateTeam", mock.Anything, mock.MatchedBy(func(team *models.Team) bool {
+					return team.TeamName == "squad-1" && team.ParentTeam != nil && *team.ParentTeam == "platform"
+				})).Return(nil)
+			},
+			expectedStatus: http.StatusCreated,
+			validateResponse: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				var response struct {
+					Team omodels.Team `json:"team"`
+				}
+				err := json.Unmarshal(rec.Body.Bytes(), &response)
+				assert.NoError(t, err)
+				if assert.NotNil(t, response.Team.ParentTeam) {
+					assert.Equal(t, "platform", *response.Team.ParentTeam)
+				}
+			},
+		},
+		{
+			name:   "new team is primary only for new users",
+			caller: "user-1",
+			requestBody: omodels.PostTeamAddJSONRequestBody{
+				TeamName: "team-2",
+				Members: []omodels.TeamMember{
+					{UserId: "user-1", Username: "user1", IsActive: true, IsPrimary: boolPtr(true)},
+					{UserId: "user-2", Username: "user2", IsActive: true},
+				},
+			},
+			setupUsers: func(userRepo *mocks.MockUserRepository) {
+				userRepo.On("GetUserByID", mock.Anything, "user-2").Return(&models.User{UserID: "user-2", UserName: "user2", IsActive: true, TeamName: "team-1"}, nil)
+			},
+			setupMocks: func(teamRepo *mocks.MockTeamRepository) {
+				teamRepo.On("CreateTeam", mock.Anything, mock.MatchedBy(func(team *models.Team) bool {
+					return len(team.Members) == 2 && team.Members[0].IsPrimary && !team.Members[1].IsPrimary
+				})).Return(nil)
+			},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:   "parent team not found",
+			caller: "user-1",
+			requestBody: omodels.PostTeamAddJSONRequestBody{
+				TeamName:   "squad-1",
+				ParentTeam: strPtr("missing"),
+				Members: []omodels.TeamMember{
+					{UserId: "user-1", Username: "user1", IsActive: true},
+				},
+			},
+			setupMocks: func(teamRepo *mocks.MockTeamRepository) {
+				teamRepo.On("CreateTeam", mock.Anything, mock.Anything).Return(errs.ErrParentTeamNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:   "team is its own parent",
+			caller: "user-1",
+			requestBody: omodels.PostTeamAddJSONRequestBody{
+				TeamName:   "squad-1",
+				ParentTeam: strPtr("squad-1"),
+				Members: []omodels.TeamMember{
+					{UserId: "user-1", Username: "user1", IsActive: true},
+				},
+			},
+			setupMocks: func(teamRepo *mocks.MockTeamRepository) {
+				// Service will return ErrBadRequest
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "invalid request body",
+			caller: "user-1",
 			requestBody: map[string]interface{}{
 				"invalid": "data",
 			},
@@ -63,7 +183,8 @@ func TestTeamHandler_PostTeamAdd(t *testing.T) {
 			expectedStatus: http.StatusBadRequest,
 		},
 		{
-			name: "empty team name",
+			name:   "empty team name",
+			caller: "user-1",
 			requestBody: omodels.PostTeamAddJSONRequestBody{
 				TeamName: "",
 				Members: []omodels.TeamMember{
@@ -76,7 +197,8 @@ func TestTeamHandler_PostTeamAdd(t *testing.T) {
 			expectedStatus: http.StatusBadRequest,
 		},
 		{
-			name: "empty members",
+			name:   "empty members",
+			caller: "user-1",
 			requestBody: omodels.PostTeamAddJSONRequestBody{
 				TeamName: "team-1",
 				Members:  []omodels.TeamMember{},
@@ -87,7 +209,8 @@ func TestTeamHandler_PostTeamAdd(t *testing.T) {
 			expectedStatus: http.StatusBadRequest,
 		},
 		{
-			name: "team already exists",
+			name:   "team already exists",
+			caller: "user-1",
 			requestBody: omodels.PostTeamAddJSONRequestBody{
 				TeamName: "team-1",
 				Members: []omodels.TeamMember{
@@ -100,7 +223,8 @@ func TestTeamHandler_PostTeamAdd(t *testing.T) {
 			expectedStatus: http.StatusConflict,
 		},
 		{
-			name: "invalid team name format",
+			name:   "invalid team name format",
+			caller: "user-1",
 			requestBody: omodels.PostTeamAddJSONRequestBody{
 				TeamName: "team@1", // Invalid character
 				Members: []omodels.TeamMember{
@@ -112,6 +236,109 @@ func TestTeamHandler_PostTeamAdd(t *testing.T) {
 			},
 			expectedStatus: http.StatusBadRequest,
 		},
+		{
+			name: "no token",
+			requestBody: omodels.PostTeamAddJSONRequestBody{
+				TeamName: "team-1",
+				Members: []omodels.TeamMember{
+					{UserId: "user-1", Username: "user1", IsActive: true},
+				},
+			},
+			setupMocks:     func(teamRepo *mocks.MockTeamRepository) {},
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:   "lead role for another member",
+			caller: "user-1",
+			requestBody: omodels.PostTeamAddJSONRequestBody{
+				TeamName: "team-1",
+				Members: []omodels.TeamMember{
+					{UserId: "user-1", Username: "user1", IsActive: true},
+					{UserId: "user-2", Username: "user2", IsActive: true, Role: rolePtr(omodels.Lead)},
+				},
+			},
+			setupMocks:     func(teamRepo *mocks.MockTeamRepository) {},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:   "maintainer role without a shared managed team",
+			caller: "user-1",
+			requestBody: omodels.PostTeamAddJSONRequestBody{
+				TeamName: "team-1",
+				Members: []omodels.TeamMember{
+					{UserId: "user-2", Username: "user2", IsActive: true, Role: rolePtr(omodels.Maintainer)},
+				},
+			},
+			setupUsers: func(userRepo *mocks.MockUserRepository) {
+				userRepo.On("IsManagerOf", mock.Anything, "user-1", "user-2").Return(false, nil)
+			},
+			setupMocks:     func(teamRepo *mocks.MockTeamRepository) {},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:   "maintainer role granted by lead of a shared team",
+			caller: "user-1",
+			requestBody: omodels.PostTeamAddJSONRequestBody{
+				TeamName: "team-1",
+				Members: []omodels.TeamMember{
+					{UserId: "user-2", Username: "user2", IsActive: true, Role: rolePtr(omodels.Maintainer)},
+				},
+			},
+			setupUsers: func(userRepo *mocks.MockUserRepository) {
+				userRepo.On("IsManagerOf", mock.Anything, "user-1", "user-2").Return(true, nil)
+			},
+			setupMocks: func(teamRepo *mocks.MockTeamRepository) {
+				teamRepo.On("CreateTeam", mock.Anything, mock.MatchedBy(func(team *models.Team) bool {
+					return team.Members[0].Role == models.TeamRoleMaintainer
+				})).Return(nil)
+			},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:   "primary flag for an existing user",
+			caller: "user-1",
+			requestBody: omodels.PostTeamAddJSONRequestBody{
+				TeamName: "team-1",
+				Members: []omodels.TeamMember{
+					{UserId: "user-2", Username: "user2", IsActive: true, IsPrimary: boolPtr(true)},
+				},
+			},
+			setupUsers: func(userRepo *mocks.MockUserRepository) {
+				userRepo.On("GetUserByID", mock.Anything, "user-2").Return(&models.User{UserID: "user-2", UserName: "user2", IsActive: true, TeamName: "backend"}, nil)
+			},
+			setupMocks:     func(teamRepo *mocks.MockTeamRepository) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "username change of an existing user",
+			caller: "user-1",
+			requestBody: omodels.PostTeamAddJSONRequestBody{
+				TeamName: "team-1",
+				Members: []omodels.TeamMember{
+					{UserId: "user-2", Username: "renamed", IsActive: true},
+				},
+			},
+			setupUsers: func(userRepo *mocks.MockUserRepository) {
+				userRepo.On("GetUserByID", mock.Anything, "user-2").Return(&models.User{UserID: "user-2", UserName: "user2", IsActive: true}, nil)
+			},
+			setupMocks:     func(teamRepo *mocks.MockTeamRepository) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "activity change of an existing user",
+			caller: "user-1",
+			requestBody: omodels.PostTeamAddJSONRequestBody{
+				TeamName: "team-1",
+				Members: []omodels.TeamMember{
+					{UserId: "user-2", Username: "user2", IsActive: false},
+				},
+			},
+			setupUsers: func(userRepo *mocks.MockUserRepository) {
+				userRepo.On("GetUserByID", mock.Anything, "user-2").Return(&models.User{UserID: "user-2", UserName: "user2", IsActive: true}, nil)
+			},
+			setupMocks:     func(teamRepo *mocks.MockTeamRepository) {},
+			expectedStatus: http.StatusBadRequest,
+		},
 	}
 
 	for _, tt := range tests {
@@ -119,15 +346,24 @@ func TestTeamHandler_PostTeamAdd(t *testing.T) {
 			// Setup
 			e := echo.New()
 			teamRepo := new(mocks.MockTeamRepository)
-			teamService := service.NewTeamService(teamRepo)
+			userRepo := new(mocks.MockUserRepository)
+			teamService := service.NewTeamService(teamRepo, userRepo, nil)
 			handler := web.NewTeamHandler(teamService)
 
 			tt.setupMocks(teamRepo)
+			if tt.setupUsers != nil {
+				tt.setupUsers(userRepo)
+			}
+			// остальные участники — новые пользователи
+			userRepo.On("GetUserByID", mock.Anything, mock.Anything).Return(nil, errs.ErrUserNotFound).Maybe()
 
 			// Create request
 			bodyBytes, _ := json.Marshal(tt.requestBody)
 			req := httptest.NewRequest(http.MethodPost, "/team/add", bytes.NewReader(bodyBytes))
 			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			if tt.caller != "" {
+				req = req.WithContext(auth.WithCaller(req.Context(), tt.caller))
+			}
 			rec := httptest.NewRecorder()
 			c := e.NewContext(req, rec)
 
@@ -143,16 +379,17 @@ func TestTeamHandler_PostTeamAdd(t *testing.T) {
 			}
 
 			teamRepo.AssertExpectations(t)
+			userRepo.AssertExpectations(t)
 		})
 	}
 }
 
 func TestTeamHandler_GetTeamGet(t *testing.T) {
 	tests := []struct {
-		name           string
-		teamName       string
-		setupMocks     func(*mocks.MockTeamRepository)
-		expectedStatus int
+		name             string
+		teamName         string
+		setupMocks       func(*mocks.MockTeamRepository)
+		expectedStatus   int
 		validateResponse func(*testing.T, *httptest.ResponseRecorder)
 	}{
 		{
@@ -210,7 +447,7 @@ func TestTeamHandler_GetTeamGet(t *testing.T) {
 			// Setup
 			e := echo.New()
 			teamRepo := new(mocks.MockTeamRepository)
-			teamService := service.NewTeamService(teamRepo)
+			teamService := service.NewTeamService(teamRepo, new(mocks.MockUserRepository), nil)
 			handler := web.NewTeamHandler(teamService)
 
 			tt.setupMocks(teamRepo)
@@ -243,23 +480,184 @@ func TestTeamHandler_GetTeamGet(t *testing.T) {
 	}
 }
 
+func TestTeamHandler_GetTeamList(t *testing.T) {
+	archived := false
+	createdAt := time.Date(2025, 10, 24, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name             string
+		params           omodels.GetTeamListParams
+		setupMocks       func(*mocks.MockTeamRepository)
+		expectedStatus   int
+		validateResponse func(*testing.T, *httptest.ResponseRecorder)
+	}{
+		{
+			name: "first page with counters and next cursor",
+			params: omodels.GetTeamListParams{
+				Search: strPtr("end"),
+				Limit:  intPtr(1),
+			},
+			setupMocks: func(teamRepo *mocks.MockTeamRepository) {
+				teams := []*models.TeamSummary{
+					{TeamName: "backend", ParentTeam: strPtr("engineering"), IsActive: true, CreatedAt: createdAt,
+						MemberCount: 5, ActiveMemberCount: 4, OpenPullRequests: 3},
+					{TeamName: "frontend", IsActive: true, CreatedAt: createdAt, MemberCount: 2, ActiveMemberCount: 2},
+				}
+				teamRepo.On("ListTeams", mock.Anything, mock.MatchedBy(func(f models.TeamListFilter) bool {
+					return f.Search == "end" && f.IsActive == nil && f.Limit == 2 && f.After == nil
+				})).Return(teams, nil)
+			},
+			expectedStatus: http.StatusOK,
+			validateResponse: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				var response omodels.TeamList
+				err := json.Unmarshal(rec.Body.Bytes(), &response)
+				assert.NoError(t, err)
+				assert.Len(t, response.Teams, 1)
+				assert.Equal(t, "backend", response.Teams[0].TeamName)
+				assert.Equal(t, "engineering", *response.Teams[0].ParentTeam)
+				assert.Equal(t, 5, response.Teams[0].MemberCount)
+				assert.Equal(t, 4, response.Teams[0].ActiveMemberCount)
+				assert.Equal(t, 3, response.Teams[0].OpenPullRequests)
+				assert.True(t, createdAt.Equal(response.Teams[0].CreatedAt))
+				assert.NotNil(t, response.NextCursor)
+			},
+		},
+		{
+			name: "archived teams only, last page",
+			params: omodels.GetTeamListParams{
+				IsActive: &archived,
+			},
+			setupMocks: func(teamRepo *mocks.MockTeamRepository) {
+				teams := []*models.TeamSummary{
+					{TeamName: "legacy", IsActive: false, CreatedAt: createdAt, MemberCount: 1},
+				}
+				teamRepo.On("ListTeams", mock.Anything, mock.MatchedBy(func(f models.TeamListFilter) bool {
+					return f.IsActive != nil && !*f.IsActive && f.Limit == service.DefaultPageLimit+1
+				})).Return(teams, nil)
+			},
+			expectedStatus: http.StatusOK,
+			validateResponse: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				var response omodels.TeamList
+				err := json.Unmarshal(rec.Body.Bytes(), &response)
+				assert.NoError(t, err)
+				assert.Len(t, response.Teams, 1)
+				assert.False(t, response.Teams[0].IsActive)
+				assert.Nil(t, response.NextCursor)
+			},
+		},
+		{
+			name: "empty result",
+			params: omodels.GetTeamListParams{
+				Search: strPtr("nothing"),
+			},
+			setupMocks: func(teamRepo *mocks.MockTeamRepository) {
+				teamRepo.On("ListTeams", mock.Anything, mock.Anything).Return([]*models.TeamSummary{}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			validateResponse: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.JSONEq(t, `{"teams":[]}`, rec.Body.String())
+			},
+		},
+		{
+			name: "non-positive limit",
+			params: omodels.GetTeamListParams{
+				Limit: intPtr(-1),
+			},
+			setupMocks:     func(teamRepo *mocks.MockTeamRepository) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "malformed cursor",
+			params: omodels.GetTeamListParams{
+				Cursor: strPtr("%%%"),
+			},
+			setupMocks:     func(teamRepo *mocks.MockTeamRepository) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			teamRepo := new(mocks.MockTeamRepository)
+			teamService := service.NewTeamService(teamRepo, new(mocks.MockUserRepository), nil)
+			handler := web.NewTeamHandler(teamService)
+
+			tt.setupMocks(teamRepo)
+
+			req := httptest.NewRequest(http.MethodGet, "/team/list", nil)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			err := handler.GetTeamList(c, tt.params)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+
+			if tt.validateResponse != nil {
+				tt.validateResponse(t, rec)
+			}
+
+			teamRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestTeamHandler_GetTeamList_CursorRoundTrip(t *testing.T) {
+	e := echo.New()
+	teamRepo := new(mocks.MockTeamRepository)
+	teamService := service.NewTeamService(teamRepo, new(mocks.MockUserRepository), nil)
+	handler := web.NewTeamHandler(teamService)
+
+	teamRepo.On("ListTeams", mock.Anything, mock.MatchedBy(func(f models.TeamListFilter) bool {
+		return f.After == nil
+	})).Return([]*models.TeamSummary{{TeamName: "alpha"}, {TeamName: "beta"}}, nil).Once()
+	teamRepo.On("ListTeams", mock.Anything, mock.MatchedBy(func(f models.TeamListFilter) bool {
+		return f.After != nil && f.After.ID == "alpha"
+	})).Return([]*models.TeamSummary{{TeamName: "beta"}}, nil).Once()
+
+	rec := httptest.NewRecorder()
+	c := e.NewContext(httptest.NewRequest(http.MethodGet, "/team/list", nil), rec)
+	assert.NoError(t, handler.GetTeamList(c, omodels.GetTeamListParams{Limit: intPtr(1)}))
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var page omodels.TeamList
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &page))
+	assert.NotNil(t, page.NextCursor)
+
+	rec = httptest.NewRecorder()
+	c = e.NewContext(httptest.NewRequest(http.MethodGet, "/team/list", nil), rec)
+	assert.NoError(t, handler.GetTeamList(c, omodels.GetTeamListParams{Limit: intPtr(1), Cursor: page.NextCursor}))
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	page = omodels.TeamList{}
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &page))
+	assert.Len(t, page.Teams, 1)
+	assert.Equal(t, "beta", page.Teams[0].TeamName)
+	assert.Nil(t, page.NextCursor)
+
+	teamRepo.AssertExpectations(t)
+}
+
 func TestTeamHandler_PostTeamDeactivate(t *testing.T) {
 	tests := []struct {
-		name           string
-		requestBody    interface{}
-		setupMocks     func(*mocks.MockTeamRepository)
-		expectedStatus int
+		name             string
+		anonymous        bool
+		requestBody      interface{}
+		setupMocks       func(*mocks.MockTeamRepository)
+		expectedStatus   int
 		validateResponse func(*testing.T, *httptest.ResponseRecorder)
 	}{
 		{
 			name: "successful deactivation",
 			requestBody: omodels.PostTeamDeactivateJSONRequestBody{
 				TeamName: "team-1",
-				UserIds: &[]string{"user-1", "user-2"},
+				UserIds:  &[]string{"user-1", "user-2"},
 			},
 			setupMocks: func(teamRepo *mocks.MockTeamRepository) {
 				deactivated := []string{"user-1", "user-2"}
-				teamRepo.On("DeactivateUsersAndReassignPRs", mock.Anything, "team-1", []string{"user-1", "user-2"}).Return(deactivated, nil)
+				teamRepo.On("GetMemberRole", mock.Anything, "team-1", "lead-1").Return(models.TeamRoleLead, nil)
+				teamRepo.On("DeactivateUsersAndReassignPRs", mock.Anything, "team-1", []string{"user-1", "user-2"}).Return(deactivated, 0, nil)
 			},
 			expectedStatus: http.StatusOK,
 			validateResponse: func(t *testing.T, rec *httptest.ResponseRecorder) {
@@ -279,7 +677,8 @@ func TestTeamHandler_PostTeamDeactivate(t *testing.T) {
 			},
 			setupMocks: func(teamRepo *mocks.MockTeamRepository) {
 				deactivated := []string{"user-1", "user-2", "user-3"}
-				teamRepo.On("DeactivateUsersAndReassignPRs", mock.Anything, "team-1", []string(nil)).Return(deactivated, nil)
+				teamRepo.On("GetMemberRole", mock.Anything, "team-1", "lead-1").Return(models.TeamRoleLead, nil)
+				teamRepo.On("DeactivateUsersAndReassignPRs", mock.Anything, "team-1", []string(nil)).Return(deactivated, 0, nil)
 			},
 			expectedStatus: http.StatusOK,
 			validateResponse: func(t *testing.T, rec *httptest.ResponseRecorder) {
@@ -317,10 +716,43 @@ func TestTeamHandler_PostTeamDeactivate(t *testing.T) {
 				UserIds:  &[]string{"user-1"},
 			},
 			setupMocks: func(teamRepo *mocks.MockTeamRepository) {
-				teamRepo.On("DeactivateUsersAndReassignPRs", mock.Anything, "team-999", []string{"user-1"}).Return(nil, errs.ErrTeamNotFound)
+				teamRepo.On("GetMemberRole", mock.Anything, "team-999", "lead-1").Return(models.TeamRoleLead, nil)
+				teamRepo.On("DeactivateUsersAndReassignPRs", mock.Anything, "team-999", []string{"user-1"}).Return(nil, 0, errs.ErrTeamNotFound)
 			},
 			expectedStatus: http.StatusNotFound,
 		},
+		{
+			name: "anonymous caller",
+			requestBody: omodels.PostTeamDeactivateJSONRequestBody{
+				TeamName: "team-1",
+				UserIds:  &[]string{"user-1"},
+			},
+			anonymous:      true,
+			setupMocks:     func(teamRepo *mocks.MockTeamRepository) {},
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name: "caller without required role",
+			requestBody: omodels.PostTeamDeactivateJSONRequestBody{
+				TeamName: "team-1",
+				UserIds:  &[]string{"user-1"},
+			},
+			setupMocks: func(teamRepo *mocks.MockTeamRepository) {
+				teamRepo.On("GetMemberRole", mock.Anything, "team-1", "lead-1").Return(models.TeamRoleMaintainer, nil)
+			},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name: "caller outside team",
+			requestBody: omodels.PostTeamDeactivateJSONRequestBody{
+				TeamName: "team-1",
+				UserIds:  &[]string{"user-1"},
+			},
+			setupMocks: func(teamRepo *mocks.MockTeamRepository) {
+				teamRepo.On("GetMemberRole", mock.Anything, "team-1", "lead-1").Return(models.TeamRole(""), nil)
+			},
+			expectedStatus: http.StatusForbidden,
+		},
 	}
 
 	for _, tt := range tests {
@@ -328,7 +760,7 @@ func TestTeamHandler_PostTeamDeactivate(t *testing.T) {
 			// Setup
 			e := echo.New()
 			teamRepo := new(mocks.MockTeamRepository)
-			teamService := service.NewTeamService(teamRepo)
+			teamService := service.NewTeamService(teamRepo, new(mocks.MockUserRepository), nil)
 			handler := web.NewTeamHandler(teamService)
 
 			tt.setupMocks(teamRepo)
@@ -337,6 +769,9 @@ func TestTeamHandler_PostTeamDeactivate(t *testing.T) {
 			bodyBytes, _ := json.Marshal(tt.requestBody)
 			req := httptest.NewRequest(http.MethodPost, "/team/deactivate", bytes.NewReader(bodyBytes))
 			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			if !tt.anonymous {
+				req = req.WithContext(auth.WithCaller(req.Context(), "lead-1"))
+			}
 			rec := httptest.NewRecorder()
 			c := e.NewContext(req, rec)
 
@@ -356,3 +791,309 @@ func TestTeamHandler_PostTeamDeactivate(t *testing.T) {
 	}
 }
 
+func TestTeamHandler_PostTeamArchive(t *testing.T) {
+	tests := []struct {
+		name             string
+		anonymous        bool
+		requestBody      interface{}
+		setupMocks       func(*mocks.MockTeamRepository)
+		expectedStatus   int
+		validateResponse func(*testing.T, *httptest.ResponseRecorder)
+	}{
+		{
+			name: "successful archive",
+			requestBody: omodels.PostTeamArchiveJSONRequestBody{
+				TeamName: "team-1",
+			},
+			setupMocks: func(teamRepo *mocks.MockTeamRepository) {
+				archive := &models.TeamArchive{
+					TeamName:         "team-1",
+					DeactivatedUsers: []string{"user-1", "user-2"},
+					ArchivedAt:       time.Now(),
+				}
+				teamRepo.On("GetMemberRole", mock.Anything, "team-1", "lead-1").Return(models.TeamRoleLead, nil)
+				teamRepo.On("ArchiveTeam", mock.Anything, "team-1").Return(archive, 0, nil)
+			},
+			expectedStatus: http.StatusOK,
+			validateResponse: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				var response omodels.TeamArchiveResponse
+				err := json.Unmarshal(rec.Body.Bytes(), &response)
+				assert.NoError(t, err)
+				assert.Equal(t, "team-1", response.TeamName)
+				assert.Len(t, response.DeactivatedUsers, 2)
+				assert.False(t, response.ArchivedAt.IsZero())
+			},
+		},
+		{
+			name: "invalid team name",
+			requestBody: omodels.PostTeamArchiveJSONRequestBody{
+				TeamName: "team@1",
+			},
+			setupMocks: func(teamRepo *mocks.MockTeamRepository) {
+				// Service will return ErrBadRequest
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "team not found",
+			requestBody: omodels.PostTeamArchiveJSONRequestBody{
+				TeamName: "team-999",
+			},
+			setupMocks: func(teamRepo *mocks.MockTeamRepository) {
+				teamRepo.On("GetMemberRole", mock.Anything, "team-999", "lead-1").Return(models.TeamRoleLead, nil)
+				teamRepo.On("ArchiveTeam", mock.Anything, "team-999").Return(nil, 0, errs.ErrTeamNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name: "maintainer may archive",
+			requestBody: omodels.PostTeamArchiveJSONRequestBody{
+				TeamName: "team-1",
+			},
+			setupMocks: func(teamRepo *mocks.MockTeamRepository) {
+				archive := &models.TeamArchive{TeamName: "team-1", DeactivatedUsers: []string{}, ArchivedAt: time.Now()}
+				teamRepo.On("GetMemberRole", mock.Anything, "team-1", "lead-1").Return(models.TeamRoleMaintainer, nil)
+				teamRepo.On("ArchiveTeam", mock.Anything, "team-1").Return(archive, 0, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "anonymous caller",
+			requestBody: omodels.PostTeamArchiveJSONRequestBody{
+				TeamName: "team-1",
+			},
+			anonymous:      true,
+			setupMocks:     func(teamRepo *mocks.MockTeamRepository) {},
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name: "caller without required role",
+			requestBody: omodels.PostTeamArchiveJSONRequestBody{
+				TeamName: "team-1",
+			},
+			setupMocks: func(teamRepo *mocks.MockTeamRepository) {
+				teamRepo.On("GetMemberRole", mock.Anything, "team-1", "lead-1").Return(models.TeamRoleMember, nil)
+			},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name: "caller outside team",
+			requestBody: omodels.PostTeamArchiveJSONRequestBody{
+				TeamName: "team-1",
+			},
+			setupMocks: func(teamRepo *mocks.MockTeamRepository) {
+				teamRepo.On("GetMemberRole", mock.Anything, "team-1", "lead-1").Return(models.TeamRole(""), nil)
+			},
+			expectedStatus: http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Setup
+			e := echo.New()
+			teamRepo := new(mocks.MockTeamRepository)
+			teamService := service.NewTeamService(teamRepo, new(mocks.MockUserRepository), nil)
+			handler := web.NewTeamHandler(teamService)
+
+			tt.setupMocks(teamRepo)
+
+			// Create request
+			bodyBytes, _ := json.Marshal(tt.requestBody)
+			req := httptest.NewRequest(http.MethodPost, "/team/archive", bytes.NewReader(bodyBytes))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			if !tt.anonymous {
+				req = req.WithContext(auth.WithCaller(req.Context(), "lead-1"))
+			}
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			// Execute
+			err := handler.PostTeamArchive(c)
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+
+			if tt.validateResponse != nil {
+				tt.validateResponse(t, rec)
+			}
+
+			teamRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestTeamHandler_PostTeamDelete(t *testing.T) {
+	tests := []struct {
+		name             string
+		anonymous        bool
+		requestBody      interface{}
+		setupMocks       func(*mocks.MockTeamRepository)
+		expectedStatus   int
+		validateResponse func(*testing.T, *httptest.ResponseRecorder)
+	}{
+		{
+			name: "delete team without history",
+			requestBody: omodels.PostTeamDeleteJSONRequestBody{
+				TeamName: "team-1",
+			},
+			setupMocks: func(teamRepo *mocks.MockTeamRepository) {
+				export := &models.TeamExport{
+					Team:         &models.Team{TeamName: "team-1"},
+					PullRequests: []*models.PullRequest{},
+					Reviews:      []*models.ReviewAssignment{},
+				}
+				teamRepo.On("GetMemberRole", mock.Anything, "team-1", "lead-1").Return(models.TeamRoleLead, nil)
+				teamRepo.On("DeleteTeam", mock.Anything, "team-1", false).Return(export, 0, nil)
+			},
+			expectedStatus: http.StatusOK,
+			validateResponse: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				var response omodels.TeamDeleteResponse
+				err := json.Unmarshal(rec.Body.Bytes(), &response)
+				assert.NoError(t, err)
+				assert.Equal(t, "team-1", response.TeamName)
+				assert.Empty(t, response.Export.PullRequests)
+			},
+		},
+		{
+			name: "history without force",
+			requestBody: omodels.PostTeamDeleteJSONRequestBody{
+				TeamName: "team-1",
+			},
+			setupMocks: func(teamRepo *mocks.MockTeamRepository) {
+				teamRepo.On("GetMemberRole", mock.Anything, "team-1", "lead-1").Return(models.TeamRoleLead, nil)
+				teamRepo.On("DeleteTeam", mock.Anything, "team-1", false).Return(nil, 0, errs.ErrTeamHasHistory)
+			},
+			expectedStatus: http.StatusConflict,
+			validateResponse: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				var response omodels.ErrorResponse
+				err := json.Unmarshal(rec.Body.Bytes(), &response)
+				assert.NoError(t, err)
+				assert.Equal(t, omodels.TEAMHASHISTORY, response.Error.Code)
+			},
+		},
+		{
+			name: "forced delete returns export",
+			requestBody: omodels.PostTeamDeleteJSONRequestBody{
+				TeamName: "team-1",
+				Force:    boolPtr(true),
+			},
+			setupMocks: func(teamRepo *mocks.MockTeamRepository) {
+				export := &models.TeamExport{
+					Team: &models.Team{
+						TeamName: "team-1",
+						Members:  []models.TeamMember{{UserID: "user-1", UserName: "user1", IsActive: true}},
+					},
+					PullRequests: []*models.PullRequest{
+						{PullRequestID: "pr-1", PullRequestName: "PR", AuthorID: "user-1", Status: models.PullRequestMerged, AssignedReviewers: []string{"user-2"}},
+					},
+					Reviews: []*models.ReviewAssignment{
+						{PullRequestID: "pr-2", UserID: "user-1", AssignedAt: time.Now()},
+					},
+				}
+				teamRepo.On("GetMemberRole", mock.Anything, "team-1", "lead-1").Return(models.TeamRoleLead, nil)
+				teamRepo.On("DeleteTeam", mock.Anything, "team-1", true).Return(export, 0, nil)
+			},
+			expectedStatus: http.StatusOK,
+			validateResponse: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				var response omodels.TeamDeleteResponse
+				err := json.Unmarshal(rec.Body.Bytes(), &response)
+				assert.NoError(t, err)
+				assert.Len(t, response.Export.Team.Members, 1)
+				assert.Len(t, response.Export.PullRequests, 1)
+				assert.Len(t, response.Export.Reviews, 1)
+			},
+		},
+		{
+			name: "team not found",
+			requestBody: omodels.PostTeamDeleteJSONRequestBody{
+				TeamName: "team-999",
+			},
+			setupMocks: func(teamRepo *mocks.MockTeamRepository) {
+				teamRepo.On("GetMemberRole", mock.Anything, "team-999", "lead-1").Return(models.TeamRoleLead, nil)
+				teamRepo.On("DeleteTeam", mock.Anything, "team-999", false).Return(nil, 0, errs.ErrTeamNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name: "anonymous caller",
+			requestBody: omodels.PostTeamDeleteJSONRequestBody{
+				TeamName: "team-1",
+			},
+			anonymous:      true,
+			setupMocks:     func(teamRepo *mocks.MockTeamRepository) {},
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name: "caller without required role",
+			requestBody: omodels.PostTeamDeleteJSONRequestBody{
+				TeamName: "team-1",
+			},
+			setupMocks: func(teamRepo *mocks.MockTeamRepository) {
+				teamRepo.On("GetMemberRole", mock.Anything, "team-1", "lead-1").Return(models.TeamRoleMember, nil)
+			},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name: "caller outside team",
+			requestBody: omodels.PostTeamDeleteJSONRequestBody{
+				TeamName: "team-1",
+			},
+			setupMocks: func(teamRepo *mocks.MockTeamRepository) {
+				teamRepo.On("GetMemberRole", mock.Anything, "team-1", "lead-1").Return(models.TeamRole(""), nil)
+			},
+			expectedStatus: http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Setup
+			e := echo.New()
+			teamRepo := new(mocks.MockTeamRepository)
+			teamService := service.NewTeamService(teamRepo, new(mocks.MockUserRepository), nil)
+			handler := web.NewTeamHandler(teamService)
+
+			tt.setupMocks(teamRepo)
+
+			// Create request
+			bodyBytes, _ := json.Marshal(tt.requestBody)
+			req := httptest.NewRequest(http.MethodPost, "/team/delete", bytes.NewReader(bodyBytes))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			if !tt.anonymous {
+				req = req.WithContext(auth.WithCaller(req.Context(), "lead-1"))
+			}
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			// Execute
+			err := handler.PostTeamDelete(c)
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+
+			if tt.validateResponse != nil {
+				tt.validateResponse(t, rec)
+			}
+
+			teamRepo.AssertExpectations(t)
+		})
+	}
+}
+
+// Helper function to create bool pointer
+func boolPtr(b bool) *bool {
+	return &b
+}
+
+// Helper function to create string pointer
+func strPtr(s string) *string {
+	return &s
+}
+
+// Helper function to create role pointer
+func rolePtr(r omodels.TeamMemberRole) *omodels.TeamMemberRole {
+	return &r
+}
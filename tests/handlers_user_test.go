@@ -2,12 +2,17 @@ package tests
 
 import (
 	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/guarref/pr-service-assignment/internal/auth"
 	"github.com/guarref/pr-service-assignment/internal/errs"
 	"github.com/guarref/pr-service-assignment/internal/models"
 	"github.com/guarref/pr-service-assignment/internal/repository/mocks"
@@ -83,6 +88,35 @@ func TestUserHandler_PostUsersSetIsActive(t *testing.T) {
 				assert.False(t, response.User.IsActive)
 			},
 		},
+		{
+			name: "user with several teams",
+			requestBody: omodels.PostUsersSetIsActiveJSONRequestBody{
+				UserId:   "user-1",
+				IsActive: true,
+			},
+			setupMocks: func(userRepo *mocks.MockUserRepository) {
+				user := &models.User{
+					UserID:   "user-1",
+					UserName: "testuser",
+					TeamName: "team-1",
+					Teams:    []string{"team-1", "team-2"},
+					IsActive: true,
+				}
+				userRepo.On("SetFlagIsActive", mock.Anything, "user-1", true).Return(user, nil)
+			},
+			expectedStatus: http.StatusOK,
+			validateResponse: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				var response struct {
+					User omodels.User `json:"user"`
+				}
+				err := json.Unmarshal(rec.Body.Bytes(), &response)
+				assert.NoError(t, err)
+				assert.Equal(t, "team-1", response.User.TeamName)
+				if assert.NotNil(t, response.User.Teams) {
+					assert.Equal(t, []string{"team-1", "team-2"}, *response.User.Teams)
+				}
+			},
+		},
 		{
 			name: "invalid request body",
 			requestBody: map[string]interface{}{
@@ -122,7 +156,7 @@ func TestUserHandler_PostUsersSetIsActive(t *testing.T) {
 			// Setup
 			e := echo.New()
 			userRepo := new(mocks.MockUserRepository)
-			userService := service.NewUserService(userRepo)
+			userService := service.NewUserService(userRepo, new(mocks.MockTeamRepository), testPseudonymSecret, nil)
 			handler := web.NewUserHandler(userService)
 
 			tt.setupMocks(userRepo)
@@ -150,3 +184,450 @@ func TestUserHandler_PostUsersSetIsActive(t *testing.T) {
 	}
 }
 
+
+func TestUserHandler_GetUsersGet(t *testing.T) {
+	tests := []struct {
+		name             string
+		userID           string
+		setupMocks       func(*mocks.MockUserRepository)
+		expectedStatus   int
+		validateResponse func(*testing.T, *httptest.ResponseRecorder)
+	}{
+		{
+			name:   "successful get user",
+			userID: "user-1",
+			setupMocks: func(userRepo *mocks.MockUserRepository) {
+				user := &models.User{
+					UserID:          "user-1",
+					UserName:        "Alice",
+					TeamName:        "backend",
+					Teams:           []string{"backend", "platform"},
+					IsActive:        true,
+					OpenReviewCount: 3,
+				}
+				userRepo.On("GetUserByID", mock.Anything, "user-1").Return(user, nil)
+			},
+			expectedStatus: http.StatusOK,
+			validateResponse: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				var response struct {
+					User omodels.UserDetails `json:"user"`
+				}
+				err := json.Unmarshal(rec.Body.Bytes(), &response)
+				assert.NoError(t, err)
+				assert.Equal(t, "user-1", response.User.UserId)
+				assert.Equal(t, "backend", response.User.TeamName)
+				assert.Equal(t, []string{"backend", "platform"}, response.User.Teams)
+				assert.Equal(t, 3, response.User.OpenReviewCount)
+			},
+		},
+		{
+			name:           "empty user_id",
+			userID:         "",
+			setupMocks:     func(userRepo *mocks.MockUserRepository) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "user not found",
+			userID: "user-999",
+			setupMocks: func(userRepo *mocks.MockUserRepository) {
+				userRepo.On("GetUserByID", mock.Anything, "user-999").Return(nil, errs.ErrUserNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			userRepo := new(mocks.MockUserRepository)
+			userService := service.NewUserService(userRepo, new(mocks.MockTeamRepository), testPseudonymSecret, nil)
+			handler := web.NewUserHandler(userService)
+
+			tt.setupMocks(userRepo)
+
+			req := httptest.NewRequest(http.MethodGet, "/users/get?user_id="+tt.userID, nil)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			err := handler.GetUsersGet(c, omodels.GetUsersGetParams{UserId: tt.userID})
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+
+			if tt.validateResponse != nil {
+				tt.validateResponse(t, rec)
+			}
+
+			userRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestUserHandler_GetUsersList(t *testing.T) {
+	sortName := omodels.GetUsersListParamsSortUsername
+	sortBad := omodels.GetUsersListParamsSort("email")
+	orderDesc := omodels.Desc
+	active := true
+
+	tests := []struct {
+		name             string
+		params           omodels.GetUsersListParams
+		setupMocks       func(*mocks.MockUserRepository)
+		expectedStatus   int
+		validateResponse func(*testing.T, *httptest.ResponseRecorder)
+	}{
+		{
+			name: "first page with next cursor",
+			params: omodels.GetUsersListParams{
+				Team:       strPtr("backend"),
+				IsActive:   &active,
+				NamePrefix: strPtr("a"),
+				Sort:       &sortName,
+				Limit:      intPtr(2),
+			},
+			setupMocks: func(userRepo *mocks.MockUserRepository) {
+				users := []*models.User{
+					{UserID: "u1", UserName: "Alice", TeamName: "backend", IsActive: true},
+					{UserID: "u2", UserName: "Anna", TeamName: "backend", IsActive: true},
+					{UserID: "u3", UserName: "Arthur", TeamName: "backend", IsActive: true},
+				}
+				userRepo.On("ListUsers", mock.Anything, mock.MatchedBy(func(f models.UserListFilter) bool {
+					return f.TeamName == "backend" && f.IsActive != nil && *f.IsActive &&
+						f.NamePrefix == "a" && f.Sort == models.UserSortUserName && !f.Desc &&
+						f.Limit == 3 && f.After == nil
+				})).Return(users, nil)
+			},
+			expectedStatus: http.StatusOK,
+			validateResponse: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				var response omodels.UserList
+				err := json.Unmarshal(rec.Body.Bytes(), &response)
+				assert.NoError(t, err)
+				assert.Len(t, response.Users, 2)
+				assert.Equal(t, "u2", response.Users[1].UserId)
+				assert.NotNil(t, response.NextCursor)
+				assert.Equal(t, []string{}, response.Users[0].Teams)
+			},
+		},
+		{
+			name: "last page without next cursor",
+			params: omodels.GetUsersListParams{
+				Order: &orderDesc,
+			},
+			setupMocks: func(userRepo *mocks.MockUserRepository) {
+				users := []*models.User{
+					{UserID: "u1", UserName: "Alice", TeamName: "backend", IsActive: true},
+				}
+				userRepo.On("ListUsers", mock.Anything, mock.MatchedBy(func(f models.UserListFilter) bool {
+					return f.Sort == models.UserSortUserName && f.Desc && f.Limit == service.DefaultPageLimit+1
+				})).Return(users, nil)
+			},
+			expectedStatus: http.StatusOK,
+			validateResponse: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				var response omodels.UserList
+				err := json.Unmarshal(rec.Body.Bytes(), &response)
+				assert.NoError(t, err)
+				assert.Len(t, response.Users, 1)
+				assert.Nil(t, response.NextCursor)
+			},
+		},
+		{
+			name: "unknown sort",
+			params: omodels.GetUsersListParams{
+				Sort: &sortBad,
+			},
+			setupMocks:     func(userRepo *mocks.MockUserRepository) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "non-positive limit",
+			params: omodels.GetUsersListParams{
+				Limit: intPtr(0),
+			},
+			setupMocks:     func(userRepo *mocks.MockUserRepository) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "malformed cursor",
+			params: omodels.GetUsersListParams{
+				Cursor: strPtr("not-a-cursor!"),
+			},
+			setupMocks:     func(userRepo *mocks.MockUserRepository) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			userRepo := new(mocks.MockUserRepository)
+			userService := service.NewUserService(userRepo, new(mocks.MockTeamRepository), testPseudonymSecret, nil)
+			handler := web.NewUserHandler(userService)
+
+			tt.setupMocks(userRepo)
+
+			req := httptest.NewRequest(http.MethodGet, "/users/list", nil)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			err := handler.GetUsersList(c, tt.params)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+
+			if tt.validateResponse != nil {
+				tt.validateResponse(t, rec)
+			}
+
+			userRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestUserHandler_GetUsersList_CursorRoundTrip(t *testing.T) {
+	e := echo.New()
+	userRepo := new(mocks.MockUserRepository)
+	userService := service.NewUserService(userRepo, new(mocks.MockTeamRepository), testPseudonymSecret, nil)
+	handler := web.NewUserHandler(userService)
+
+	sortCount := omodels.GetUsersListParamsSortOpenReviewCount
+
+	first := []*models.User{
+		{UserID: "u1", UserName: "Alice", OpenReviewCount: 0},
+		{UserID: "u2", UserName: "Bob", OpenReviewCount: 4},
+	}
+	userRepo.On("ListUsers", mock.Anything, mock.MatchedBy(func(f models.UserListFilter) bool {
+		return f.After == nil
+	})).Return(first, nil).Once()
+	userRepo.On("ListUsers", mock.Anything, mock.MatchedBy(func(f models.UserListFilter) bool {
+		return f.After != nil && f.After.Key == "0" && f.After.ID == "u1" && f.Sort == models.UserSortOpenReviewCount
+	})).Return([]*models.User{first[1]}, nil).Once()
+
+	rec := httptest.NewRecorder()
+	c := e.NewContext(httptest.NewRequest(http.MethodGet, "/users/list", nil), rec)
+	assert.NoError(t, handler.GetUsersList(c, omodels.GetUsersListParams{Sort: &sortCount, Limit: intPtr(1)}))
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var page omodels.UserList
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &page))
+	assert.NotNil(t, page.NextCursor)
+
+	// курсор другой сортировки отклоняется
+	rec = httptest.NewRecorder()
+	c = e.NewContext(httptest.NewRequest(http.MethodGet, "/users/list", nil), rec)
+	assert.NoError(t, handler.GetUsersList(c, omodels.GetUsersListParams{Limit: intPtr(1), Cursor: page.NextCursor}))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec = httptest.NewRecorder()
+	c = e.NewContext(httptest.NewRequest(http.MethodGet, "/users/list", nil), rec)
+	assert.NoError(t, handler.GetUsersList(c, omodels.GetUsersListParams{Sort: &sortCount, Limit: intPtr(1), Cursor: page.NextCursor}))
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	page = omodels.UserList{}
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &page))
+	assert.Len(t, page.Users, 1)
+	assert.Nil(t, page.NextCursor)
+
+	userRepo.AssertExpectations(t)
+}
+
+func TestUserHandler_PostUsersErase(t *testing.T) {
+	isPseudonym := mock.MatchedBy(func(id string) bool {
+		return strings.HasPrefix(id, "erased-") && len(id) == len("erased-")+16
+	})
+	isSubjectHash := mock.MatchedBy(func(hash string) bool {
+		return len(hash) == sha256.Size*2
+	})
+	erasedAt := time.Date(2025, 10, 24, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name             string
+		caller           string
+		requestBody      interface{}
+		setupMocks       func(*mocks.MockUserRepository, *mocks.MockTeamRepository)
+		expectedStatus   int
+		validateResponse func(*testing.T, *httptest.ResponseRecorder)
+	}{
+		{
+			name:        "user erases themselves",
+			caller:      "user-2",
+			requestBody: omodels.PostUsersEraseJSONRequestBody{UserId: "user-2"},
+			setupMocks: func(userRepo *mocks.MockUserRepository, teamRepo *mocks.MockTeamRepository) {
+				erasure := &models.UserErasure{Pseudonym: "erased-0011223344556677", RequestedBy: "erased-0011223344556677", ReassignedReviews: 2, ErasedAt: erasedAt}
+				userRepo.On("EraseUser", mock.Anything, "user-2", isSubjectHash, isPseudonym, "user-2").Return(erasure, 0, nil)
+			},
+			expectedStatus: http.StatusOK,
+			validateResponse: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				var response struct {
+					Erasure omodels.UserErasure `json:"erasure"`
+				}
+				err := json.Unmarshal(rec.Body.Bytes(), &response)
+				assert.NoError(t, err)
+				assert.Equal(t, "erased-0011223344556677", response.Erasure.UserId)
+				assert.Equal(t, 2, response.Erasure.ReassignedReviews)
+				assert.True(t, erasedAt.Equal(response.Erasure.ErasedAt))
+			},
+		},
+		{
+			name:        "team lead erases member",
+			caller:      "lead-1",
+			requestBody: omodels.PostUsersEraseJSONRequestBody{UserId: "user-2"},
+			setupMocks: func(userRepo *mocks.MockUserRepository, teamRepo *mocks.MockTeamRepository) {
+				erasure := &models.UserErasure{Pseudonym: "erased-0011223344556677", RequestedBy: "lead-1", ErasedAt: erasedAt}
+				userRepo.On("GetUserByID", mock.Anything, "user-2").Return(&models.User{UserID: "user-2", TeamName: "backend"}, nil)
+				teamRepo.On("GetMemberRole", mock.Anything, "backend", "lead-1").Return(models.TeamRoleLead, nil)
+				userRepo.On("EraseUser", mock.Anything, "user-2", isSubjectHash, isPseudonym, "lead-1").Return(erasure, 0, nil)
+			},
+			expectedStatus: http.StatusOK,
+			validateResponse: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				var response struct {
+					Erasure omodels.UserErasure `json:"erasure"`
+				}
+				err := json.Unmarshal(rec.Body.Bytes(), &response)
+				assert.NoError(t, err)
+				assert.Equal(t, "lead-1", response.Erasure.RequestedBy)
+			},
+		},
+		{
+			name:        "repeated erase by lead is idempotent",
+			caller:      "lead-1",
+			requestBody: omodels.PostUsersEraseJSONRequestBody{UserId: "user-2"},
+			setupMocks: func(userRepo *mocks.MockUserRepository, teamRepo *mocks.MockTeamRepository) {
+				erasure := &models.UserErasure{Pseudonym: "erased-0011223344556677", RequestedBy: "lead-1", ErasedAt: erasedAt}
+				userRepo.On("GetUserByID", mock.Anything, "user-2").Return(nil, errs.ErrUserNotFound)
+				userRepo.On("GetUserErasure", mock.Anything, "user-2", isSubjectHash).Return(erasure, nil)
+				userRepo.On("GetUserByID", mock.Anything, "erased-0011223344556677").Return(&models.User{UserID: "erased-0011223344556677", TeamName: "backend"}, nil)
+				teamRepo.On("GetMemberRole", mock.Anything, "backend", "lead-1").Return(models.TeamRoleLead, nil)
+				userRepo.On("EraseUser", mock.Anything, "user-2", isSubjectHash, isPseudonym, "lead-1").Return(erasure, 0, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:        "maintainer of primary team cannot erase",
+			caller:      "user-3",
+			requestBody: omodels.PostUsersEraseJSONRequestBody{UserId: "user-2"},
+			setupMocks: func(userRepo *mocks.MockUserRepository, teamRepo *mocks.MockTeamRepository) {
+				userRepo.On("GetUserByID", mock.Anything, "user-2").Return(&models.User{UserID: "user-2", TeamName: "backend"}, nil)
+				teamRepo.On("GetMemberRole", mock.Anything, "backend", "user-3").Return(models.TeamRoleMaintainer, nil)
+			},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:        "lead only of a self-created team cannot erase",
+			caller:      "lead-9",
+			requestBody: omodels.PostUsersEraseJSONRequestBody{UserId: "user-2"},
+			setupMocks: func(userRepo *mocks.MockUserRepository, teamRepo *mocks.MockTeamRepository) {
+				// lead-9 создал команду с user-2 участником, но основная команда user-2 — backend
+				userRepo.On("GetUserByID", mock.Anything, "user-2").Return(&models.User{UserID: "user-2", TeamName: "backend", Teams: []string{"backend", "throwaway"}}, nil)
+				teamRepo.On("GetMemberRole", mock.Anything, "backend", "lead-9").Return(models.TeamRole(""), nil)
+			},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:        "other user that was never erased",
+			caller:      "lead-1",
+			requestBody: omodels.PostUsersEraseJSONRequestBody{UserId: "user-404"},
+			setupMocks: func(userRepo *mocks.MockUserRepository, teamRepo *mocks.MockTeamRepository) {
+				userRepo.On("GetUserByID", mock.Anything, "user-404").Return(nil, errs.ErrUserNotFound)
+				userRepo.On("GetUserErasure", mock.Anything, "user-404", isSubjectHash).Return(nil, errs.ErrNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "anonymous caller",
+			requestBody:    omodels.PostUsersEraseJSONRequestBody{UserId: "user-2"},
+			setupMocks:     func(userRepo *mocks.MockUserRepository, teamRepo *mocks.MockTeamRepository) {},
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "empty user_id",
+			caller:         "lead-1",
+			requestBody:    omodels.PostUsersEraseJSONRequestBody{},
+			setupMocks:     func(userRepo *mocks.MockUserRepository, teamRepo *mocks.MockTeamRepository) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:        "user not found",
+			caller:      "user-999",
+			requestBody: omodels.PostUsersEraseJSONRequestBody{UserId: "user-999"},
+			setupMocks: func(userRepo *mocks.MockUserRepository, teamRepo *mocks.MockTeamRepository) {
+				userRepo.On("EraseUser", mock.Anything, "user-999", isSubjectHash, isPseudonym, "user-999").Return(nil, 0, errs.ErrUserNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			userRepo := new(mocks.MockUserRepository)
+			teamRepo := new(mocks.MockTeamRepository)
+			userService := service.NewUserService(userRepo, teamRepo, testPseudonymSecret, nil)
+			handler := web.NewUserHandler(userService)
+
+			tt.setupMocks(userRepo, teamRepo)
+
+			bodyBytes, _ := json.Marshal(tt.requestBody)
+			req := httptest.NewRequest(http.MethodPost, "/users/erase", bytes.NewReader(bodyBytes))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			if tt.caller != "" {
+				req = req.WithContext(auth.WithCaller(req.Context(), tt.caller))
+			}
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			err := handler.PostUsersErase(c)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+
+			if tt.validateResponse != nil {
+				tt.validateResponse(t, rec)
+			}
+
+			userRepo.AssertExpectations(t)
+			teamRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestUserHandler_PostUsersErase_KeyedSubjectHash(t *testing.T) {
+	userRepo := new(mocks.MockUserRepository)
+	userService := service.NewUserService(userRepo, new(mocks.MockTeamRepository), testPseudonymSecret, nil)
+
+	var subjects, pseudonyms []string
+	userRepo.On("EraseUser", mock.Anything, "user-2", mock.Anything, mock.Anything, "user-2").
+		Run(func(args mock.Arguments) {
+			subjects = append(subjects, args.String(2))
+			pseudonyms = append(pseudonyms, args.String(3))
+		}).
+		Return(&models.UserErasure{}, 0, nil).Twice()
+
+	ctx := auth.WithCaller(context.Background(), "user-2")
+	_, err := userService.EraseUser(ctx, "user-2")
+	assert.NoError(t, err)
+	_, err = userService.EraseUser(ctx, "user-2")
+	assert.NoError(t, err)
+
+	// повторный запрос находит прежнее удаление по ключевому хешу, сам псевдоним случайный
+	assert.Len(t, subjects, 2)
+	assert.Equal(t, subjects[0], subjects[1])
+	unkeyed := sha256.Sum256([]byte("user-2"))
+	assert.NotEqual(t, hex.EncodeToString(unkeyed[:]), subjects[0])
+	assert.NotEqual(t, pseudonyms[0], pseudonyms[1])
+	assert.NotContains(t, pseudonyms[0], "user-2")
+
+	otherRepo := new(mocks.MockUserRepository)
+	var otherSubject string
+	otherRepo.On("EraseUser", mock.Anything, "user-2", mock.Anything, mock.Anything, "user-2").
+		Run(func(args mock.Arguments) { otherSubject = args.String(2) }).
+		Return(&models.UserErasure{}, 0, nil)
+
+	_, err = service.NewUserService(otherRepo, new(mocks.MockTeamRepository), "another-secret", nil).EraseUser(ctx, "user-2")
+	assert.NoError(t, err)
+	assert.NotEqual(t, subjects[0], otherSubject)
+
+	userRepo.AssertExpectations(t)
+	otherRepo.AssertExpectations(t)
+}
+
+const testPseudonymSecret = "test-pseudonym-secret"